@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-investing/internal/corpaction"
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+const usage = `usage:
+  corporate_actions add split <종목코드> <권리락일 YYYY-MM-DD> <1주당 새 주식 수> [메모]   (5:1 분할=5, 10:1 병합=0.1)
+  corporate_actions add bonus <종목코드> <권리락일> <1주당 배정 주식 수> [메모]           (무상증자 0.5주 배정=0.5)
+  corporate_actions add remap <종목코드> <적용일> <새 종목코드> [1주당 새 주식 수] [메모]
+  corporate_actions apply      적용일이 지난 미적용 이벤트 반영
+  corporate_actions list
+  corporate_actions log <id>`
+
+func main() {
+	logger.Init()
+
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	store, err := storage.NewSQLiteStore("stock_investing.db")
+	if err != nil {
+		logger.Error.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Migrate(); err != nil {
+		logger.Error.Fatalf("failed to migrate sqlite: %v", err)
+	}
+
+	actions := storage.NewCorporateActionRepository(store)
+	svc := corpaction.NewService(actions, storage.NewRepository(store))
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "add":
+		a, err := parseAction(args)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		if err := svc.Add(ctx, a); err != nil {
+			logger.Error.Fatalf("add: %v", err)
+		}
+		fmt.Printf("added %s %s ex=%s ratio=%g %s\n", a.Kind, a.Code, a.ExDate.Format("2006-01-02"), a.Ratio, a.NewCode)
+
+	case "apply":
+		n, err := svc.ApplyPending(ctx, time.Now())
+		if err != nil {
+			logger.Error.Fatalf("apply: %v", err)
+		}
+		fmt.Printf("%d corporate actions applied\n", n)
+
+	case "list":
+		list, err := actions.ListActions(ctx, 100)
+		if err != nil {
+			logger.Error.Fatalf("list: %v", err)
+		}
+		for _, a := range list {
+			applied := "pending"
+			if !a.AppliedAt.IsZero() {
+				applied = "applied " + a.AppliedAt.In(models.KST).Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%4d %-6s %s ex=%s ratio=%-8g %-6s %s %s\n",
+				a.ID, a.Kind, a.Code, a.ExDate.Format("2006-01-02"), a.Ratio, a.NewCode, applied, a.Note)
+		}
+
+	case "log":
+		if len(args) < 1 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid id %q\n", args[0])
+			os.Exit(1)
+		}
+		entries, err := actions.ListActionLog(ctx, id)
+		if err != nil {
+			logger.Error.Fatalf("log: %v", err)
+		}
+		for _, l := range entries {
+			fmt.Printf("%-8s %-18s %s: %s -> %s\n", l.Item, l.Strategy, l.Code, l.Before, l.After)
+		}
+
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+}
+
+// parseAction add 인자: <kind> <code> <date> <ratio|new_code> ...
+func parseAction(args []string) (*models.CorporateAction, error) {
+	if len(args) < 4 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	ex, err := storage.ParseDate(args[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", args[2])
+	}
+	a := &models.CorporateAction{Kind: args[0], Code: args[1], ExDate: ex}
+	rest := args[3:]
+	if a.Kind == models.CorpRemap {
+		a.NewCode, a.Ratio, rest = rest[0], 1, rest[1:]
+		if len(rest) > 0 {
+			if r, err := strconv.ParseFloat(rest[0], 64); err == nil {
+				a.Ratio, rest = r, rest[1:]
+			}
+		}
+	} else {
+		if a.Ratio, err = strconv.ParseFloat(rest[0], 64); err != nil {
+			return nil, fmt.Errorf("invalid ratio %q", rest[0])
+		}
+		rest = rest[1:]
+	}
+	a.Note = strings.Join(rest, " ")
+	return a, nil
+}
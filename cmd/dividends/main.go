@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"stock-investing/internal/dividend"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+const usage = `usage:
+  dividends import <배당일정.csv>   (code,ex_date,pay_date,per_share)
+  dividends post                    지급일이 지난 배당을 현금 원장에 반영
+  dividends list [전략]
+  dividends ledger [전략]`
+
+func main() {
+	logger.Init()
+
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	store, err := storage.NewSQLiteStore("stock_investing.db")
+	if err != nil {
+		logger.Error.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Migrate(); err != nil {
+		logger.Error.Fatalf("failed to migrate sqlite: %v", err)
+	}
+
+	divRepo := storage.NewDividendRepository(store)
+	svc := dividend.NewService(divRepo, storage.NewRepository(store), taxRate())
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "import":
+		if len(args) < 1 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			logger.Error.Fatalf("open %s: %v", args[0], err)
+		}
+		events, err := dividend.ParseCSV(f)
+		f.Close()
+		if err != nil {
+			logger.Error.Fatalf("parse %s: %v", args[0], err)
+		}
+		var recorded int
+		for _, ev := range events {
+			divs, err := svc.Record(ctx, ev, args[0])
+			if err != nil {
+				logger.Error.Fatalf("record %s %s: %v", ev.Code, ev.ExDate.Format("2006-01-02"), err)
+			}
+			recorded += len(divs)
+		}
+		fmt.Printf("%d events, %d holdings recorded\n", len(events), recorded)
+
+	case "post":
+		n, err := svc.PostPaid(ctx, time.Now())
+		if err != nil {
+			logger.Error.Fatalf("post: %v", err)
+		}
+		fmt.Printf("%d dividends posted\n", n)
+
+	case "list":
+		divs, err := divRepo.ListDividends(ctx, optional(args), 100)
+		if err != nil {
+			logger.Error.Fatalf("list: %v", err)
+		}
+		for _, d := range divs {
+			fmt.Printf("%-18s %s ex=%s pay=%s %6d x %10.2f gross=%12.0f tax=%10.0f net=%12.0f %s\n",
+				d.Strategy, d.Code, d.ExDate.Format("2006-01-02"), d.PayDate.Format("2006-01-02"),
+				d.Quantity, d.PerShare, d.Gross, d.Tax, d.Net, status(d.PostedAt, d.ReinvestedAt))
+		}
+
+	case "ledger":
+		entries, err := storage.NewLedgerRepository(store).ListLedger(ctx, optional(args), 100)
+		if err != nil {
+			logger.Error.Fatalf("ledger: %v", err)
+		}
+		var total float64
+		for _, e := range entries {
+			total += e.Amount
+			fmt.Printf("%s %-18s %-10s %s %12.0f %s\n", e.Date.Format("2006-01-02"), e.Strategy, e.Kind, e.Code, e.Amount, e.Note)
+		}
+		fmt.Printf("total %.0f KRW (%d entries)\n", total, len(entries))
+
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+}
+
+func optional(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return ""
+}
+
+func status(posted, reinvested time.Time) string {
+	switch {
+	case !reinvested.IsZero():
+		return "reinvested"
+	case !posted.IsZero():
+		return "posted"
+	default:
+		return "pending"
+	}
+}
+
+// taxRate DIVIDEND_TAX_RATE (기본 15.4%)
+func taxRate() float64 {
+	if v, err := strconv.ParseFloat(os.Getenv("DIVIDEND_TAX_RATE"), 64); err == nil {
+		return v
+	}
+	return 0.154
+}
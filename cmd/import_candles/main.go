@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"stock-investing/internal/marketdata"
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+func main() {
+	code := flag.String("code", "", "종목코드 (비우면 CSV 의 종목코드 컬럼 또는 파일명)")
+	dateFlag := flag.String("date", "", "날짜 컬럼이 없는 KRX 전종목 시세 파일의 기준일 (YYYY-MM-DD)")
+	maxGap := flag.Int("max-gap", 5, "경고할 최대 영업일 공백")
+	validateOnly := flag.Bool("validate-only", false, "검증만 하고 저장하지 않음")
+	dbPath := flag.String("db", "stock_investing.db", "SQLite 경로")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: import_candles [flags] <file.csv|file.zip>...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	logger.Init()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	opt := marketdata.ImportOptions{Code: *code, MaxGapDays: *maxGap}
+	if *dateFlag != "" {
+		d, err := time.ParseInLocation("2006-01-02", *dateFlag, models.KST)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid date: %s\n", *dateFlag)
+			os.Exit(1)
+		}
+		opt.Date = d
+	}
+
+	var repo storage.CandleRepository
+	if !*validateOnly {
+		store, err := storage.NewSQLiteStore(*dbPath)
+		if err != nil {
+			logger.Error.Fatalf("failed to open sqlite: %v", err)
+		}
+		defer store.Close()
+		if err := store.Migrate(); err != nil {
+			logger.Error.Fatalf("failed to migrate sqlite: %v", err)
+		}
+		repo = storage.NewCandleRepository(store)
+	}
+
+	ctx := context.Background()
+	failed := false
+	for _, path := range flag.Args() {
+		reports, err := marketdata.ReadCSVFiles(path, opt)
+		if err != nil {
+			logger.Error.Printf("[import] %v\n", err)
+			failed = true
+			continue
+		}
+		for _, rep := range reports {
+			printReport(rep)
+			if repo == nil {
+				continue
+			}
+			if err := marketdata.StoreImported(ctx, repo, rep); err != nil {
+				logger.Error.Printf("[import] %s: %v\n", rep.Source, err)
+				failed = true
+				continue
+			}
+			logger.Info.Printf("[import] %s: stored %d candles\n", rep.Source, len(rep.Candles))
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printReport(rep *marketdata.ImportReport) {
+	fmt.Printf("%s: layout=%s rows=%d candles=%d duplicates=%d non-positive=%d gaps=%d\n",
+		rep.Source, rep.Layout, rep.Rows, len(rep.Candles), len(rep.Duplicates), len(rep.NonPositive), len(rep.Gaps))
+	for _, d := range rep.Duplicates {
+		fmt.Printf("  duplicate    %s\n", d)
+	}
+	for _, n := range rep.NonPositive {
+		fmt.Printf("  non-positive %s\n", n)
+	}
+	for _, g := range rep.Gaps {
+		fmt.Printf("  gap          %s\n", g)
+	}
+	if rep.HasGaps() {
+		fmt.Println("  (gaps found: candles stored, cache coverage left unchanged so missing days are fetched from KIS)")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/position"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+const usage = `usage:
+  positions show [전략]      보유 포지션 (체결 반영 후)
+  positions closed [전략]    청산된 포지션과 실현 손익
+  positions rebuild          전체 체결 기록으로 포지션을 다시 만든다`
+
+func main() {
+	logger.Init()
+
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	store, err := storage.NewSQLiteStore("stock_investing.db")
+	if err != nil {
+		logger.Error.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Migrate(); err != nil {
+		logger.Error.Fatalf("failed to migrate sqlite: %v", err)
+	}
+
+	repo := storage.NewPositionRepository(store)
+	svc := position.NewService(repo, storage.NewRepository(store))
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	strategy := ""
+	if len(os.Args) > 2 {
+		strategy = os.Args[2]
+	}
+	switch os.Args[1] {
+	case "show":
+		list, err := svc.Positions(ctx, strategy)
+		if err != nil {
+			logger.Error.Fatalf("positions: %v", err)
+		}
+		for _, p := range list {
+			fmt.Printf("%-18s %s %8d @ %12.2f realized %12.0f since %s\n",
+				p.Strategy, p.Code, p.Quantity, p.AvgPrice, p.Realized, p.OpenedAt.In(models.KST).Format("2006-01-02"))
+		}
+
+	case "closed":
+		if _, err := svc.Sync(ctx); err != nil {
+			logger.Error.Fatalf("sync: %v", err)
+		}
+		list, err := repo.ListClosedPositions(ctx, strategy, 100)
+		if err != nil {
+			logger.Error.Fatalf("closed: %v", err)
+		}
+		var total float64
+		for _, c := range list {
+			total += c.Realized
+			fmt.Printf("%-18s %s %s ~ %s realized %12.0f\n", c.Strategy, c.Code,
+				c.OpenedAt.In(models.KST).Format("2006-01-02"), c.ClosedAt.In(models.KST).Format("2006-01-02"), c.Realized)
+		}
+		fmt.Printf("total realized %.0f KRW (%d positions)\n", total, len(list))
+
+	case "rebuild":
+		n, err := svc.Rebuild(ctx)
+		if err != nil {
+			logger.Error.Fatalf("rebuild: %v", err)
+		}
+		fmt.Printf("%d trades applied\n", n)
+
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+}
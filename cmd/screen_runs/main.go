@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+const usage = `usage:
+  screen_runs list [스크리너명] [최근 일수=7]
+  screen_runs show <run_id>
+  screen_runs diff <from_run_id> <to_run_id>
+  screen_runs diff-days <스크리너명> <YYYY-MM-DD> <YYYY-MM-DD>
+  screen_runs why <종목코드> [건수=10]`
+
+func main() {
+	logger.Init()
+
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	store, err := storage.NewSQLiteStore("stock_investing.db")
+	if err != nil {
+		logger.Error.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Migrate(); err != nil {
+		logger.Error.Fatalf("failed to migrate sqlite: %v", err)
+	}
+
+	runs := storage.NewScreenRunRepository(store)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "list":
+		name := ""
+		days := 7
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if len(args) > 1 {
+			days = mustInt(args[1])
+		}
+		now := time.Now()
+		list, err := runs.ListScreenRuns(ctx, name, now.AddDate(0, 0, -days), now.Add(time.Minute))
+		if err != nil {
+			logger.Error.Fatalf("list: %v", err)
+		}
+		for _, r := range list {
+			fmt.Printf("#%-5d %s  %-20s %s\n", r.ID, r.StartedAt.Local().Format("2006-01-02 15:04"), r.Name, r.Params)
+		}
+
+	case "show":
+		if len(args) < 1 {
+			exitUsage()
+		}
+		run, err := runs.GetScreenRun(ctx, int64(mustInt(args[0])))
+		if err != nil {
+			logger.Error.Fatalf("show: %v", err)
+		}
+		fmt.Printf("#%d %s %s\nparams: %s\n", run.ID, run.Name, run.StartedAt.Local().Format(time.RFC3339), run.Params)
+		for _, c := range run.Candidates {
+			printCandidate("", c)
+		}
+
+	case "diff":
+		if len(args) < 2 {
+			exitUsage()
+		}
+		d, err := runs.CompareScreenRuns(ctx, int64(mustInt(args[0])), int64(mustInt(args[1])))
+		if err != nil {
+			logger.Error.Fatalf("diff: %v", err)
+		}
+		printDiff(d)
+
+	case "diff-days":
+		if len(args) < 3 {
+			exitUsage()
+		}
+		from := latestOn(ctx, runs, args[0], args[1])
+		to := latestOn(ctx, runs, args[0], args[2])
+		printDiff(storage.DiffScreenRuns(from, to))
+
+	case "why":
+		if len(args) < 1 {
+			exitUsage()
+		}
+		limit := 10
+		if len(args) > 1 {
+			limit = mustInt(args[1])
+		}
+		hist, err := runs.CandidateHistory(ctx, args[0], limit)
+		if err != nil {
+			logger.Error.Fatalf("why: %v", err)
+		}
+		for _, h := range hist {
+			printCandidate(fmt.Sprintf("#%-5d %s %-20s ", h.RunID, h.StartedAt.Local().Format("2006-01-02 15:04"), h.RunName), h.ScreenCandidate)
+		}
+
+	default:
+		exitUsage()
+	}
+}
+
+// latestOn day(로컬 날짜)에 실행된 name 스크리너의 마지막 스냅샷
+func latestOn(ctx context.Context, runs storage.ScreenRunRepository, name, day string) *models.ScreenRun {
+	d, err := time.ParseInLocation("2006-01-02", day, time.Local)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid date: %s\n", day)
+		os.Exit(1)
+	}
+	run, err := runs.LatestScreenRunBefore(ctx, name, d.AddDate(0, 0, 1))
+	if err != nil {
+		logger.Error.Fatalf("load run %s %s: %v", name, day, err)
+	}
+	if run == nil || run.StartedAt.Before(d) {
+		fmt.Fprintf(os.Stderr, "no %s run on %s\n", name, day)
+		os.Exit(1)
+	}
+	return run
+}
+
+func printDiff(d *storage.ScreenRunDiff) {
+	fmt.Printf("from #%d (%s) -> to #%d (%s)\n",
+		d.From.ID, d.From.StartedAt.Local().Format("2006-01-02 15:04"),
+		d.To.ID, d.To.StartedAt.Local().Format("2006-01-02 15:04"))
+	for _, c := range d.Added {
+		printCandidate("+ ", c)
+	}
+	for _, c := range d.Removed {
+		printCandidate("- ", c)
+	}
+	for _, c := range d.Kept {
+		printCandidate("= ", c)
+	}
+}
+
+func printCandidate(prefix string, c models.ScreenCandidate) {
+	status := fmt.Sprintf("selected #%d", c.Rank)
+	if !c.Selected {
+		status = "dropped by " + c.DroppedBy
+	}
+	keys := make([]string, 0, len(c.Values))
+	for k := range c.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	vals := make([]string, len(keys))
+	for i, k := range keys {
+		vals[i] = fmt.Sprintf("%s=%.4g", k, c.Values[k])
+	}
+	fmt.Printf("%s%s %-24s score=%.3f %s\n", prefix, c.Code, status, c.Score, strings.Join(vals, " "))
+}
+
+func mustInt(s string) int {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid number: %s\n", s)
+		os.Exit(1)
+	}
+	return v
+}
+
+func exitUsage() {
+	fmt.Println(usage)
+	os.Exit(1)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+const usage = `usage:
+  screener_lists show
+  screener_lists add <black|white> <종목코드> [사유]
+  screener_lists remove <black|white> <종목코드>
+  screener_lists cooldown <종목코드> <일수> [사유]`
+
+func main() {
+	logger.Init()
+
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	store, err := storage.NewSQLiteStore("stock_investing.db")
+	if err != nil {
+		logger.Error.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Migrate(); err != nil {
+		logger.Error.Fatalf("failed to migrate sqlite: %v", err)
+	}
+
+	lists := storage.NewScreenerListRepository(store)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "show":
+		for _, list := range []string{storage.ListBlack, storage.ListWhite} {
+			codes, err := lists.ListCodes(ctx, list)
+			if err != nil {
+				logger.Error.Fatalf("list %s: %v", list, err)
+			}
+			fmt.Printf("%s: %v\n", list, codes)
+		}
+		codes, err := lists.ActiveCooldowns(ctx, time.Now())
+		if err != nil {
+			logger.Error.Fatalf("cooldowns: %v", err)
+		}
+		fmt.Printf("cooldown: %v\n", codes)
+
+	case "add", "remove":
+		if len(args) < 2 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		list, err := listName(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if os.Args[1] == "add" {
+			err = lists.AddToList(ctx, list, args[1], strings.Join(args[2:], " "))
+		} else {
+			err = lists.RemoveFromList(ctx, list, args[1])
+		}
+		if err != nil {
+			logger.Error.Fatalf("%s %s %s: %v", os.Args[1], list, args[1], err)
+		}
+		fmt.Printf("%s %s %s\n", os.Args[1], list, args[1])
+
+	case "cooldown":
+		if len(args) < 2 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		days, err := strconv.Atoi(args[1])
+		if err != nil || days <= 0 {
+			fmt.Fprintf(os.Stderr, "invalid days: %s\n", args[1])
+			os.Exit(1)
+		}
+		until := time.Now().AddDate(0, 0, days)
+		if err := lists.AddCooldown(ctx, args[0], until, strings.Join(args[2:], " ")); err != nil {
+			logger.Error.Fatalf("cooldown %s: %v", args[0], err)
+		}
+		fmt.Printf("cooldown %s until %s\n", args[0], until.Format("2006-01-02"))
+
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+}
+
+func listName(s string) (string, error) {
+	switch s {
+	case "black", storage.ListBlack:
+		return storage.ListBlack, nil
+	case "white", storage.ListWhite:
+		return storage.ListWhite, nil
+	}
+	return "", fmt.Errorf("unknown list: %s (black|white)", s)
+}
@@ -3,15 +3,24 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"stock-investing/internal/config"
+	"stock-investing/internal/corpaction"
+	"stock-investing/internal/dividend"
+	"stock-investing/internal/dryrun"
+	"stock-investing/internal/execution"
 	"stock-investing/internal/kis"
+	"stock-investing/internal/marketdata"
+	"stock-investing/internal/position"
 	"stock-investing/internal/risk"
 	"stock-investing/internal/screener"
+	"stock-investing/internal/sizing"
 	"stock-investing/internal/storage"
 	"stock-investing/internal/strategy"
 	"stock-investing/pkg/logger"
@@ -19,13 +28,27 @@ import (
 )
 
 func main() {
-	modeFlag := flag.String("mode", "hybrid", "trading mode: hybrid|stable|aggressive")
+	modeFlag := flag.String("mode", "hybrid", "strategy to run once (see --list-strategies)")
+	listStrategies := flag.Bool("list-strategies", false, "list registered strategies and their config, then exit")
+	daemon := flag.Bool("daemon", false, "keep running and start strategies on the SCHEDULE (e.g. stable=09:05,aggressive=09:10)")
 	initDB := flag.Bool("init-db", false, "initialize database")
+	updateCandles := flag.Bool("update-candles", false, "update cached daily candles up to the last close and exit")
+	dryRun := flag.Bool("dry-run", false, "run strategies on live quotes/balance but only log orders and record them in dry_run_trades")
 	flag.Parse()
 
 	// 1) 로거 초기화
 	logger.Init()
 
+	if *listStrategies {
+		for _, def := range strategy.Definitions() {
+			fmt.Printf("%s\t%s\n", def.Name, def.Description)
+			for _, f := range def.Config {
+				fmt.Printf("    %-28s default=%-12s %s\n", f.Key, f.Default, f.Description)
+			}
+		}
+		return
+	}
+
 	// 2) 환경변수 로드
 	cfg := config.Load()
 	logger.Info.Printf("config loaded, mock=%v, server=%s\n", cfg.MockTrading, cfg.KIS)
@@ -54,57 +77,322 @@ func main() {
 	}
 	defer store.Close()
 
+	// 새 테이블이 추가돼도 기존 DB 에서 바로 동작하도록 매 실행 시 스키마를 맞춘다 (IF NOT EXISTS).
+	if err := store.Migrate(); err != nil {
+		logger.Error.Fatalf("failed to migrate sqlite: %v", err)
+	}
+
 	repo := storage.NewRepository(store)
 	kisClient := kis.NewClient(
 		cfg.KIS.AppKey,
 		cfg.KIS.AppSecret,
 		cfg.KIS.BaseURL,
 		cfg.KIS.AccountNo,
+		cfg.MockTrading,
+	)
+
+	market := marketdata.NewService(
+		kisClient,
+		kisClient,
+		storage.NewCandleRepository(store),
+		time.Duration(cfg.MarketData.QuoteTTLSeconds)*time.Second,
 	)
 
+	// 장 마감 후 일봉 캐시 증분 갱신 모드
+	if *updateCandles {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		if _, err := market.UpdateEndOfDay(ctx, cfg.Stable.ETFs); err != nil {
+			logger.Error.Fatalf("failed to update candles: %v", err)
+		}
+		return
+	}
+
+	// 손실 한도 계산용 평가금액 기록. dry-run 에서는 실제 기록을 읽기만 한다.
+	var pnl storage.PnLRepository = storage.NewPnLRepository(store)
+	if *dryRun {
+		pnl = dryrun.NewPnLRepository(pnl)
+	}
 	riskMgr := risk.NewManager(risk.Config{
-		MaxRiskRatio:     cfg.Risk.MaxRisk, // .env의 MAX_RISK
-		MaxPositionRatio: 0.05,             // 종목당 5% (임시)
-		MaxThemeRatio:    0.5,              // 테마당 50% (임시)
-		MinCashRatio:     0.2,              // 현금 20% 유지 (임시)
-	})
+		MaxRiskRatio:     cfg.Risk.MaxRisk,        // .env의 MAX_RISK (고점 대비)
+		DailyLossLimit:   cfg.Risk.DailyLossLimit, // .env의 DAILY_LOSS_LIMIT (장 시작 대비)
+		MaxPositionRatio: 0.05,                    // 종목당 5% (임시)
+		MaxThemeRatio:    0.5,                     // 테마당 50% (임시)
+		MinCashRatio:     cfg.Risk.MinCashRatio,
+	}, pnl)
+
+	lists := storage.NewScreenerListRepository(store)
+
+	var scr screener.Screener = screener.NewKosdaqScreener()
+	scrName := "kosdaq"
+	// 규칙 파일은 한 번만 읽어 aggressive 스크리너와 평균 회귀 유니버스가 같이 쓴다.
+	var rules *screener.Set
+	if cfg.Screener.RulesFile != "" {
+		set, err := screener.LoadRuleFile(cfg.Screener.RulesFile, screener.Sources{
+			Candles: market,
+			Quotes:  market,
+			Lists:   lists,
+		})
+		if err != nil {
+			logger.Error.Fatalf("failed to load screener rules: %v", err)
+		}
+		rules = set
+		scrName = cfg.Screener.Name
+		if scrName == "" && len(set.Names()) > 0 {
+			scrName = set.Names()[0]
+		}
+		if scr, err = set.Get(scrName); err != nil {
+			logger.Error.Fatalf("%v", err)
+		}
+		logger.Info.Printf("screener selected: %s (from %s)\n", scrName, cfg.Screener.RulesFile)
+	}
+	// 매 스크리닝 결과를 screen_runs 에 남겨 사후 분석이 가능하게 한다.
+	scr = screener.NewRecorder(scr, scrName, storage.NewScreenRunRepository(store))
+
+	// 평균 회귀 유니버스: 규칙 파일의 스크리너 또는 고정 대형주 목록 + 유동성/상태 필터
+	var meanRevUniverse screener.Screener = screener.NewPipeline("mean_reversion_universe",
+		screener.StaticUniverse{Codes: cfg.MeanRev.Universe, Market: "KOSPI"},
+		screener.WithQuotes(market),
+		screener.StatusFilter(true),
+		screener.LiquidityFilter(cfg.MeanRev.MinTradingValue),
+	)
+	meanRevName := "mean_reversion_universe"
+	if cfg.MeanRev.Screener != "" {
+		if rules == nil {
+			log.Fatalf("MEANREV_SCREENER requires SCREENER_RULES_FILE")
+		}
+		var err error
+		if meanRevUniverse, err = rules.Get(cfg.MeanRev.Screener); err != nil {
+			logger.Error.Fatalf("%v", err)
+		}
+		meanRevName = cfg.MeanRev.Screener
+	}
+	meanRevUniverse = screener.NewRecorder(meanRevUniverse, meanRevName, storage.NewScreenRunRepository(store))
+
+	var (
+		broker    execution.Broker               = kisClient
+		orders    execution.OrderStore           = storage.NewOrderRepository(store)
+		stops     storage.PositionStopRepository = storage.NewPositionStopRepository(store)
+		state     storage.StateRepository        = storage.NewStateRepository(store)
+		carry     storage.CarryRepository        = storage.NewCarryRepository(store)
+		cooldowns strategy.CooldownStore         = lists
+	)
+	// 보유 포지션은 실계좌 체결(trades)로만 갱신된다. dry-run 가상 체결은 반영되지 않는다.
+	positions := position.NewService(storage.NewPositionRepository(store), repo)
+	divs := dividend.NewService(storage.NewDividendRepository(store), repo, cfg.Dividend.TaxRate)
+	var dividends strategy.DividendLedger = divs
+	// dry-run: 시세/잔고/기존 체결은 실제 것을 쓰고, 주문은 로그와 dry_run_trades 에만 남긴다.
+	// 전략 상태(적립 잔액, 스탑, 리밸런싱일, 쿨다운)는 메모리에서만 바뀐다.
+	if *dryRun {
+		runID := dryrun.RunID(time.Now())
+		logger.Info.Printf("DRY RUN %s: no orders will be sent\n", runID)
+		broker = dryrun.NewBroker(market)
+		repo = dryrun.NewRepository(repo, storage.NewDryRunTradeRepository(store), runID)
+		orders = nil
+		stops = dryrun.NewPositionStopRepository(stops)
+		state = dryrun.NewStateRepository(state)
+		carry = dryrun.NewCarryRepository(carry)
+		cooldowns = dryrun.Cooldowns{}
+		dividends = dryrun.NewDividendLedger(divs)
+	}
+
+	// 모든 주문은 실행 파이프라인을 거친다. stable/로테이션은 ETF 라 종목당 비중 한도를 두지 않는다.
+	exec := execution.NewExecutor(broker, market, riskMgr, repo, orders).
+		ExemptPositionCheck(string(strategy.ModeStable), string(strategy.ModeRotation)).
+		WithPositions(positions).
+		WithFillWait(time.Duration(cfg.Execution.FillTimeout)*time.Second, time.Duration(cfg.Execution.SessionWait)*time.Minute)
+	// 큰 주문은 시장가 한 번 대신 TWAP / 호가 추종 지정가로 나눠 낸다. dry-run 에서는 쓰지 않는다.
+	if cfg.Execution.Algo != "market" && !*dryRun {
+		algo, err := execution.NewAlgo(kisClient, kisClient, execution.AlgoConfig{
+			Kind:           cfg.Execution.Algo,
+			Window:         time.Duration(cfg.Execution.WindowMinutes) * time.Minute,
+			Slices:         cfg.Execution.Slices,
+			RepegInterval:  time.Duration(cfg.Execution.RepegSeconds) * time.Second,
+			SweepRemainder: cfg.Execution.Sweep,
+		})
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		exec.WithAlgo(algo, cfg.Execution.AlgoMinValue)
+		logger.Info.Printf("execution algo: %s (min value %.0f)\n", cfg.Execution.Algo, cfg.Execution.AlgoMinValue)
+	}
 
-	scr := screener.NewKosdaqScreener()
+	sizer, err := sizing.New(cfg.Aggressive.Sizer, sizing.Params{
+		Fraction:      cfg.Aggressive.PositionRatio,
+		RiskPerTrade:  cfg.Aggressive.RiskPerTrade,
+		VolTarget:     cfg.Aggressive.VolTarget,
+		KellyWinRate:  cfg.Aggressive.KellyWinRate,
+		KellyPayoff:   cfg.Aggressive.KellyPayoff,
+		KellyFraction: cfg.Aggressive.KellyFraction,
+		KellyCap:      cfg.Aggressive.KellyCap,
+	})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	meanRevSizer, err := sizing.New(cfg.MeanRev.Sizer, sizing.Params{
+		Fraction:      cfg.MeanRev.PositionRatio,
+		RiskPerTrade:  cfg.MeanRev.RiskPerTrade,
+		VolTarget:     cfg.MeanRev.VolTarget,
+		KellyWinRate:  cfg.MeanRev.KellyWinRate,
+		KellyPayoff:   cfg.MeanRev.KellyPayoff,
+		KellyFraction: cfg.MeanRev.KellyFraction,
+		KellyCap:      cfg.MeanRev.KellyCap,
+	})
+	if err != nil {
+		log.Fatalf("MEANREV_SIZER: %v", err)
+	}
 
 	deps := strategy.Deps{
-		KIS:      kisClient,
-		Risk:     riskMgr,
-		Screener: scr,
-		Repo:     repo,
+		KIS:       kisClient,
+		Market:    market,
+		Risk:      riskMgr,
+		Screener:  scr,
+		Repo:      repo,
+		Positions: positions,
+		Exec:      exec,
+		Cooldowns: cooldowns,
+		Stops:     stops,
+		State:     state,
+		Carry:     carry,
+		Dividends: dividends,
 		Stable: strategy.StableConfig{
 			ETFs:        cfg.Stable.ETFs,
+			Weights:     cfg.Stable.Weights,
 			DailyAmount: cfg.Stable.DailyAmount,
+
+			Alloc:          cfg.Stable.Alloc,
+			RebalanceDays:  cfg.Stable.RebalanceDays,
+			RebalanceDrift: cfg.Stable.RebalanceDrift,
+			DCA: strategy.DCAConfig{
+				Mode:          cfg.Stable.DCAMode,
+				VAGrowth:      cfg.Stable.VAGrowth,
+				VAMaxMultiple: cfg.Stable.VAMaxMultiple,
+				DipThreshold:  cfg.Stable.DipThreshold,
+				DipLookback:   cfg.Stable.DipLookback,
+				DipBasis:      cfg.Stable.DipBasis,
+				DipMultiplier: cfg.Stable.DipMultiplier,
+			},
+			DCASession:        cfg.Stable.DCASession,
+			ReinvestDividends: cfg.Stable.ReinvestDividends,
+		},
+		Aggressive: strategy.AggressiveConfig{
+			Alloc:         cfg.Aggressive.Alloc,
+			MaxHoldings:   cfg.Aggressive.MaxHoldings,
+			PositionRatio: cfg.Aggressive.PositionRatio,
+			Pyramiding:    cfg.Aggressive.Pyramiding,
+			Sizer:         sizer,
+			Exit: strategy.ExitRules{
+				TakeProfit:     cfg.Aggressive.TakeProfit,
+				StopLoss:       cfg.Aggressive.StopLoss,
+				MaxHoldingDays: cfg.Aggressive.MaxHoldingDays,
+				TrailingPct:    cfg.Aggressive.TrailingPct,
+				TrailingATR:    cfg.Aggressive.TrailingATR,
+				ATRPeriod:      cfg.Aggressive.ATRPeriod,
+				BreakevenAfter: cfg.Aggressive.BreakevenAfter,
+			},
+			CooldownDays: cfg.Aggressive.CooldownDays,
+		},
+		MeanReversion: strategy.MeanReversionConfig{
+			Alloc:         cfg.MeanRev.Alloc,
+			MaxHoldings:   cfg.MeanRev.MaxHoldings,
+			PositionRatio: cfg.MeanRev.PositionRatio,
+			Sizer:         meanRevSizer,
+			BBPeriod:      cfg.MeanRev.BBPeriod,
+			BBK:           cfg.MeanRev.BBK,
+			RSIPeriod:     cfg.MeanRev.RSIPeriod,
+			RSIEntry:      cfg.MeanRev.RSIEntry,
+			Exit: strategy.ExitRules{
+				StopLoss:       cfg.MeanRev.StopLoss,
+				MaxHoldingDays: cfg.MeanRev.MaxHoldingDays,
+				MeanTarget:     cfg.MeanRev.BBPeriod,
+			},
+			Universe: meanRevUniverse,
+		},
+		Rotation: strategy.RotationConfig{
+			Alloc:           cfg.Rotation.Alloc,
+			Universe:        cfg.Rotation.Universe,
+			Lookbacks:       cfg.Rotation.Lookbacks,
+			TopK:            cfg.Rotation.TopK,
+			SafeAsset:       cfg.Rotation.SafeAsset,
+			AbsThreshold:    cfg.Rotation.AbsThreshold,
+			RebalanceMonths: cfg.Rotation.RebalanceMonths,
+			Band:            cfg.Rotation.Band,
 		},
 	}
 
-	// 5) 전략 선택
-	var runner strategy.Runner
-	switch strategy.Mode(*modeFlag) {
-	case strategy.ModeHybrid:
-		runner = strategy.NewHybridStrategy(deps)
-	case strategy.ModeStable:
-		runner = strategy.NewStableStrategy(deps)
-	case strategy.ModeAggressive:
-		runner = strategy.NewAggressiveStrategy(deps)
-	default:
-		log.Fatalf("unknown mode: %s", *modeFlag)
+	// 4-1) 종료 시그널 처리 + 컨텍스트
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// 지급일이 지난 배당을 현금 원장에 반영한다 (재투자 예산은 stable 이 읽는다). dry-run 에서는 원장을 바꾸지 않는다.
+	postDividends := func(ctx context.Context) error {
+		_, err := divs.PostPaid(ctx, time.Now())
+		return err
+	}
+	// 배당락일이 된 분할/무상증자/코드 변경을 포지션·일봉·스탑에 반영한다. dry-run 에서는 반영하지 않는다.
+	corpActions := corpaction.NewService(storage.NewCorporateActionRepository(store), repo)
+	applyCorpActions := func(ctx context.Context) error {
+		_, err := corpActions.ApplyPending(ctx, time.Now())
+		return err
 	}
 
-	// 6) 스케줄러 시작 (현재는 stub)
-	s := scheduler.New()
-	s.Start()
-	defer s.Stop()
+	// 장 마감 후 평가금액을 남겨 다음 날 당일 손실의 기준으로 쓴다.
+	snapshotEquity := func(ctx context.Context) error {
+		bal, err := kisClient.GetBalance(ctx)
+		if err != nil {
+			return err
+		}
+		day, err := riskMgr.RecordEquity(ctx, bal.TotalEquity)
+		if err != nil {
+			return err
+		}
+		logger.Info.Printf("equity snapshot %.0f (today %+.0f, drawdown %.2f%%)\n", day.Equity, day.Profit, day.Drawdown*100)
+		return nil
+	}
 
-	// 7) 종료 시그널 처리 + 컨텍스트
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer cancel()
+	// 5) 상주 모드: 스케줄에 등록된 전략을 이름으로 실행
+	if *daemon {
+		if len(cfg.Schedule) == 0 {
+			log.Fatalf("--daemon requires SCHEDULE (e.g. stable=09:05,aggressive=09:10)")
+		}
+		s := scheduler.New().
+			WithGrace(time.Duration(cfg.Daemon.Grace) * time.Minute).
+			WithHolidays(cfg.Daemon.Holidays...)
+		if !*dryRun {
+			if err := s.AddDaily("corporate_actions", cfg.CorpAction.ApplyAt, applyCorpActions); err != nil {
+				log.Fatalf("%v", err)
+			}
+			if err := s.AddDaily("dividends", cfg.Dividend.PostAt, postDividends); err != nil {
+				log.Fatalf("%v", err)
+			}
+			if err := s.AddDaily("equity_snapshot", cfg.Risk.SnapshotAt, snapshotEquity); err != nil {
+				log.Fatalf("%v", err)
+			}
+		}
+		for _, e := range cfg.Schedule {
+			if err := s.AddStrategy(e.Strategy, e.At, deps); err != nil {
+				log.Fatalf("schedule %s: %v", e.Strategy, err)
+			}
+		}
+		s.Start()
+		<-ctx.Done()
+		s.Stop()
+		return
+	}
 
-	// 8) 선택된 전략 실행
+	// 6) 선택된 전략 1회 실행
+	runner, err := strategy.New(*modeFlag, deps)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if !*dryRun {
+		if err := applyCorpActions(ctx); err != nil {
+			logger.Error.Printf("failed to apply corporate actions: %v\n", err)
+		}
+		if err := postDividends(ctx); err != nil {
+			logger.Error.Printf("failed to post dividends: %v\n", err)
+		}
+	}
 	if err := runner.Run(ctx); err != nil {
 		logger.Error.Printf("strategy run error: %v\n", err)
 	}
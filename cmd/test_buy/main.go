@@ -41,11 +41,12 @@ func main() {
 		cfg.KIS.AppSecret,
 		cfg.KIS.BaseURL,
 		cfg.KIS.AccountNo,
+		cfg.MockTrading,
 	)
 
-	fmt.Printf("TRY BUY (mock): code=%s, qty=%d\n", code, qty)
+	fmt.Printf("TRY BUY (mock=%v): code=%s, qty=%d\n", cfg.MockTrading, code, qty)
 
-	if err := client.Buy(ctx, code, qty); err != nil {
+	if _, err := client.Buy(ctx, code, qty); err != nil {
 		fmt.Fprintf(os.Stderr, "Buy error: %v\n", err)
 		os.Exit(1)
 	}
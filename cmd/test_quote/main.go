@@ -32,6 +32,7 @@ func main() {
 		cfg.KIS.AppSecret,
 		cfg.KIS.BaseURL,
 		cfg.KIS.AccountNo,
+		cfg.MockTrading,
 	)
 
 	price, err := client.GetQuote(ctx, code)
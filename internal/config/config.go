@@ -2,19 +2,45 @@ package config
 
 import (
 	"log"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+
+	"stock-investing/internal/models"
 )
 
 type AppConfig struct {
 	KIS         KISConfig
 	Stable      StableConfig
 	Aggressive  AggressiveConfig
+	MeanRev     MeanReversionConfig
+	Rotation    RotationConfig
 	Risk        RiskConfig
+	Execution   ExecutionConfig
+	Dividend    DividendConfig
+	CorpAction  CorpActionConfig
+	Screener    ScreenerConfig
+	MarketData  MarketDataConfig
+	Schedule    []ScheduleEntry
+	Daemon      DaemonConfig
 	MockTrading bool
 }
 
+// DaemonConfig --daemon 스케줄 실행 조건
+type DaemonConfig struct {
+	Grace    int      // 예정 시각이 이만큼(분) 지나도록 실행하지 못한 작업은 그날 건너뛴다
+	Holidays []string // 주말 외 휴장일 (KST YYYY-MM-DD)
+}
+
+// ScheduleEntry --daemon 모드에서 매일 실행할 전략과 시각 (KST HH:MM)
+type ScheduleEntry struct {
+	Strategy string
+	At       string
+}
+
 type KISConfig struct {
 	BaseURL   string
 	AppKey    string
@@ -25,16 +51,124 @@ type KISConfig struct {
 type StableConfig struct {
 	Alloc         float64
 	ETFs          []string
+	Weights       map[string]float64 // ETF 별 목표 비중 (합 1 로 정규화)
 	DailyAmount   int64
 	RebalanceDays int
+	// RebalanceDrift 목표 비중 대비 이탈 허용치 (0.05 = 5%p)
+	RebalanceDrift float64
+
+	DCAMode       string  // fixed | value_averaging | dip
+	VAGrowth      float64 // value averaging 목표 경로 연 성장률
+	VAMaxMultiple float64 // value averaging 하루 투입 상한 (DailyAmount 배수)
+	DipThreshold  float64 // dip: 기준 대비 하락률
+	DipLookback   int     // dip: 기준 기간 (일봉 개수)
+	DipBasis      string  // dip: high | ma
+	DipMultiplier float64 // dip: 확대 배수
+
+	DCASession        string // 적립/리밸런싱 주문 세션 ("" = 정규장)
+	ReinvestDividends bool   // 입금된 배당을 적립 예산에 더함
 }
 
 type AggressiveConfig struct {
-	Alloc float64
+	Alloc          float64
+	MaxHoldings    int     // 동시 보유 종목 수 상한
+	PositionRatio  float64 // 종목당 비중 (전체 평가금액 대비)
+	Pyramiding     bool    // 보유 종목 추가 매수 허용
+	Sizer          string  // fixed_fraction | fixed_risk | atr | kelly
+	RiskPerTrade   float64 // fixed_risk: 거래당 위험 (평가금액 대비)
+	VolTarget      float64 // atr: ATR 1배 움직임의 손익 목표 (평가금액 대비)
+	KellyWinRate   float64
+	KellyPayoff    float64 // 평균 이익 / 평균 손실
+	KellyFraction  float64 // 켈리 비율에 곱할 값 (0.5 = 하프 켈리)
+	KellyCap       float64 // 최대 비율
+	TakeProfit     float64 // 익절 수익률 (0.2 = +20%)
+	StopLoss       float64 // 손절 손실률 (0.06 = -6%)
+	MaxHoldingDays int     // 최대 보유 기간 (달력일, 0 이면 제한 없음)
+	CooldownDays   int     // 손절 후 재진입 금지 기간
+	TrailingPct    float64 // 고점 대비 트레일링 스탑 (0 이면 미사용)
+	TrailingATR    float64 // 고점 - ATR × 배수 트레일링 스탑 (0 이면 미사용)
+	ATRPeriod      int
+	BreakevenAfter float64 // 이 수익률 도달 후 스탑을 본전으로 (0 이면 미사용)
+}
+
+// MeanReversionConfig KOSPI 200 대형주 평균 회귀 슬리브
+type MeanReversionConfig struct {
+	Alloc           float64
+	Universe        []string // 후보 종목코드
+	Screener        string   // SCREENER_RULES_FILE 안의 유니버스 스크리너 (있으면 Universe 대신)
+	MinTradingValue float64  // 당일 거래대금 하한 (원)
+	MaxHoldings     int
+	PositionRatio   float64
+	Sizer           string // fixed_fraction | fixed_risk | atr | kelly (파라미터는 MEANREV_ 접두사)
+	RiskPerTrade    float64
+	VolTarget       float64
+	KellyWinRate    float64
+	KellyPayoff     float64
+	KellyFraction   float64
+	KellyCap        float64
+	BBPeriod        int
+	BBK             float64
+	RSIPeriod       int
+	RSIEntry        float64
+	MaxHoldingDays  int
+	StopLoss        float64
+}
+
+// defaultMeanRevUniverse KOSPI 200 시총 상위 유동성 종목
+var defaultMeanRevUniverse = []string{
+	"005930", "000660", "373220", "207940", "005380", "000270", "068270", "035420", "005490", "051910",
+	"006400", "105560", "055550", "035720", "012330", "028260", "066570", "003550", "032830", "096770",
+}
+
+// RotationConfig ETF 모멘텀 로테이션
+type RotationConfig struct {
+	Alloc           float64
+	Universe        []string
+	Lookbacks       []int // 개월
+	TopK            int
+	SafeAsset       string // 절대 모멘텀 탈락 슬롯을 옮길 ETF ("" 이면 현금)
+	AbsThreshold    float64
+	RebalanceMonths int
+	Band            float64
 }
 
 type RiskConfig struct {
-	MaxRisk float64
+	MaxRisk        float64 // 최고 평가금액 대비 누적 손실 한도 (넘으면 신규 매수 중단)
+	DailyLossLimit float64 // 장 시작 평가금액 대비 당일 손실 한도
+	SnapshotAt     string  // --daemon: 장 마감 후 평가금액 기록 시각 (다음 날 시작 평가금액)
+	MinCashRatio   float64 // 주문 후에도 유지할 현금 비율 (전체 평가금액 대비)
+}
+
+// ExecutionConfig 체결 확인과 큰 주문의 분할 실행 (TWAP / 호가 추종 지정가)
+type ExecutionConfig struct {
+	FillTimeout   int     // 시장가 주문 체결 확인 대기 (초). 지나면 잔량 취소
+	SessionWait   int     // 동시호가/시간외 주문 체결 대기 (분). 지나면 잔량 취소
+	Algo          string  // market | twap | peg
+	AlgoMinValue  float64 // 이 금액 이상 주문만 알고리즘으로
+	WindowMinutes int     // 부모 주문 실행 시간
+	Slices        int     // twap 자식 주문 수
+	RepegSeconds  int     // peg 호가 재확인 간격
+	Sweep         bool    // 창 종료 후 잔량 시장가 정리
+}
+
+// DividendConfig 배당 기록/원장 반영
+type DividendConfig struct {
+	TaxRate float64 // 원천징수 세율 (0.154)
+	PostAt  string  // --daemon 모드에서 지급분을 원장에 반영하는 시각 (KST HH:MM)
+}
+
+// CorpActionConfig 분할/무상증자/코드 변경 반영
+type CorpActionConfig struct {
+	ApplyAt string // --daemon 모드에서 배당락일이 된 이벤트를 반영하는 시각 (KST HH:MM)
+}
+
+type MarketDataConfig struct {
+	QuoteTTLSeconds int // 같은 실행 안에서 시세 재조회를 막는 메모리 캐시 TTL
+}
+
+type ScreenerConfig struct {
+	RulesFile string // 규칙 DSL JSON 파일 경로 (비어 있으면 기본 KOSDAQ 스크리너)
+	Name      string // RulesFile 안에서 사용할 스크리너 이름 (비어 있으면 첫 번째)
 }
 
 func mustEnv(key string) string {
@@ -45,6 +179,13 @@ func mustEnv(key string) string {
 	return v
 }
 
+func getEnv(key, def string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return def
+}
+
 func getEnvFloat(key string, def float64) float64 {
 	v := os.Getenv(key)
 	if v == "" {
@@ -69,6 +210,122 @@ func getEnvInt(key string, def int) int {
 	return i
 }
 
+func getEnvBool(key string, def bool) bool {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatalf("invalid bool env %s: %v", key, err)
+	}
+	return b
+}
+
+// parseWeights "069500:50,360750:50" 형식 (비율 또는 퍼센트) 을 합 1 로 정규화한다.
+// 비어 있으면 etfs 동일 비중. 가중치에만 있는 ETF 는 목록에 없어도 목표에 포함된다.
+// etfs 에 있는데 가중치가 없으면 리밸런싱이 전량 매도하므로 거부한다 (정리하려면 CODE:0 으로 명시).
+func parseWeights(v string, etfs []string) map[string]float64 {
+	w := map[string]float64{}
+	if strings.TrimSpace(v) == "" {
+		for _, code := range etfs {
+			w[code] = 1
+		}
+	} else {
+		for _, part := range strings.Split(v, ",") {
+			code, val, ok := strings.Cut(strings.TrimSpace(part), ":")
+			if !ok {
+				log.Fatalf("invalid STABLE_WEIGHTS entry %q (want CODE:WEIGHT)", part)
+			}
+			f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(val), "%"), 64)
+			if err != nil || f < 0 {
+				log.Fatalf("invalid STABLE_WEIGHTS weight %q", part)
+			}
+			w[strings.TrimSpace(code)] = f
+		}
+		for _, code := range etfs {
+			if _, ok := w[code]; !ok {
+				log.Fatalf("STABLE_WEIGHTS has no weight for STABLE_ETFS entry %s (set %s:0 to sell it down)", code, code)
+			}
+		}
+	}
+
+	var sum float64
+	for _, f := range w {
+		sum += f
+	}
+	if sum <= 0 {
+		log.Fatalf("STABLE_WEIGHTS must sum to a positive value")
+	}
+	for code := range w {
+		w[code] /= sum
+	}
+	return w
+}
+
+// parseCodes "069500,360750" → 코드 목록. 비어 있으면 def.
+func parseCodes(v string, def []string) []string {
+	var out []string
+	for _, code := range strings.Split(v, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			out = append(out, code)
+		}
+	}
+	if len(out) == 0 {
+		return def
+	}
+	return out
+}
+
+// parseInts "3,6,12"
+func parseInts(key, v string, def []int) []int {
+	if strings.TrimSpace(v) == "" {
+		return def
+	}
+	var out []int
+	for _, part := range strings.Split(v, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			log.Fatalf("invalid %s entry %q", key, part)
+		}
+		out = append(out, n)
+	}
+	return out
+}
+
+// parseSchedule "stable=09:05,aggressive=09:10"
+func parseSchedule(v string) []ScheduleEntry {
+	var out []ScheduleEntry
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, at, ok := strings.Cut(part, "=")
+		if !ok {
+			log.Fatalf("invalid SCHEDULE entry %q (want STRATEGY=HH:MM)", part)
+		}
+		out = append(out, ScheduleEntry{Strategy: strings.TrimSpace(name), At: strings.TrimSpace(at)})
+	}
+	return out
+}
+
+// parseDates "2025-01-01,2025-03-03" 형식 검증
+func parseDates(key, v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", part); err != nil {
+			log.Fatalf("invalid %s entry %q (want YYYY-MM-DD)", key, part)
+		}
+		out = append(out, part)
+	}
+	return out
+}
+
 func Load() *AppConfig {
 	rawMock := strings.TrimSpace(os.Getenv("MOCK_TRADING"))
 	mock := strings.EqualFold(rawMock, "true")
@@ -101,21 +358,170 @@ func Load() *AppConfig {
 		}
 		etfs = parts
 	}
+	switch dcaMode := getEnv("STABLE_DCA_MODE", "fixed"); dcaMode {
+	case "fixed", "value_averaging", "dip":
+	default:
+		log.Fatalf("invalid STABLE_DCA_MODE %q (fixed|value_averaging|dip)", dcaMode)
+	}
+
+	// 로테이션 절대 모멘텀 탈락 슬롯: 기본 단기채 ETF, cash 면 현금 보유
+	safeAsset := getEnv("ROTATION_SAFE_ASSET", "153130")
+	if strings.EqualFold(safeAsset, "cash") {
+		safeAsset = ""
+	}
 
-	return &AppConfig{
+	for _, key := range []string{"MAX_RISK", "DAILY_LOSS_LIMIT"} {
+		if v := getEnvFloat(key, 0); v < 0 || v >= 1 {
+			log.Fatalf("invalid %s %g (want 0 <= v < 1, 0 disables)", key, v)
+		}
+	}
+
+	switch algo := getEnv("EXEC_ALGO", "market"); algo {
+	case "market", "twap", "peg":
+	default:
+		log.Fatalf("invalid EXEC_ALGO %q (market|twap|peg)", algo)
+	}
+
+	dcaSession := getEnv("STABLE_DCA_SESSION", "regular")
+	switch dcaSession {
+	case "regular":
+		dcaSession = models.SessionRegular
+	case models.SessionOpeningAuction, models.SessionPreMarket, models.SessionClosingAuction,
+		models.SessionAfterHours, models.SessionAfterSingle:
+	default:
+		log.Fatalf("invalid STABLE_DCA_SESSION %q (regular|opening_auction|pre_market|closing_auction|after_hours|after_hours_single)", dcaSession)
+	}
+
+	// STABLE_WEIGHTS 만 주면 ETF 목록은 가중치에서 정한다 (기본 목록을 섞지 않는다).
+	weightsEnv := os.Getenv("STABLE_WEIGHTS")
+	if etfsEnv == "" && strings.TrimSpace(weightsEnv) != "" {
+		etfs = nil
+	}
+	weights := parseWeights(weightsEnv, etfs)
+	for _, code := range slices.Sorted(maps.Keys(weights)) {
+		if !slices.Contains(etfs, code) {
+			etfs = append(etfs, code)
+		}
+	}
+
+	cfg := &AppConfig{
 		KIS: kisCfg,
 		Stable: StableConfig{
-			Alloc:         getEnvFloat("STABLE_ALLOC", 0.7),
-			ETFs:          etfs,
-			DailyAmount:   70000,
-			RebalanceDays: getEnvInt("REBALANCE_PERIOD", 90),
+			Alloc:          getEnvFloat("STABLE_ALLOC", 0.56),
+			ETFs:           etfs,
+			Weights:        weights,
+			DailyAmount:    int64(getEnvInt("STABLE_DAILY_AMOUNT", 70000)),
+			RebalanceDays:  getEnvInt("REBALANCE_PERIOD", 90),
+			RebalanceDrift: getEnvFloat("REBALANCE_DRIFT", 0.05),
+			DCAMode:        getEnv("STABLE_DCA_MODE", "fixed"),
+			VAGrowth:       getEnvFloat("STABLE_VA_GROWTH", 0.08),
+			VAMaxMultiple:  getEnvFloat("STABLE_VA_MAX_MULTIPLE", 3),
+			DipThreshold:   getEnvFloat("STABLE_DIP_THRESHOLD", 0.05),
+			DipLookback:    getEnvInt("STABLE_DIP_LOOKBACK", 60),
+			DipBasis:       getEnv("STABLE_DIP_BASIS", "high"),
+			DipMultiplier:  getEnvFloat("STABLE_DIP_MULTIPLIER", 2),
+
+			DCASession:        dcaSession,
+			ReinvestDividends: getEnvBool("STABLE_REINVEST_DIVIDENDS", true),
 		},
 		Aggressive: AggressiveConfig{
-			Alloc: getEnvFloat("AGGRESSIVE_ALLOC", 0.3),
+			Alloc:          getEnvFloat("AGGRESSIVE_ALLOC", 0.24),
+			MaxHoldings:    getEnvInt("AGGRESSIVE_MAX_HOLDINGS", 6),
+			PositionRatio:  getEnvFloat("AGGRESSIVE_POSITION_RATIO", 0.04),
+			Pyramiding:     getEnvBool("AGGRESSIVE_PYRAMIDING", false),
+			Sizer:          getEnv("AGGRESSIVE_SIZER", "fixed_fraction"),
+			RiskPerTrade:   getEnvFloat("AGGRESSIVE_RISK_PER_TRADE", 0.01),
+			VolTarget:      getEnvFloat("AGGRESSIVE_VOL_TARGET", 0.005),
+			KellyWinRate:   getEnvFloat("AGGRESSIVE_KELLY_WIN_RATE", 0.55),
+			KellyPayoff:    getEnvFloat("AGGRESSIVE_KELLY_PAYOFF", 2),
+			KellyFraction:  getEnvFloat("AGGRESSIVE_KELLY_FRACTION", 0.5),
+			KellyCap:       getEnvFloat("AGGRESSIVE_KELLY_CAP", 0.05),
+			TakeProfit:     getEnvFloat("AGGRESSIVE_TAKE_PROFIT", 0.2),
+			StopLoss:       getEnvFloat("AGGRESSIVE_STOP_LOSS", 0.06),
+			MaxHoldingDays: getEnvInt("AGGRESSIVE_MAX_HOLDING_DAYS", 90),
+			CooldownDays:   getEnvInt("AGGRESSIVE_COOLDOWN_DAYS", 20),
+			TrailingPct:    getEnvFloat("AGGRESSIVE_TRAILING_STOP", 0),
+			TrailingATR:    getEnvFloat("AGGRESSIVE_TRAILING_ATR", 0),
+			ATRPeriod:      getEnvInt("AGGRESSIVE_ATR_PERIOD", 14),
+			BreakevenAfter: getEnvFloat("AGGRESSIVE_BREAKEVEN_AFTER", 0),
+		},
+		MeanRev: MeanReversionConfig{
+			Alloc:           getEnvFloat("MEANREV_ALLOC", 0),
+			Universe:        parseCodes(os.Getenv("MEANREV_UNIVERSE"), defaultMeanRevUniverse),
+			Screener:        getEnv("MEANREV_SCREENER", ""),
+			MinTradingValue: getEnvFloat("MEANREV_MIN_TRADING_VALUE", 10_000_000_000),
+			MaxHoldings:     getEnvInt("MEANREV_MAX_HOLDINGS", 5),
+			PositionRatio:   getEnvFloat("MEANREV_POSITION_RATIO", 0.03),
+			Sizer:           getEnv("MEANREV_SIZER", "fixed_fraction"),
+			RiskPerTrade:    getEnvFloat("MEANREV_RISK_PER_TRADE", 0.005),
+			VolTarget:       getEnvFloat("MEANREV_VOL_TARGET", 0.003),
+			KellyWinRate:    getEnvFloat("MEANREV_KELLY_WIN_RATE", 0.6),
+			KellyPayoff:     getEnvFloat("MEANREV_KELLY_PAYOFF", 1),
+			KellyFraction:   getEnvFloat("MEANREV_KELLY_FRACTION", 0.5),
+			KellyCap:        getEnvFloat("MEANREV_KELLY_CAP", 0.03),
+			BBPeriod:        getEnvInt("MEANREV_BB_PERIOD", 20),
+			BBK:             getEnvFloat("MEANREV_BB_K", 2),
+			RSIPeriod:       getEnvInt("MEANREV_RSI_PERIOD", 2),
+			RSIEntry:        getEnvFloat("MEANREV_RSI_ENTRY", 10),
+			MaxHoldingDays:  getEnvInt("MEANREV_MAX_HOLDING_DAYS", 7),
+			StopLoss:        getEnvFloat("MEANREV_STOP_LOSS", 0.08),
+		},
+		Rotation: RotationConfig{
+			Alloc:           getEnvFloat("ROTATION_ALLOC", 0),
+			Universe:        parseCodes(os.Getenv("ROTATION_UNIVERSE"), []string{"069500", "360750", "148070", "132030"}),
+			Lookbacks:       parseInts("ROTATION_LOOKBACKS", os.Getenv("ROTATION_LOOKBACKS"), []int{3, 6, 12}),
+			TopK:            getEnvInt("ROTATION_TOP_K", 2),
+			SafeAsset:       safeAsset,
+			AbsThreshold:    getEnvFloat("ROTATION_ABS_THRESHOLD", 0),
+			RebalanceMonths: getEnvInt("ROTATION_REBALANCE_MONTHS", 1),
+			Band:            getEnvFloat("ROTATION_BAND", 0.05),
 		},
 		Risk: RiskConfig{
-			MaxRisk: getEnvFloat("MAX_RISK", 0.1),
+			MaxRisk:        getEnvFloat("MAX_RISK", 0.1),
+			DailyLossLimit: getEnvFloat("DAILY_LOSS_LIMIT", 0.03),
+			SnapshotAt:     getEnv("EQUITY_SNAPSHOT_AT", "15:40"),
+			MinCashRatio:   getEnvFloat("MIN_CASH_RATIO", 0.2),
+		},
+		Execution: ExecutionConfig{
+			FillTimeout:   getEnvInt("EXEC_FILL_TIMEOUT", 30),
+			SessionWait:   getEnvInt("EXEC_SESSION_WAIT", 30),
+			Algo:          getEnv("EXEC_ALGO", "market"),
+			AlgoMinValue:  getEnvFloat("EXEC_ALGO_MIN_VALUE", 1000000),
+			WindowMinutes: getEnvInt("EXEC_ALGO_WINDOW", 30),
+			Slices:        getEnvInt("EXEC_ALGO_SLICES", 6),
+			RepegSeconds:  getEnvInt("EXEC_ALGO_REPEG", 60),
+			Sweep:         getEnvBool("EXEC_ALGO_SWEEP", true),
+		},
+		Dividend: DividendConfig{
+			TaxRate: getEnvFloat("DIVIDEND_TAX_RATE", 0.154),
+			PostAt:  getEnv("DIVIDEND_POST_AT", "08:50"),
+		},
+		CorpAction: CorpActionConfig{
+			ApplyAt: getEnv("CORP_ACTION_APPLY_AT", "08:45"),
+		},
+		MarketData: MarketDataConfig{
+			QuoteTTLSeconds: getEnvInt("QUOTE_CACHE_TTL", 30),
+		},
+		Screener: ScreenerConfig{
+			RulesFile: strings.TrimSpace(os.Getenv("SCREENER_RULES_FILE")),
+			Name:      strings.TrimSpace(os.Getenv("SCREENER_NAME")),
+		},
+		Schedule: parseSchedule(os.Getenv("SCHEDULE")),
+		Daemon: DaemonConfig{
+			Grace:    getEnvInt("SCHEDULE_GRACE_MINUTES", 20),
+			Holidays: parseDates("MARKET_HOLIDAYS", os.Getenv("MARKET_HOLIDAYS")),
 		},
 		MockTrading: mock,
 	}
+	checkSleeveAllocs(cfg)
+	return cfg
+}
+
+// checkSleeveAllocs 슬리브 비중 합이 1 − MIN_CASH_RATIO 를 넘지 않아야 한다. 넘으면 hybrid 의 현금 하한이
+// 매번 뒤쪽 슬리브 매수를 잘라 낸다. 기본값(0.56/0.24, 현금 0.2)은 투자분을 70:30 으로 나눈 값이다.
+func checkSleeveAllocs(c *AppConfig) {
+	sum := c.Stable.Alloc + c.Aggressive.Alloc + c.MeanRev.Alloc + c.Rotation.Alloc
+	if limit := 1 - c.Risk.MinCashRatio; sum > limit+1e-9 {
+		log.Fatalf("STABLE_ALLOC + AGGRESSIVE_ALLOC + MEANREV_ALLOC + ROTATION_ALLOC = %g exceeds 1 - MIN_CASH_RATIO = %g", sum, limit)
+	}
 }
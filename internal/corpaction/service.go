@@ -0,0 +1,164 @@
+// Package corpaction 액면분할/병합, 무상증자, 합병에 따른 코드 변경을 보유 포지션, 과거 일봉, 스탑 가격에 반영한다.
+package corpaction
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"stock-investing/internal/execution"
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+// TradeSource 권리락일(분할/무상증자 기준일, 코드 변경일) 전 보유 수량 계산용 (storage.Repository 가 구현)
+type TradeSource interface {
+	ListTradesByCode(ctx context.Context, code string) ([]*models.Trade, error)
+}
+
+type Service struct {
+	actions storage.CorporateActionRepository
+	trades  TradeSource
+}
+
+func NewService(actions storage.CorporateActionRepository, trades TradeSource) *Service {
+	return &Service{actions: actions, trades: trades}
+}
+
+// Validate 종류별 필수 값 확인
+func Validate(a *models.CorporateAction) error {
+	if a.Code == "" || a.ExDate.IsZero() {
+		return fmt.Errorf("corporate action needs code and ex date")
+	}
+	switch a.Kind {
+	case models.CorpSplit, models.CorpBonus, models.CorpRemap:
+	default:
+		return fmt.Errorf("unknown corporate action %q (want split|bonus|remap)", a.Kind)
+	}
+	if a.Ratio <= 0 {
+		return fmt.Errorf("%s %s: ratio must be > 0", a.Kind, a.Code)
+	}
+	if a.Kind == models.CorpRemap && (a.NewCode == "" || a.NewCode == a.Code) {
+		return fmt.Errorf("remap %s: new code required", a.Code)
+	}
+	return nil
+}
+
+// Add 이벤트를 등록한다. 적용은 ExDate 가 지난 뒤 ApplyPending 에서 한다.
+func (s *Service) Add(ctx context.Context, a *models.CorporateAction) error {
+	if err := Validate(a); err != nil {
+		return err
+	}
+	return s.actions.AddAction(ctx, a)
+}
+
+// ApplyPending ExDate 가 asOf 이전인 미적용 이벤트를 오래된 순으로 적용한다. 적용한 개수를 반환한다.
+func (s *Service) ApplyPending(ctx context.Context, asOf time.Time) (int, error) {
+	pending, err := s.actions.ListPending(ctx, asOf)
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	for _, a := range pending {
+		ok, err := s.Apply(ctx, a)
+		if err != nil {
+			return applied, fmt.Errorf("apply %s %s %s: %w", a.Kind, a.Code, a.ExDate.Format("2006-01-02"), err)
+		}
+		if ok {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+// Apply 권리락일 전날까지의 체결로 전략별 보유 수량을 구해 보정 체결을 만들고, 일봉/스탑과 함께 한 번에 반영한다.
+// 이미 적용된 이벤트면 false. 1주 미만 단수주는 버린다 (단수주 대금은 증권사 입금으로 처리된다).
+func (s *Service) Apply(ctx context.Context, a *models.CorporateAction) (bool, error) {
+	if err := Validate(a); err != nil {
+		return false, err
+	}
+	trades, err := s.trades.ListTradesByCode(ctx, a.Code)
+	if err != nil {
+		return false, err
+	}
+	byStrategy := map[string][]*models.Trade{}
+	for _, t := range trades {
+		if t.Time.Before(a.ExDate) {
+			byStrategy[t.Strategy] = append(byStrategy[t.Strategy], t)
+		}
+	}
+	strategies := make([]string, 0, len(byStrategy))
+	for st := range byStrategy {
+		strategies = append(strategies, st)
+	}
+	sort.Strings(strategies)
+
+	var adjustments []storage.PositionAdjustment
+	for _, st := range strategies {
+		for _, p := range execution.PositionsFromTrades(st, byStrategy[st]) {
+			if adj, ok := adjust(a, p); ok {
+				adjustments = append(adjustments, adj)
+			}
+		}
+	}
+
+	ok, err := s.actions.ApplyAction(ctx, a, adjustments, time.Now())
+	if err != nil || !ok {
+		return ok, err
+	}
+	logger.Info.Printf("[corpaction] applied %s %s ex=%s ratio=%g %s (%d positions)\n",
+		a.Kind, a.Code, a.ExDate.Format("2006-01-02"), a.Ratio, a.NewCode, len(adjustments))
+	for _, adj := range adjustments {
+		logger.Info.Printf("[corpaction] %s %s: %s -> %s\n", adj.Strategy, adj.Code, adj.Before, adj.After)
+	}
+	return true, nil
+}
+
+// adjust 한 포지션의 보정 체결. 분할/무상증자는 원가 0 인 주식 증감으로 총원가를 유지하고,
+// 코드 변경은 REMAP 으로 기존 코드 전량 차감 + 새 코드 편입(총원가 유지)을 둘 다 권리락일 시각에 남긴다.
+// 진입 시각과 실현 손익은 execution.RemapCarry 가 차감 → 편입 순서로 넘긴다.
+// 새 코드가 1주 미만이면 편입할 것이 없어 ADJUST 차감만 남기고 (청산으로 기록된다).
+func adjust(a *models.CorporateAction, p *models.Position) (storage.PositionAdjustment, bool) {
+	cost := p.AvgPrice * float64(p.Quantity)
+	qty := int64(math.Floor(float64(p.Quantity)*a.Factor() + 1e-9))
+	adj := storage.PositionAdjustment{
+		Strategy: p.Strategy,
+		Code:     p.Code,
+		Before:   fmt.Sprintf("%s %d @ %.2f", p.Code, p.Quantity, p.AvgPrice),
+	}
+
+	if a.Kind == models.CorpRemap {
+		side := models.SideRemap
+		if qty <= 0 {
+			side = models.SideAdjust
+		}
+		adj.Trades = append(adj.Trades, &models.Trade{
+			Strategy: p.Strategy, Code: p.Code, Side: side, Quantity: -p.Quantity, Time: a.ExDate,
+		})
+		if qty > 0 {
+			adj.Trades = append(adj.Trades, &models.Trade{
+				Strategy: p.Strategy, Code: a.NewCode, Side: models.SideRemap, Quantity: qty, Price: cost / float64(qty), Time: a.ExDate,
+			})
+			adj.After = fmt.Sprintf("%s %d @ %.2f", a.NewCode, qty, cost/float64(qty))
+		} else {
+			adj.After = a.NewCode + " 0"
+		}
+		return adj, true
+	}
+
+	if qty == p.Quantity {
+		return adj, false
+	}
+	adj.Trades = append(adj.Trades, &models.Trade{
+		Strategy: p.Strategy, Code: p.Code, Side: models.SideAdjust, Quantity: qty - p.Quantity, Time: a.ExDate,
+	})
+	if qty > 0 {
+		adj.After = fmt.Sprintf("%s %d @ %.2f", p.Code, qty, cost/float64(qty))
+	} else {
+		adj.After = p.Code + " 0"
+	}
+	return adj, true
+}
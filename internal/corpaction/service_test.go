@@ -0,0 +1,189 @@
+package corpaction
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"stock-investing/internal/execution"
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+)
+
+// memActions ApplyAction 에 넘어온 보정만 기록한다.
+type memActions struct {
+	storage.CorporateActionRepository
+	applied     bool // true 면 이미 적용된 이벤트로 본다
+	adjustments []storage.PositionAdjustment
+}
+
+func (m *memActions) ApplyAction(_ context.Context, _ *models.CorporateAction, adj []storage.PositionAdjustment, _ time.Time) (bool, error) {
+	if m.applied {
+		return false, nil
+	}
+	m.adjustments = adj
+	return true, nil
+}
+
+// memTrades 체결 기록 (ID 순으로 쌓인다)
+type memTrades []*models.Trade
+
+func (m memTrades) ListTradesByCode(_ context.Context, code string) ([]*models.Trade, error) {
+	var out []*models.Trade
+	for _, t := range m {
+		if t.Code == code {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func tradeList(trades []*models.Trade) string {
+	var out []string
+	for _, t := range trades {
+		out = append(out, fmt.Sprintf("%s %s %d @ %.2f %s", t.Side, t.Code, t.Quantity, t.Price, t.Time.Format("01-02")))
+	}
+	return strings.Join(out, ", ")
+}
+
+func TestAdjust(t *testing.T) {
+	exDate := time.Date(2025, 6, 10, 0, 0, 0, 0, models.KST)
+	opened := time.Date(2025, 3, 3, 9, 0, 0, 0, models.KST)
+	pos := func(qty int64, avg float64) *models.Position {
+		return &models.Position{Strategy: "s", Code: "A", Quantity: qty, AvgPrice: avg, OpenedAt: opened}
+	}
+
+	tests := []struct {
+		name   string
+		action models.CorporateAction
+		pos    *models.Position
+		ok     bool
+		trades string
+		after  string
+	}{
+		{name: "split", action: models.CorporateAction{Kind: models.CorpSplit, Ratio: 2}, pos: pos(10, 100), ok: true,
+			trades: "ADJUST A 10 @ 0.00 06-10", after: "A 20 @ 50.00"},
+		{name: "reverse split drops fractions", action: models.CorporateAction{Kind: models.CorpSplit, Ratio: 0.3}, pos: pos(5, 100), ok: true,
+			trades: "ADJUST A -4 @ 0.00 06-10", after: "A 1 @ 500.00"},
+		{name: "bonus issue", action: models.CorporateAction{Kind: models.CorpBonus, Ratio: 0.5}, pos: pos(10, 90), ok: true,
+			trades: "ADJUST A 5 @ 0.00 06-10", after: "A 15 @ 60.00"},
+		{name: "no change", action: models.CorporateAction{Kind: models.CorpSplit, Ratio: 1.05}, pos: pos(10, 100)},
+		// 두 REMAP 은 같은 권리락일에 차감 → 편입 순으로 남는다.
+		{name: "remap", action: models.CorporateAction{Kind: models.CorpRemap, Ratio: 0.5, NewCode: "B"}, pos: pos(6, 100), ok: true,
+			trades: "REMAP A -6 @ 0.00 06-10, REMAP B 3 @ 200.00 06-10", after: "B 3 @ 200.00"},
+		{name: "remap below one share", action: models.CorporateAction{Kind: models.CorpRemap, Ratio: 0.1, NewCode: "B"}, pos: pos(5, 100), ok: true,
+			trades: "ADJUST A -5 @ 0.00 06-10", after: "B 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.action
+			a.Code, a.ExDate = "A", exDate
+			adj, ok := adjust(&a, tt.pos)
+			if ok != tt.ok {
+				t.Fatalf("adjust ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if got := tradeList(adj.Trades); got != tt.trades {
+				t.Errorf("trades = %s, want %s", got, tt.trades)
+			}
+			if adj.After != tt.after || adj.Before != fmt.Sprintf("A %d @ %.2f", tt.pos.Quantity, tt.pos.AvgPrice) {
+				t.Errorf("before/after = %s -> %s, want %s", adj.Before, adj.After, tt.after)
+			}
+		})
+	}
+}
+
+func TestApplyRemap(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 10, 0, 0, 0, models.KST) }
+	exDate := time.Date(2025, 6, 10, 0, 0, 0, 0, models.KST)
+	trades := memTrades{
+		{ID: 1, Strategy: "s", Code: "A", Side: "BUY", Quantity: 10, Price: 100, Time: day(2)},
+		{ID: 2, Strategy: "t", Code: "A", Side: "BUY", Quantity: 3, Price: 100, Time: day(3)},
+		{ID: 3, Strategy: "s", Code: "A", Side: "SELL", Quantity: 4, Price: 130, Time: day(5)},
+		{ID: 4, Strategy: "u", Code: "A", Side: "BUY", Quantity: 5, Price: 100, Time: day(10)}, // 권리락일 매수는 대상 아님
+	}
+	a := &models.CorporateAction{Code: "A", Kind: models.CorpRemap, ExDate: exDate, Ratio: 0.5, NewCode: "B"}
+
+	repo := &memActions{}
+	ok, err := NewService(repo, trades).Apply(context.Background(), a)
+	if err != nil || !ok {
+		t.Fatalf("Apply = %v %v", ok, err)
+	}
+	var got []string
+	for _, adj := range repo.adjustments {
+		got = append(got, adj.Strategy+": "+tradeList(adj.Trades))
+	}
+	want := []string{
+		"s: REMAP A -6 @ 0.00 06-10, REMAP B 3 @ 200.00 06-10",
+		"t: REMAP A -3 @ 0.00 06-10, REMAP B 1 @ 300.00 06-10",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("adjustments = %v, want %v", got, want)
+	}
+
+	// 저장소처럼 (time, id) 순으로 다시 읽어도 실현 손익과 진입 시각이 새 코드로 이어진다.
+	log := append(memTrades{}, trades...)
+	for _, adj := range repo.adjustments {
+		for _, tr := range adj.Trades {
+			tr.ID = int64(len(log) + 1)
+			log = append(log, tr)
+		}
+	}
+	sort.SliceStable(log, func(i, j int) bool {
+		if !log[i].Time.Equal(log[j].Time) {
+			return log[i].Time.Before(log[j].Time)
+		}
+		return log[i].ID < log[j].ID
+	})
+	var s []*models.Trade
+	for _, tr := range log {
+		if tr.Strategy == "s" {
+			s = append(s, tr)
+		}
+	}
+	positions := execution.PositionsFromTrades("s", s)
+	if len(positions) != 1 {
+		t.Fatalf("positions = %d, want 1", len(positions))
+	}
+	p := positions[0]
+	if p.Code != "B" || p.Quantity != 3 || math.Abs(p.AvgPrice-200) > 1e-9 || math.Abs(p.Realized-120) > 1e-9 || !p.OpenedAt.Equal(day(2)) {
+		t.Errorf("position = %s %d @ %.2f realized %.2f opened %s, want B 3 @ 200 realized 120 opened %s",
+			p.Code, p.Quantity, p.AvgPrice, p.Realized, p.OpenedAt, day(2))
+	}
+
+	// 이미 적용된 이벤트
+	if ok, err := NewService(&memActions{applied: true}, trades).Apply(context.Background(), a); ok || err != nil {
+		t.Errorf("Apply on applied action = %v %v, want false", ok, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	exDate := time.Date(2025, 6, 10, 0, 0, 0, 0, models.KST)
+	tests := []struct {
+		name   string
+		action models.CorporateAction
+		err    string
+	}{
+		{name: "split", action: models.CorporateAction{Code: "A", Kind: models.CorpSplit, ExDate: exDate, Ratio: 5}},
+		{name: "missing ex date", action: models.CorporateAction{Code: "A", Kind: models.CorpSplit, Ratio: 5}, err: "needs code and ex date"},
+		{name: "unknown kind", action: models.CorporateAction{Code: "A", Kind: "spinoff", ExDate: exDate, Ratio: 1}, err: "unknown corporate action"},
+		{name: "zero ratio", action: models.CorporateAction{Code: "A", Kind: models.CorpBonus, ExDate: exDate}, err: "ratio must be > 0"},
+		{name: "remap to itself", action: models.CorporateAction{Code: "A", Kind: models.CorpRemap, ExDate: exDate, Ratio: 1, NewCode: "A"}, err: "new code required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.action)
+			if (err != nil) != (tt.err != "") || (err != nil && !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("Validate = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
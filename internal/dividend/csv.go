@@ -0,0 +1,101 @@
+package dividend
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+type csvField int
+
+const (
+	fieldCode csvField = iota
+	fieldExDate
+	fieldPayDate
+	fieldPerShare
+)
+
+// 헤더 별칭 (소문자/공백 제거 후 비교)
+var headerAliases = map[string]csvField{
+	"code": fieldCode, "symbol": fieldCode, "ticker": fieldCode, "종목코드": fieldCode, "단축코드": fieldCode,
+	"ex_date": fieldExDate, "exdate": fieldExDate, "배당락일": fieldExDate, "분배락일": fieldExDate,
+	"pay_date": fieldPayDate, "paydate": fieldPayDate, "지급일": fieldPayDate, "지급예정일": fieldPayDate,
+	"per_share": fieldPerShare, "dividend": fieldPerShare, "amount": fieldPerShare,
+	"주당배당금": fieldPerShare, "주당분배금": fieldPerShare, "분배금": fieldPerShare, "배당금": fieldPerShare,
+}
+
+var dateLayouts = []string{"2006-01-02", "2006/01/02", "2006.01.02", "20060102"}
+
+// ParseCSV 배당 일정 CSV 를 읽는다. 종목코드/배당락일/주당배당금은 필수, 지급일이 없으면 배당락일로 둔다.
+func ParseCSV(r io.Reader) ([]Event, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	cols := map[csvField]int{}
+	for i, h := range header {
+		key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")), " ", ""))
+		if f, ok := headerAliases[key]; ok {
+			cols[f] = i
+		}
+	}
+	for _, f := range []csvField{fieldCode, fieldExDate, fieldPerShare} {
+		if _, ok := cols[f]; !ok {
+			return nil, fmt.Errorf("missing column (need code, ex_date, per_share): header=%v", header)
+		}
+	}
+
+	var out []Event
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		get := func(f csvField) string {
+			i, ok := cols[f]
+			if !ok || i >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[i])
+		}
+		if get(fieldCode) == "" {
+			continue
+		}
+
+		ev := Event{Code: get(fieldCode)}
+		if ev.ExDate, err = parseDate(get(fieldExDate)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if v := get(fieldPayDate); v != "" {
+			if ev.PayDate, err = parseDate(v); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		if ev.PerShare, err = strconv.ParseFloat(strings.ReplaceAll(get(fieldPerShare), ",", ""), 64); err != nil || ev.PerShare <= 0 {
+			return nil, fmt.Errorf("line %d: invalid per-share amount %q", line, get(fieldPerShare))
+		}
+		out = append(out, ev)
+	}
+	return out, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, models.KST); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
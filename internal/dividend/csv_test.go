@@ -0,0 +1,49 @@
+package dividend
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		events []string // "code ex pay per_share"
+		err    string
+	}{
+		{name: "english headers", src: "code,ex_date,pay_date,per_share\n069500,2025-06-27,2025-07-01,333\n",
+			events: []string{"069500 2025-06-27 2025-07-01 333"}},
+		{name: "korean headers, BOM and thousands separators",
+			src:    "\ufeff종목코드,분배락일,지급예정일,주당분배금\n069500,2025.06.27,20250701,\"1,250\"\n,,,\n",
+			events: []string{"069500 2025-06-27 2025-07-01 1250"}},
+		{name: "pay date column is optional", src: "ticker,exdate,amount\n360750,2025/06/27,55.5\n",
+			events: []string{"360750 2025-06-27 0001-01-01 55.5"}},
+		{name: "missing column", src: "code,pay_date,per_share\n069500,2025-07-01,333\n", err: "missing column"},
+		{name: "invalid date", src: "code,ex_date,per_share\n069500,06/27/2025,333\n", err: "line 2: invalid date"},
+		{name: "non-positive amount", src: "code,ex_date,per_share\n069500,2025-06-27,0\n", err: "invalid per-share amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := ParseCSV(strings.NewReader(tt.src))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseCSV error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, ev := range events {
+				got = append(got, fmt.Sprintf("%s %s %s %g", ev.Code, ev.ExDate.Format("2006-01-02"), ev.PayDate.Format("2006-01-02"), ev.PerShare))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.events, "|") {
+				t.Errorf("events = %v, want %v", got, tt.events)
+			}
+		})
+	}
+}
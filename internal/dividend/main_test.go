@@ -0,0 +1,13 @@
+package dividend
+
+import (
+	"os"
+	"testing"
+
+	"stock-investing/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,134 @@
+// Package dividend 배당/분배금 이벤트를 보유 전략별로 기록하고, 지급분을 현금 원장과 재투자 예산에 넘긴다.
+package dividend
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"stock-investing/internal/execution"
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+// Event 종목의 배당 공시 (CSV 한 행)
+type Event struct {
+	Code     string
+	ExDate   time.Time
+	PayDate  time.Time
+	PerShare float64
+}
+
+// TradeSource 배당락일 전 보유 수량 계산용 (storage.Repository 가 구현)
+type TradeSource interface {
+	ListTradesByCode(ctx context.Context, code string) ([]*models.Trade, error)
+}
+
+type Service struct {
+	divs    storage.DividendRepository
+	trades  TradeSource
+	taxRate float64 // 원천징수 세율 (배당소득세 14% + 지방세 = 0.154)
+}
+
+func NewService(divs storage.DividendRepository, trades TradeSource, taxRate float64) *Service {
+	return &Service{divs: divs, trades: trades, taxRate: taxRate}
+}
+
+// Record 배당락일 전날까지의 체결로 전략별 보유 수량을 구해 배당을 기록한다.
+// 같은 이벤트를 다시 넣으면 (원장 반영 전까지) 갱신된다. 보유 전략이 없으면 아무것도 남기지 않는다.
+func (s *Service) Record(ctx context.Context, ev Event, source string) ([]*models.Dividend, error) {
+	if ev.Code == "" || ev.ExDate.IsZero() || ev.PerShare <= 0 {
+		return nil, fmt.Errorf("invalid dividend event %+v", ev)
+	}
+	if ev.PayDate.IsZero() {
+		ev.PayDate = ev.ExDate
+	}
+
+	trades, err := s.trades.ListTradesByCode(ctx, ev.Code)
+	if err != nil {
+		return nil, err
+	}
+	byStrategy := map[string][]*models.Trade{}
+	for _, t := range trades {
+		if t.Time.Before(ev.ExDate) {
+			byStrategy[t.Strategy] = append(byStrategy[t.Strategy], t)
+		}
+	}
+	strategies := make([]string, 0, len(byStrategy))
+	for st := range byStrategy {
+		strategies = append(strategies, st)
+	}
+	sort.Strings(strategies)
+
+	var out []*models.Dividend
+	for _, st := range strategies {
+		var qty int64
+		for _, p := range execution.PositionsFromTrades(st, byStrategy[st]) {
+			if p.Code == ev.Code {
+				qty = p.Quantity
+			}
+		}
+		if qty <= 0 {
+			continue
+		}
+		gross := ev.PerShare * float64(qty)
+		tax := math.Floor(gross * s.taxRate)
+		d := &models.Dividend{
+			Strategy: st,
+			Code:     ev.Code,
+			ExDate:   ev.ExDate,
+			PayDate:  ev.PayDate,
+			PerShare: ev.PerShare,
+			Quantity: qty,
+			Gross:    gross,
+			Tax:      tax,
+			Net:      gross - tax,
+			Source:   source,
+		}
+		if err := s.divs.UpsertDividend(ctx, d); err != nil {
+			return out, err
+		}
+		logger.Info.Printf("[dividend] %s %s ex=%s pay=%s %d x %.2f = %.0f (net %.0f)\n",
+			st, ev.Code, ev.ExDate.Format("2006-01-02"), ev.PayDate.Format("2006-01-02"), qty, ev.PerShare, gross, d.Net)
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// PostPaid 지급일이 지난 배당을 현금 원장에 입금으로 반영한다. 여러 번 실행해도 한 번만 반영된다.
+func (s *Service) PostPaid(ctx context.Context, asOf time.Time) (int, error) {
+	divs, err := s.divs.ListUnposted(ctx, asOf)
+	if err != nil {
+		return 0, err
+	}
+	for i, d := range divs {
+		if err := s.divs.PostDividend(ctx, d, time.Now()); err != nil {
+			return i, err
+		}
+		logger.Info.Printf("[dividend] posted %s %s %.0f KRW (paid %s)\n", d.Strategy, d.Code, d.Net, d.PayDate.Format("2006-01-02"))
+	}
+	return len(divs), nil
+}
+
+// PendingReinvestment 원장에 입금됐지만 아직 재투자 예산에 넣지 않은 세후 배당 합계와 해당 배당 ID
+func (s *Service) PendingReinvestment(ctx context.Context, strategy string) (float64, []int64, error) {
+	divs, err := s.divs.ListReinvestable(ctx, strategy)
+	if err != nil {
+		return 0, nil, err
+	}
+	var total float64
+	ids := make([]int64, 0, len(divs))
+	for _, d := range divs {
+		total += d.Net
+		ids = append(ids, d.ID)
+	}
+	return total, ids, nil
+}
+
+// MarkReinvested 재투자 예산(적립 이월 금액)으로 넘어간 배당을 표시한다.
+func (s *Service) MarkReinvested(ctx context.Context, ids []int64) error {
+	return s.divs.MarkReinvested(ctx, ids, time.Now())
+}
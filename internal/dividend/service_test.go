@@ -0,0 +1,177 @@
+package dividend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+)
+
+// memDividends 메모리 배당 저장소. 원장 반영/재투자 표시는 ID 로 기록한다.
+type memDividends struct {
+	storage.DividendRepository
+	saved      []*models.Dividend
+	unposted   []*models.Dividend
+	posted     []int64
+	reinvest   []*models.Dividend
+	reinvested []int64
+}
+
+func (m *memDividends) UpsertDividend(_ context.Context, d *models.Dividend) error {
+	m.saved = append(m.saved, d)
+	return nil
+}
+
+func (m *memDividends) ListUnposted(context.Context, time.Time) ([]*models.Dividend, error) {
+	return m.unposted, nil
+}
+
+func (m *memDividends) PostDividend(_ context.Context, d *models.Dividend, _ time.Time) error {
+	if d.ID < 0 {
+		return fmt.Errorf("post %d failed", d.ID)
+	}
+	m.posted = append(m.posted, d.ID)
+	return nil
+}
+
+func (m *memDividends) ListReinvestable(_ context.Context, strategy string) ([]*models.Dividend, error) {
+	var out []*models.Dividend
+	for _, d := range m.reinvest {
+		if d.Strategy == strategy {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (m *memDividends) MarkReinvested(_ context.Context, ids []int64, _ time.Time) error {
+	m.reinvested = append(m.reinvested, ids...)
+	return nil
+}
+
+// memTrades 종목별 체결 기록
+type memTrades []*models.Trade
+
+func (m memTrades) ListTradesByCode(_ context.Context, code string) ([]*models.Trade, error) {
+	var out []*models.Trade
+	for _, t := range m {
+		if t.Code == code {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func TestRecord(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 10, 0, 0, 0, models.KST) }
+	exDate := time.Date(2025, 6, 27, 0, 0, 0, 0, models.KST)
+	trade := func(strategy, side string, qty int64, at time.Time) *models.Trade {
+		return &models.Trade{Strategy: strategy, Code: "069500", Side: side, Quantity: qty, Price: 35000, Time: at}
+	}
+	trades := memTrades{
+		trade("stable", "BUY", 100, day(2)),
+		trade("stable", "BUY", 20, day(20)),
+		trade("stable", "SELL", 30, day(25)),
+		trade("stable", "BUY", 50, day(27)), // 배당락일 매수는 권리가 없다
+		trade("aggressive", "BUY", 10, day(3)),
+		trade("aggressive", "SELL", 10, day(10)), // 배당락 전 전량 매도
+		trade("momentum_rotation", "BUY", 7, day(26)),
+		{Strategy: "stable", Code: "360750", Side: "BUY", Quantity: 99, Price: 15000, Time: day(2)},
+	}
+
+	tests := []struct {
+		name string
+		ev   Event
+		divs []string // "strategy qty gross tax net pay"
+		err  string
+	}{
+		{name: "holders before the ex-date",
+			ev: Event{Code: "069500", ExDate: exDate, PayDate: time.Date(2025, 7, 1, 0, 0, 0, 0, models.KST), PerShare: 333},
+			divs: []string{
+				"momentum_rotation 7 2331 358 1973 2025-07-01",
+				"stable 90 29970 4615 25355 2025-07-01",
+			}},
+		{name: "pay date defaults to the ex-date", ev: Event{Code: "069500", ExDate: exDate, PerShare: 100},
+			divs: []string{
+				"momentum_rotation 7 700 107 593 2025-06-27",
+				"stable 90 9000 1386 7614 2025-06-27",
+			}},
+		{name: "no holders", ev: Event{Code: "005930", ExDate: exDate, PerShare: 361}},
+		{name: "missing per-share amount", ev: Event{Code: "069500", ExDate: exDate}, err: "invalid dividend event"},
+		{name: "missing ex-date", ev: Event{Code: "069500", PerShare: 100}, err: "invalid dividend event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memDividends{}
+			svc := NewService(repo, trades, 0.154)
+
+			out, err := svc.Record(context.Background(), tt.ev, "test.csv")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Record error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, d := range out {
+				got = append(got, fmt.Sprintf("%s %d %.0f %.0f %.0f %s", d.Strategy, d.Quantity, d.Gross, d.Tax, d.Net, d.PayDate.Format("2006-01-02")))
+				if d.Source != "test.csv" || !d.ExDate.Equal(exDate) {
+					t.Errorf("dividend %+v: source/ex-date not kept", d)
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.divs, "|") {
+				t.Errorf("dividends = %v, want %v", got, tt.divs)
+			}
+			if len(repo.saved) != len(out) {
+				t.Errorf("saved %d dividends, returned %d", len(repo.saved), len(out))
+			}
+		})
+	}
+}
+
+func TestPostPaid(t *testing.T) {
+	repo := &memDividends{unposted: []*models.Dividend{{ID: 1, Net: 100}, {ID: 2, Net: 200}}}
+	n, err := NewService(repo, memTrades{}, 0.154).PostPaid(context.Background(), time.Now())
+	if err != nil || n != 2 || fmt.Sprint(repo.posted) != "[1 2]" {
+		t.Errorf("PostPaid = %d %v, posted %v", n, err, repo.posted)
+	}
+
+	// 중간에 실패하면 그때까지 반영한 건수를 돌려준다.
+	repo = &memDividends{unposted: []*models.Dividend{{ID: 1}, {ID: -2}, {ID: 3}}}
+	n, err = NewService(repo, memTrades{}, 0.154).PostPaid(context.Background(), time.Now())
+	if err == nil || n != 1 || fmt.Sprint(repo.posted) != "[1]" {
+		t.Errorf("PostPaid = %d %v, posted %v", n, err, repo.posted)
+	}
+}
+
+func TestPendingReinvestment(t *testing.T) {
+	repo := &memDividends{reinvest: []*models.Dividend{
+		{ID: 1, Strategy: "stable", Net: 25355},
+		{ID: 2, Strategy: "momentum_rotation", Net: 1973},
+		{ID: 3, Strategy: "stable", Net: 7614},
+	}}
+	svc := NewService(repo, memTrades{}, 0.154)
+
+	total, ids, err := svc.PendingReinvestment(context.Background(), "stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 32969 || fmt.Sprint(ids) != "[1 3]" {
+		t.Errorf("PendingReinvestment = %.0f %v, want 32969 [1 3]", total, ids)
+	}
+	if total, ids, _ := svc.PendingReinvestment(context.Background(), "aggressive"); total != 0 || len(ids) != 0 {
+		t.Errorf("PendingReinvestment(aggressive) = %.0f %v, want nothing", total, ids)
+	}
+
+	if err := svc.MarkReinvested(context.Background(), ids); err != nil || fmt.Sprint(repo.reinvested) != "[1 3]" {
+		t.Errorf("MarkReinvested = %v, marked %v", err, repo.reinvested)
+	}
+}
@@ -0,0 +1,102 @@
+// Package dryrun --dry-run 용 가짜 브로커와 저장소.
+// 시세/잔고/과거 체결은 실제 것을 읽고, 주문과 상태 변경은 로그와 dry_run_trades 에만 남긴다.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+// QuoteSource 가상 체결가용 현재가
+type QuoteSource interface {
+	GetQuote(ctx context.Context, code string) (float64, error)
+}
+
+// Broker 주문을 보내지 않고 로그만 남긴다 (execution.Broker, execution.SessionBroker 구현).
+// 주문은 접수 즉시 실제 현재가 (시간외 단일가는 지정가) 로 전량 체결된 것으로 조회된다.
+type Broker struct {
+	quotes QuoteSource
+	mu     sync.Mutex
+	seq    int
+	fills  map[string]*models.OrderFill
+}
+
+func NewBroker(quotes QuoteSource) *Broker {
+	return &Broker{quotes: quotes, fills: map[string]*models.OrderFill{}}
+}
+
+func (b *Broker) Buy(ctx context.Context, code string, quantity int64) (*models.BrokerOrder, error) {
+	logger.Info.Printf("[dry-run] would BUY %s x %d (market)\n", code, quantity)
+	return b.accept(ctx, code, quantity, 0)
+}
+
+func (b *Broker) Sell(ctx context.Context, code string, quantity int64) (*models.BrokerOrder, error) {
+	logger.Info.Printf("[dry-run] would SELL %s x %d (market)\n", code, quantity)
+	return b.accept(ctx, code, quantity, 0)
+}
+
+func (b *Broker) BuySession(ctx context.Context, code string, quantity int64, session string, price float64) (*models.BrokerOrder, error) {
+	logger.Info.Printf("[dry-run] would BUY %s x %d (%s, %.2f)\n", code, quantity, session, price)
+	return b.accept(ctx, code, quantity, price)
+}
+
+func (b *Broker) SellSession(ctx context.Context, code string, quantity int64, session string, price float64) (*models.BrokerOrder, error) {
+	logger.Info.Printf("[dry-run] would SELL %s x %d (%s, %.2f)\n", code, quantity, session, price)
+	return b.accept(ctx, code, quantity, price)
+}
+
+// accept 가상 주문번호를 매기고 체결을 남긴다. price 가 0 이면 현재가.
+func (b *Broker) accept(ctx context.Context, code string, quantity int64, price float64) (*models.BrokerOrder, error) {
+	if price <= 0 {
+		p, err := b.quotes.GetQuote(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		price = p
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	o := &models.BrokerOrder{ID: fmt.Sprintf("dry-%d", b.seq)}
+	b.fills[o.ID] = &models.OrderFill{Ordered: quantity, Filled: quantity, AvgPrice: price}
+	return o, nil
+}
+
+func (b *Broker) GetOrderFill(ctx context.Context, o *models.BrokerOrder) (*models.OrderFill, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	f, ok := b.fills[o.ID]
+	if !ok {
+		return nil, fmt.Errorf("order %s not found", o.ID)
+	}
+	cp := *f
+	return &cp, nil
+}
+
+// tradeRepo 조회는 실제 trades, 기록은 dry_run_trades 로 보낸다.
+type tradeRepo struct {
+	storage.Repository
+	sim   storage.DryRunTradeRepository
+	runID string
+}
+
+// NewRepository 보유 포지션은 실계좌 체결 기준으로 계산하고, 가상 체결은 runID 로 묶어 따로 남긴다.
+func NewRepository(base storage.Repository, sim storage.DryRunTradeRepository, runID string) storage.Repository {
+	return &tradeRepo{Repository: base, sim: sim, runID: runID}
+}
+
+func (r *tradeRepo) InsertTrade(ctx context.Context, t *models.Trade) error {
+	logger.Info.Printf("[dry-run] %s %s %s x %d @ %.2f (run %s)\n", t.Strategy, t.Side, t.Code, t.Quantity, t.Price, r.runID)
+	return r.sim.InsertDryRunTrade(ctx, r.runID, t)
+}
+
+// RunID 실행 단위 식별자 (가상 체결 묶음)
+func RunID(now time.Time) string {
+	return now.In(models.KST).Format("20060102-150405")
+}
@@ -0,0 +1,228 @@
+package dryrun
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+// 아래 저장소들은 실제 DB 를 읽되 쓰기는 메모리에만 남긴다.
+// 같은 실행 안에서는 쓴 값이 보이고, 프로세스가 끝나면 사라진다.
+
+// stateKey 전략별 키 (stops 에서는 key 가 종목코드)
+type stateKey struct{ strategy, key string }
+
+type stateRepo struct {
+	base storage.StateRepository
+	mu   sync.Mutex
+	mem  map[stateKey]string
+}
+
+func NewStateRepository(base storage.StateRepository) storage.StateRepository {
+	return &stateRepo{base: base, mem: map[stateKey]string{}}
+}
+
+func (r *stateRepo) GetState(ctx context.Context, strategy, key string) (string, bool, error) {
+	r.mu.Lock()
+	v, ok := r.mem[stateKey{strategy, key}]
+	r.mu.Unlock()
+	if ok {
+		return v, true, nil
+	}
+	return r.base.GetState(ctx, strategy, key)
+}
+
+func (r *stateRepo) SetState(ctx context.Context, strategy, key, value string) error {
+	logger.Info.Printf("[dry-run] %s state %s=%s (not saved)\n", strategy, key, value)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mem[stateKey{strategy, key}] = value
+	return nil
+}
+
+type carryRepo struct {
+	base storage.CarryRepository
+	mu   sync.Mutex
+	mem  map[string]map[string]float64
+}
+
+func NewCarryRepository(base storage.CarryRepository) storage.CarryRepository {
+	return &carryRepo{base: base, mem: map[string]map[string]float64{}}
+}
+
+func (r *carryRepo) ListCarry(ctx context.Context, strategy string) (map[string]float64, error) {
+	out, err := r.base.ListCarry(ctx, strategy)
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		out = map[string]float64{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for code, amt := range r.mem[strategy] {
+		out[code] = amt
+	}
+	return out, nil
+}
+
+func (r *carryRepo) SetCarry(ctx context.Context, strategy, code string, amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mem[strategy] == nil {
+		r.mem[strategy] = map[string]float64{}
+	}
+	r.mem[strategy][code] = amount
+	return nil
+}
+
+type stopRepo struct {
+	base storage.PositionStopRepository
+	mu   sync.Mutex
+	mem  map[stateKey]*models.PositionStop // nil 값은 삭제
+}
+
+func NewPositionStopRepository(base storage.PositionStopRepository) storage.PositionStopRepository {
+	return &stopRepo{base: base, mem: map[stateKey]*models.PositionStop{}}
+}
+
+func (r *stopRepo) GetStop(ctx context.Context, strategy, code string) (*models.PositionStop, error) {
+	r.mu.Lock()
+	st, ok := r.mem[stateKey{strategy, code}]
+	r.mu.Unlock()
+	if ok {
+		if st == nil {
+			return nil, nil
+		}
+		cp := *st
+		return &cp, nil
+	}
+	return r.base.GetStop(ctx, strategy, code)
+}
+
+func (r *stopRepo) SaveStop(ctx context.Context, st *models.PositionStop) error {
+	cp := *st
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mem[stateKey{st.Strategy, st.Code}] = &cp
+	return nil
+}
+
+func (r *stopRepo) DeleteStop(ctx context.Context, strategy, code string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mem[stateKey{strategy, code}] = nil
+	return nil
+}
+
+func (r *stopRepo) ListStops(ctx context.Context, strategy string) ([]*models.PositionStop, error) {
+	base, err := r.base.ListStops(ctx, strategy)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*models.PositionStop
+	seen := map[string]bool{}
+	for _, st := range base {
+		seen[st.Code] = true
+		if m, ok := r.mem[stateKey{strategy, st.Code}]; ok {
+			st = m
+		}
+		if st != nil {
+			out = append(out, st)
+		}
+	}
+	for k, st := range r.mem {
+		if k.strategy == strategy && !seen[k.key] && st != nil {
+			out = append(out, st)
+		}
+	}
+	return out, nil
+}
+
+// Cooldowns 손절 쿨다운은 기록하지 않고 로그만 남긴다.
+type Cooldowns struct{}
+
+func (Cooldowns) AddCooldown(ctx context.Context, code string, until time.Time, reason string) error {
+	logger.Info.Printf("[dry-run] cooldown %s until %s (%s, not saved)\n", code, until.Format("2006-01-02"), reason)
+	return nil
+}
+
+// DividendLedger 재투자할 배당은 실제 원장에서 읽고, 재투자 표시는 남기지 않는다.
+type DividendLedger interface {
+	PendingReinvestment(ctx context.Context, strategy string) (float64, []int64, error)
+	MarkReinvested(ctx context.Context, ids []int64) error
+}
+
+type dividends struct {
+	base DividendLedger
+}
+
+func NewDividendLedger(base DividendLedger) DividendLedger {
+	return dividends{base: base}
+}
+
+func (d dividends) PendingReinvestment(ctx context.Context, strategy string) (float64, []int64, error) {
+	return d.base.PendingReinvestment(ctx, strategy)
+}
+
+func (d dividends) MarkReinvested(ctx context.Context, ids []int64) error {
+	logger.Info.Printf("[dry-run] dividends %v reinvested (not saved)\n", ids)
+	return nil
+}
+
+// pnlRepo 손실 한도 계산용 평가금액 기록. 실제 기록을 읽고, 이번 실행의 평가금액은 메모리에만 둔다.
+type pnlRepo struct {
+	base storage.PnLRepository
+	mu   sync.Mutex
+	mem  map[string]*models.DailyPnL // 날짜 → 기록
+}
+
+func NewPnLRepository(base storage.PnLRepository) storage.PnLRepository {
+	return &pnlRepo{base: base, mem: map[string]*models.DailyPnL{}}
+}
+
+func (r *pnlRepo) GetDailyPnL(ctx context.Context, date time.Time) (*models.DailyPnL, error) {
+	r.mu.Lock()
+	p, ok := r.mem[storage.FormatDate(date)]
+	r.mu.Unlock()
+	if ok {
+		cp := *p
+		return &cp, nil
+	}
+	return r.base.GetDailyPnL(ctx, date)
+}
+
+func (r *pnlRepo) LastDailyPnLBefore(ctx context.Context, date time.Time) (*models.DailyPnL, error) {
+	return r.base.LastDailyPnLBefore(ctx, date)
+}
+
+func (r *pnlRepo) PeakEquity(ctx context.Context) (float64, error) {
+	peak, err := r.base.PeakEquity(ctx)
+	if err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.mem {
+		peak = max(peak, p.Equity)
+	}
+	return peak, nil
+}
+
+func (r *pnlRepo) SaveDailyPnL(ctx context.Context, p *models.DailyPnL) error {
+	cp := *p
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mem[storage.FormatDate(p.Date)] = &cp
+	return nil
+}
+
+func (r *pnlRepo) ListDailyPnL(ctx context.Context, limit int) ([]*models.DailyPnL, error) {
+	return r.base.ListDailyPnL(ctx, limit)
+}
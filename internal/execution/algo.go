@@ -0,0 +1,303 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+// LimitBroker 지정가 주문/취소/체결 조회/호가 (kis.Client 가 구현)
+type LimitBroker interface {
+	BuyLimit(ctx context.Context, code string, quantity int64, price float64) (*models.BrokerOrder, error)
+	SellLimit(ctx context.Context, code string, quantity int64, price float64) (*models.BrokerOrder, error)
+	CancelOrder(ctx context.Context, o *models.BrokerOrder) error
+	GetOrderFill(ctx context.Context, o *models.BrokerOrder) (*models.OrderFill, error)
+	GetBestQuote(ctx context.Context, code string) (bid, ask float64, err error)
+}
+
+// 실행 알고리즘 종류
+const (
+	AlgoTWAP = "twap" // 창을 균등 분할해 구간마다 최우선 반대 호가 지정가
+	AlgoPeg  = "peg"  // 같은 편 최우선 호가에 지정가를 걸고 호가가 바뀌면 취소/재주문
+)
+
+// AlgoConfig 부모 주문 실행 방식
+type AlgoConfig struct {
+	Kind   string        // twap | peg
+	Window time.Duration // 부모 주문 전체 실행 시간
+	Slices int           // twap 자식 주문 수
+	// RepegInterval peg 에서 체결/호가를 다시 확인하는 간격
+	RepegInterval time.Duration
+	// SweepRemainder 창이 끝났을 때 남은 수량을 시장가로 정리
+	SweepRemainder bool
+}
+
+// ParentOrder 알고리즘으로 나눠 낼 원 주문
+type ParentOrder struct {
+	Strategy, Code, Side string
+	Quantity             int64
+}
+
+// AlgoReport 부모 주문 실행 결과. SlippageBps 는 도착가 대비 불리한 방향이 양수.
+type AlgoReport struct {
+	Filled       int64
+	AvgPrice     float64
+	ArrivalPrice float64
+	SlippageBps  float64
+	Children     int
+	Canceled     int
+}
+
+func (r *AlgoReport) add(qty int64, price float64) {
+	if qty <= 0 {
+		return
+	}
+	r.AvgPrice = (r.AvgPrice*float64(r.Filled) + price*float64(qty)) / float64(r.Filled+qty)
+	r.Filled += qty
+}
+
+// Algo 부모 주문을 자식 지정가 주문으로 나눠 실행한다.
+type Algo struct {
+	broker LimitBroker
+	market Broker // 잔량 시장가 정리용
+	cfg    AlgoConfig
+
+	// wait 테스트/시뮬레이션에서 바꿔 끼울 수 있는 대기 함수
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+func NewAlgo(broker LimitBroker, market Broker, cfg AlgoConfig) (*Algo, error) {
+	switch cfg.Kind {
+	case AlgoTWAP, AlgoPeg:
+	default:
+		return nil, fmt.Errorf("unknown execution algo %q (twap|peg)", cfg.Kind)
+	}
+	if cfg.Window <= 0 {
+		return nil, fmt.Errorf("execution algo window must be positive")
+	}
+	if cfg.Slices <= 0 {
+		cfg.Slices = 1
+	}
+	if cfg.RepegInterval <= 0 {
+		cfg.RepegInterval = time.Minute
+	}
+	return &Algo{broker: broker, market: market, cfg: cfg, wait: sleepCtx}, nil
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Run 부모 주문을 실행한다. 일부만 체결돼도 report 는 채워서 반환한다.
+func (a *Algo) Run(ctx context.Context, p ParentOrder) (*AlgoReport, error) {
+	tag := "[" + p.Strategy + "]"
+	bid, ask, err := a.broker.GetBestQuote(ctx, p.Code)
+	if err != nil {
+		return &AlgoReport{}, fmt.Errorf("arrival quote: %w", err)
+	}
+	rep := &AlgoReport{ArrivalPrice: mid(bid, ask)}
+	logger.Info.Printf("%s algo %s %s %s x %d start (arrival=%.2f bid=%.2f ask=%.2f window=%s)\n",
+		tag, a.cfg.Kind, p.Side, p.Code, p.Quantity, rep.ArrivalPrice, bid, ask, a.cfg.Window)
+
+	if a.cfg.Kind == AlgoTWAP {
+		err = a.twap(ctx, p, rep)
+	} else {
+		err = a.peg(ctx, p, rep)
+	}
+
+	// 창이 끝나고 남은 수량은 설정에 따라 시장가로 정리한다. 중단(ctx) 시에는 정리하지 않는다.
+	if rest := p.Quantity - rep.Filled; rest > 0 && err == nil && a.cfg.SweepRemainder {
+		err = a.sweep(ctx, p, rest, rep)
+	}
+
+	if rep.Filled > 0 && rep.ArrivalPrice > 0 {
+		rep.SlippageBps = (rep.AvgPrice - rep.ArrivalPrice) / rep.ArrivalPrice * 1e4
+		if p.Side == "SELL" {
+			rep.SlippageBps = -rep.SlippageBps
+		}
+	}
+	logger.Info.Printf("%s algo %s %s filled %d/%d avg=%.2f arrival=%.2f slippage=%.1fbp (children=%d canceled=%d)\n",
+		tag, p.Side, p.Code, rep.Filled, p.Quantity, rep.AvgPrice, rep.ArrivalPrice, rep.SlippageBps, rep.Children, rep.Canceled)
+	if err == nil && rep.Filled < p.Quantity {
+		err = fmt.Errorf("partially filled %d/%d", rep.Filled, p.Quantity)
+	}
+	return rep, err
+}
+
+// twap 구간마다 누적 목표 수량(Q × i/N)까지 부족한 만큼 최우선 반대 호가로 지정가를 내고,
+// 구간이 끝나면 미체결 잔량을 취소해 다음 구간 목표에 넘긴다.
+func (a *Algo) twap(ctx context.Context, p ParentOrder, rep *AlgoReport) error {
+	n := int64(a.cfg.Slices)
+	interval := a.cfg.Window / time.Duration(n)
+	for i := int64(1); i <= n; i++ {
+		target := (p.Quantity*i + n - 1) / n
+		qty := target - rep.Filled
+		if qty > 0 {
+			bid, ask, err := a.broker.GetBestQuote(ctx, p.Code)
+			if err != nil {
+				return fmt.Errorf("quote: %w", err)
+			}
+			price := ask
+			if p.Side == "SELL" {
+				price = bid
+			}
+			child, err := a.place(ctx, p, qty, price, rep)
+			if err != nil {
+				return err
+			}
+			if err := a.wait(ctx, interval); err != nil {
+				a.finish(context.WithoutCancel(ctx), p, child, rep)
+				return err
+			}
+			if err := a.finish(ctx, p, child, rep); err != nil {
+				return err
+			}
+		} else if err := a.wait(ctx, interval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// peg 같은 편 최우선 호가(매수는 bid, 매도는 ask)에 잔량 전부를 걸어 두고,
+// RepegInterval 마다 체결을 확인해 호가가 움직였으면 취소 후 새 호가로 다시 낸다.
+func (a *Algo) peg(ctx context.Context, p ParentOrder, rep *AlgoReport) error {
+	deadline := time.Now().Add(a.cfg.Window)
+	var (
+		child    *models.BrokerOrder
+		pegPrice float64
+		before   int64 // 현재 자식 주문 이전까지의 체결 수량
+	)
+	for {
+		bid, ask, err := a.broker.GetBestQuote(ctx, p.Code)
+		if err != nil {
+			err = fmt.Errorf("quote: %w", err)
+		}
+		price := bid
+		if p.Side == "SELL" {
+			price = ask
+		}
+
+		if child != nil && (err != nil || price != pegPrice || !time.Now().Before(deadline)) {
+			if ferr := a.finish(ctx, p, child, rep); ferr != nil {
+				return ferr
+			}
+			child, before = nil, rep.Filled
+		} else if child != nil {
+			// 호가가 그대로면 체결만 확인 (완전 체결이면 종료)
+			fill, ferr := a.broker.GetOrderFill(ctx, child)
+			if ferr == nil && before+fill.Filled >= p.Quantity {
+				if ferr := a.finish(ctx, p, child, rep); ferr != nil {
+					return ferr
+				}
+				return nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if rep.Filled >= p.Quantity || !time.Now().Before(deadline) {
+			return nil
+		}
+
+		if child == nil {
+			if child, err = a.place(ctx, p, p.Quantity-rep.Filled, price, rep); err != nil {
+				return err
+			}
+			pegPrice = price
+		}
+
+		wait := min(a.cfg.RepegInterval, time.Until(deadline))
+		if err := a.wait(ctx, max(wait, 0)); err != nil {
+			a.finish(context.WithoutCancel(ctx), p, child, rep)
+			return err
+		}
+	}
+}
+
+// place 자식 지정가 주문 제출
+func (a *Algo) place(ctx context.Context, p ParentOrder, qty int64, price float64, rep *AlgoReport) (*models.BrokerOrder, error) {
+	if price <= 0 {
+		return nil, fmt.Errorf("invalid limit price %.2f for %s", price, p.Code)
+	}
+	var (
+		o   *models.BrokerOrder
+		err error
+	)
+	if p.Side == "SELL" {
+		o, err = a.broker.SellLimit(ctx, p.Code, qty, price)
+	} else {
+		o, err = a.broker.BuyLimit(ctx, p.Code, qty, price)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("child order: %w", err)
+	}
+	rep.Children++
+	logger.Info.Printf("[%s] algo child %s %s x %d @ %.2f (ODNO=%s)\n", p.Strategy, p.Side, p.Code, qty, price, o.ID)
+	return o, nil
+}
+
+// finish 자식 주문의 미체결 잔량을 취소하고 최종 체결 수량을 report 에 더한다.
+// 체결 조회가 실패해도 주문이 남지 않도록 취소를 먼저 하고, 취소 직전 체결분까지 보도록 그 뒤에 조회한다.
+func (a *Algo) finish(ctx context.Context, p ParentOrder, o *models.BrokerOrder, rep *AlgoReport) error {
+	if err := a.broker.CancelOrder(ctx, o); err != nil {
+		// 이미 전량 체결된 주문은 취소가 거부될 수 있다. 아래 조회 결과를 따른다.
+		logger.Info.Printf("[%s] algo cancel %s: %v\n", p.Strategy, o.ID, err)
+	} else {
+		rep.Canceled++
+	}
+	fill, err := a.broker.GetOrderFill(ctx, o)
+	if err != nil {
+		return fmt.Errorf("fill %s: %w", o.ID, err)
+	}
+	rep.add(fill.Filled, fill.AvgPrice)
+	return nil
+}
+
+// sweep 남은 수량 시장가 정리. 체결 조회로 확인한 수량/평균가만 report 에 더한다.
+func (a *Algo) sweep(ctx context.Context, p ParentOrder, qty int64, rep *AlgoReport) error {
+	if a.market == nil {
+		return errors.New("no market broker for sweep")
+	}
+	var (
+		o   *models.BrokerOrder
+		err error
+	)
+	if p.Side == "SELL" {
+		o, err = a.market.Sell(ctx, p.Code, qty)
+	} else {
+		o, err = a.market.Buy(ctx, p.Code, qty)
+	}
+	if err != nil {
+		return fmt.Errorf("sweep: %w", err)
+	}
+	fill, err := awaitFill(ctx, a.market, o, qty, DefaultFillTimeout, a.wait)
+	if err != nil {
+		return fmt.Errorf("sweep: %w", err)
+	}
+	logger.Info.Printf("[%s] algo sweep %s %s x %d/%d at market (avg %.2f)\n", p.Strategy, p.Side, p.Code, fill.Filled, qty, fill.AvgPrice)
+	rep.add(min(fill.Filled, qty), fill.AvgPrice)
+	return nil
+}
+
+func mid(bid, ask float64) float64 {
+	switch {
+	case bid > 0 && ask > 0:
+		return (bid + ask) / 2
+	case bid > 0:
+		return bid
+	default:
+		return ask
+	}
+}
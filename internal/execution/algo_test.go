@@ -0,0 +1,257 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// fakeChild 가짜 자식 주문. filled 는 조회 시 보이는 체결 수량.
+type fakeChild struct {
+	qty, filled int64
+	price       float64
+	canceled    bool
+}
+
+// fakeLimitBroker 호가를 순서대로 돌려주고, 자식 주문마다 fills[n] 만큼 체결된 것으로 보인다.
+type fakeLimitBroker struct {
+	quotes   [][2]float64 // bid, ask (마지막 값 반복)
+	fills    []int64      // n 번째 자식 주문의 체결 수량 (없으면 0)
+	fillErrs int          // 처음 몇 번의 체결 조회를 실패시킬지
+	children []*fakeChild
+	calls    []string
+}
+
+func (b *fakeLimitBroker) GetBestQuote(context.Context, string) (float64, float64, error) {
+	q := b.quotes[0]
+	if len(b.quotes) > 1 {
+		b.quotes = b.quotes[1:]
+	}
+	return q[0], q[1], nil
+}
+
+func (b *fakeLimitBroker) place(qty int64, price float64) (*models.BrokerOrder, error) {
+	c := &fakeChild{qty: qty, price: price}
+	if n := len(b.children); n < len(b.fills) {
+		c.filled = min(b.fills[n], qty)
+	}
+	b.children = append(b.children, c)
+	id := fmt.Sprint(len(b.children))
+	b.calls = append(b.calls, "place "+id)
+	return &models.BrokerOrder{ID: id}, nil
+}
+
+func (b *fakeLimitBroker) BuyLimit(_ context.Context, _ string, qty int64, price float64) (*models.BrokerOrder, error) {
+	return b.place(qty, price)
+}
+
+func (b *fakeLimitBroker) SellLimit(_ context.Context, _ string, qty int64, price float64) (*models.BrokerOrder, error) {
+	return b.place(qty, price)
+}
+
+func (b *fakeLimitBroker) child(o *models.BrokerOrder) *fakeChild {
+	var n int
+	fmt.Sscan(o.ID, &n)
+	return b.children[n-1]
+}
+
+func (b *fakeLimitBroker) CancelOrder(_ context.Context, o *models.BrokerOrder) error {
+	b.calls = append(b.calls, "cancel "+o.ID)
+	c := b.child(o)
+	if c.filled >= c.qty {
+		return errors.New("already filled")
+	}
+	c.canceled = true
+	return nil
+}
+
+func (b *fakeLimitBroker) GetOrderFill(_ context.Context, o *models.BrokerOrder) (*models.OrderFill, error) {
+	b.calls = append(b.calls, "fill "+o.ID)
+	if b.fillErrs > 0 {
+		b.fillErrs--
+		return nil, errors.New("inquiry failed")
+	}
+	c := b.child(o)
+	f := &models.OrderFill{Ordered: c.qty, Filled: c.filled}
+	if c.filled > 0 {
+		f.AvgPrice = c.price
+	}
+	return f, nil
+}
+
+// fakeMarketBroker 시장가 주문은 price 에 바로 전량 체결된다.
+type fakeMarketBroker struct {
+	price float64
+	qty   int64
+}
+
+func (m *fakeMarketBroker) Buy(_ context.Context, _ string, qty int64) (*models.BrokerOrder, error) {
+	m.qty = qty
+	return &models.BrokerOrder{ID: "m"}, nil
+}
+
+func (m *fakeMarketBroker) Sell(_ context.Context, _ string, qty int64) (*models.BrokerOrder, error) {
+	m.qty = qty
+	return &models.BrokerOrder{ID: "m"}, nil
+}
+
+func (m *fakeMarketBroker) GetOrderFill(context.Context, *models.BrokerOrder) (*models.OrderFill, error) {
+	return &models.OrderFill{Ordered: m.qty, Filled: m.qty, AvgPrice: m.price}, nil
+}
+
+func TestTWAP(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     string
+		fills    []int64
+		sweep    bool
+		children []int64 // 자식 주문 수량
+		filled   int64
+		canceled int
+		avg      float64
+		slippage float64
+		err      string
+	}{
+		{name: "even slices", side: "BUY", fills: []int64{4, 3, 3}, children: []int64{4, 3, 3},
+			filled: 10, avg: 101, slippage: 100},
+		{name: "shortfall rolls into the next slice", side: "BUY", fills: []int64{2, 5, 3}, children: []int64{4, 5, 3},
+			filled: 10, canceled: 1, avg: 101, slippage: 100},
+		{name: "sell crosses the bid", side: "SELL", fills: []int64{4, 3, 3}, children: []int64{4, 3, 3},
+			filled: 10, avg: 99, slippage: 100},
+		{name: "remainder swept at market", side: "BUY", fills: []int64{4}, sweep: true, children: []int64{4, 3, 6},
+			filled: 10, canceled: 2, avg: 101.6, slippage: 160},
+		{name: "remainder left without sweep", side: "BUY", fills: []int64{4}, children: []int64{4, 3, 6},
+			filled: 4, canceled: 2, avg: 101, slippage: 100, err: "partially filled 4/10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := &fakeLimitBroker{quotes: [][2]float64{{99, 101}}, fills: tt.fills}
+			market := &fakeMarketBroker{price: 102}
+			algo, err := NewAlgo(broker, market, AlgoConfig{Kind: AlgoTWAP, Window: 3 * time.Minute, Slices: 3, SweepRemainder: tt.sweep})
+			if err != nil {
+				t.Fatal(err)
+			}
+			algo.wait = func(context.Context, time.Duration) error { return nil }
+
+			rep, err := algo.Run(context.Background(), ParentOrder{Strategy: "stable", Code: "A", Side: tt.side, Quantity: 10})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Run error = %v, want %q", err, tt.err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			var children []int64
+			for _, c := range broker.children {
+				children = append(children, c.qty)
+			}
+			if fmt.Sprint(children) != fmt.Sprint(tt.children) {
+				t.Errorf("children = %v, want %v", children, tt.children)
+			}
+			if rep.Filled != tt.filled || rep.Canceled != tt.canceled || math.Abs(rep.AvgPrice-tt.avg) > 1e-9 ||
+				math.Abs(rep.SlippageBps-tt.slippage) > 1e-6 {
+				t.Errorf("report = %+v, want filled=%d canceled=%d avg=%.2f slippage=%.1f",
+					rep, tt.filled, tt.canceled, tt.avg, tt.slippage)
+			}
+		})
+	}
+}
+
+func TestTWAPInterrupted(t *testing.T) {
+	broker := &fakeLimitBroker{quotes: [][2]float64{{99, 101}}, fills: []int64{1}}
+	market := &fakeMarketBroker{price: 102}
+	algo, err := NewAlgo(broker, market, AlgoConfig{Kind: AlgoTWAP, Window: 3 * time.Minute, Slices: 3, SweepRemainder: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	algo.wait = func(ctx context.Context, _ time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+
+	rep, err := algo.Run(ctx, ParentOrder{Strategy: "stable", Code: "A", Side: "BUY", Quantity: 10})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run error = %v, want context.Canceled", err)
+	}
+	// 중단돼도 자식 주문은 취소하고 체결분은 남기며, 잔량은 시장가로 정리하지 않는다.
+	if got := strings.Join(broker.calls, ","); got != "place 1,cancel 1,fill 1" {
+		t.Errorf("calls = %s", got)
+	}
+	if rep.Filled != 1 || rep.Canceled != 1 || market.qty != 0 {
+		t.Errorf("report = %+v, swept %d", rep, market.qty)
+	}
+}
+
+func TestPeg(t *testing.T) {
+	// 도착가 100 → 99 에 매수 대기 → bid 가 101 로 오르면 취소 후 101 에 다시 걸고 전량 체결
+	broker := &fakeLimitBroker{
+		quotes: [][2]float64{{99, 101}, {99, 101}, {101, 103}},
+		fills:  []int64{0, 10},
+	}
+	algo, err := NewAlgo(broker, nil, AlgoConfig{Kind: AlgoPeg, Window: time.Hour, RepegInterval: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	algo.wait = func(context.Context, time.Duration) error { return nil }
+
+	rep, err := algo.Run(context.Background(), ParentOrder{Strategy: "aggressive", Code: "A", Side: "BUY", Quantity: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(broker.children) != 2 || broker.children[0].price != 99 || !broker.children[0].canceled || broker.children[1].price != 101 {
+		t.Fatalf("children = %+v %+v", broker.children[0], broker.children[1])
+	}
+	if rep.Filled != 10 || rep.AvgPrice != 101 || rep.Children != 2 || rep.Canceled != 1 || math.Abs(rep.SlippageBps-100) > 1e-6 {
+		t.Errorf("report = %+v", rep)
+	}
+}
+
+func TestFinish(t *testing.T) {
+	tests := []struct {
+		name     string
+		fill     int64
+		fillErrs int
+		calls    string
+		filled   int64
+		canceled int
+		err      bool
+	}{
+		{name: "partial fill is canceled then counted", fill: 3, calls: "cancel 1,fill 1", filled: 3, canceled: 1},
+		{name: "full fill ignores the cancel rejection", fill: 5, calls: "cancel 1,fill 1", filled: 5},
+		// 조회가 실패해도 미체결 잔량이 장에 남지 않는다.
+		{name: "fill inquiry fails after cancel", fill: 3, fillErrs: 1, calls: "cancel 1,fill 1", canceled: 1, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := &fakeLimitBroker{quotes: [][2]float64{{99, 101}}, fills: []int64{tt.fill}}
+			algo, err := NewAlgo(broker, nil, AlgoConfig{Kind: AlgoTWAP, Window: time.Minute})
+			if err != nil {
+				t.Fatal(err)
+			}
+			o, _ := broker.BuyLimit(context.Background(), "A", 5, 101)
+			broker.calls, broker.fillErrs = nil, tt.fillErrs
+
+			rep := &AlgoReport{}
+			err = algo.finish(context.Background(), ParentOrder{Strategy: "stable", Code: "A", Side: "BUY", Quantity: 5}, o, rep)
+			if (err != nil) != tt.err {
+				t.Fatalf("finish error = %v, want error %v", err, tt.err)
+			}
+			if got := strings.Join(broker.calls, ","); got != tt.calls {
+				t.Errorf("calls = %s, want %s", got, tt.calls)
+			}
+			if rep.Filled != tt.filled || rep.Canceled != tt.canceled {
+				t.Errorf("report = %+v, want filled=%d canceled=%d", rep, tt.filled, tt.canceled)
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/risk"
+	"stock-investing/pkg/logger"
+)
+
+// Broker 시장가 주문 제출과 체결 조회 (kis.Client 가 구현). 접수된 주문은 체결을 확인한 수량만 기록한다.
+type Broker interface {
+	Buy(ctx context.Context, code string, quantity int64) (*models.BrokerOrder, error)
+	Sell(ctx context.Context, code string, quantity int64) (*models.BrokerOrder, error)
+	FillSource
+}
+
+// SessionBroker 정규장 외 세션(동시호가/시간외) 주문 (kis.Client 가 구현)
+type SessionBroker interface {
+	BuySession(ctx context.Context, code string, quantity int64, session string, price float64) (*models.BrokerOrder, error)
+	SellSession(ctx context.Context, code string, quantity int64, session string, price float64) (*models.BrokerOrder, error)
+}
+
+// QuoteSource 수량 계산용 현재가
+type QuoteSource interface {
+	GetQuote(ctx context.Context, code string) (float64, error)
+}
+
+// TradeStore 체결 기록 (storage.Repository 가 구현)
+type TradeStore interface {
+	InsertTrade(ctx context.Context, t *models.Trade) error
+	ListTradesByStrategy(ctx context.Context, strategy string) ([]*models.Trade, error)
+}
+
+// PositionSource 전략별 보유 포지션 (position.Service 가 구현)
+type PositionSource interface {
+	Positions(ctx context.Context, strategy string) ([]*models.Position, error)
+}
+
+// OrderStore 주문 의도/결과 기록 (storage.OrderRepository 가 구현)
+type OrderStore interface {
+	InsertOrder(ctx context.Context, o *models.Order) error
+}
+
+// Executor 전략이 낸 주문 의도를 수량 확정 → 상계 → 리스크 체크 → 제출 → 기록 순으로 처리한다.
+type Executor struct {
+	broker Broker
+	quotes QuoteSource
+	risk   risk.Manager
+	trades TradeStore
+	orders OrderStore // nil 이면 주문 기록을 남기지 않는다.
+	// positions nil 이면 보유 수량을 체결 기록에서 바로 계산한다.
+	positions PositionSource
+
+	// 종목당 비중 체크를 하지 않는 전략 (지수 ETF 처럼 자체로 분산된 슬리브)
+	noPositionCheck map[string]bool
+
+	// algo 가 있으면 algoMinValue 이상 주문은 시장가 대신 TWAP/호가 추종으로 나눠 낸다.
+	algo         *Algo
+	algoMinValue float64
+
+	// 제출 후 체결을 기다리는 시간. 지나면 잔량을 취소하고 체결된 만큼만 기록한다.
+	fillTimeout time.Duration
+	sessionWait time.Duration
+	// wait 테스트에서 바꿔 끼울 수 있는 대기 함수
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+func NewExecutor(broker Broker, quotes QuoteSource, riskMgr risk.Manager, trades TradeStore, orders OrderStore) *Executor {
+	return &Executor{
+		broker: broker, quotes: quotes, risk: riskMgr, trades: trades, orders: orders, noPositionCheck: map[string]bool{},
+		fillTimeout: DefaultFillTimeout, sessionWait: DefaultSessionWait, wait: sleepCtx,
+	}
+}
+
+// ExemptPositionCheck 지정한 전략의 매수에는 risk.CheckPositionSize 를 적용하지 않는다.
+func (e *Executor) ExemptPositionCheck(strategies ...string) *Executor {
+	for _, s := range strategies {
+		e.noPositionCheck[s] = true
+	}
+	return e
+}
+
+// WithPositions 매도 수량 제한/종목당 비중 체크에 쓸 보유 포지션을 positions 에서 읽는다.
+func (e *Executor) WithPositions(positions PositionSource) *Executor {
+	e.positions = positions
+	return e
+}
+
+// WithFillWait 시장가 / 세션 주문의 체결 대기 시간 (0 이면 기본값 유지)
+func (e *Executor) WithFillWait(market, session time.Duration) *Executor {
+	if market > 0 {
+		e.fillTimeout = market
+	}
+	if session > 0 {
+		e.sessionWait = session
+	}
+	return e
+}
+
+// WithAlgo 주문 금액이 minValue 이상이면 algo 로 실행한다.
+func (e *Executor) WithAlgo(algo *Algo, minValue float64) *Executor {
+	e.algo = algo
+	e.algoMinValue = minValue
+	return e
+}
+
+type groupKey struct {
+	strategy, code, session string
+}
+
+// Execute intents 를 처리하고 같은 순서로 결과를 반환한다.
+// 같은 전략·종목의 매수/매도는 상계해 순수량만 남기고, 다른 전략끼리의 같은 종목 반대 주문은
+// 내부 체결(양쪽 모두 체결 기록)한 뒤 나머지만 제출한다. 매도를 매수보다 먼저 제출한다.
+func (e *Executor) Execute(ctx context.Context, equity float64, intents []models.OrderIntent) []*models.Order {
+	out := make([]*models.Order, len(intents))
+	now := time.Now()
+
+	// 1) 가격/수량 확정
+	groups := map[groupKey][]int{}
+	var keys []groupKey
+	for i, in := range intents {
+		o := &models.Order{Intent: in, CreatedAt: now}
+		out[i] = o
+
+		price := in.LimitPrice
+		if price <= 0 {
+			p, err := e.quotes.GetQuote(ctx, in.Code)
+			if err != nil {
+				e.reject(o, models.OrderFailed, fmt.Sprintf("quote: %v", err))
+				continue
+			}
+			price = p
+		}
+		if price <= 0 {
+			e.reject(o, models.OrderRejected, fmt.Sprintf("invalid price %.2f", price))
+			continue
+		}
+		o.Price = price
+		o.Quantity = in.Quantity
+		if o.Quantity <= 0 {
+			o.Quantity = int64(math.Floor(in.TargetValue / price))
+		}
+		if o.Quantity <= 0 {
+			e.reject(o, models.OrderRejected, fmt.Sprintf("amount too small (target=%.0f, price=%.2f)", in.TargetValue, price))
+			continue
+		}
+
+		k := groupKey{in.Strategy, in.Code, in.Session}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], i)
+	}
+
+	// 2) 상계 후 순주문 구성 + 보유 수량/리스크 확인
+	var subs []*submission
+	for _, k := range keys {
+		sub, ok := e.net(out, groups[k])
+		if !ok {
+			continue
+		}
+		if e.prepare(ctx, equity, out, &sub) {
+			subs = append(subs, &sub)
+		}
+	}
+
+	// 3) 서로 다른 전략의 같은 종목 반대 주문은 내부에서 맞바꾸고 남는 수량만 제출한다.
+	crossInternally(subs)
+
+	// 4) 매도 → 매수 순 제출
+	for _, side := range []string{"SELL", "BUY"} {
+		for _, sub := range subs {
+			if sub.side != side {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				e.complete(ctx, out, sub, sub.crossed, ctx.Err().Error())
+				continue
+			default:
+			}
+			e.submit(ctx, out, sub)
+		}
+	}
+
+	for _, o := range out {
+		e.record(ctx, o)
+	}
+	return out
+}
+
+// crossInternally 종목별로 매도 전략과 매수 전략의 수량을 앞에서부터 맞바꾼다.
+func crossInternally(subs []*submission) {
+	byCode := map[string][]*submission{}
+	for _, sub := range subs {
+		byCode[sub.code] = append(byCode[sub.code], sub)
+	}
+	for code, group := range byCode {
+		var sells, buys []*submission
+		for _, sub := range group {
+			if sub.side == "SELL" {
+				sells = append(sells, sub)
+			} else {
+				buys = append(buys, sub)
+			}
+		}
+		for _, sl := range sells {
+			for _, b := range buys {
+				q := min(sl.qty-sl.crossed, b.qty-b.crossed)
+				if q <= 0 {
+					continue
+				}
+				sl.crossed += q
+				b.crossed += q
+				logger.Info.Printf("[execution] crossed %s x %d: %s SELL <-> %s BUY\n", code, q, sl.strategy, b.strategy)
+			}
+		}
+	}
+}
+
+// submission 한 전략·종목의 순주문
+type submission struct {
+	strategy, code, side string
+	qty                  int64
+	price                float64
+	idx                  []int // 이 주문에 포함된 intent 인덱스
+	crossed              int64 // 다른 전략의 반대 주문과 내부 체결된 수량
+	arrival              float64
+	session              string // 비어 있으면 정규장
+}
+
+// net 같은 전략·종목 주문을 상계한다. 전부 상계되면 ok=false.
+func (e *Executor) net(out []*models.Order, idx []int) (submission, bool) {
+	var buyQty, sellQty int64
+	for _, i := range idx {
+		if out[i].Intent.Side == "SELL" {
+			sellQty += out[i].Quantity
+		} else {
+			buyQty += out[i].Quantity
+		}
+	}
+	first := out[idx[0]].Intent
+	sub := submission{strategy: first.Strategy, code: first.Code, session: first.Session}
+
+	side, netQty := "BUY", buyQty-sellQty
+	if netQty < 0 {
+		side, netQty = "SELL", -netQty
+	}
+	// 반대편 주문은 상계 처리, 같은 편은 앞에서부터 남은 수량을 배정
+	remaining := netQty
+	for _, i := range idx {
+		o := out[i]
+		if netQty == 0 || o.Intent.Side != side {
+			if buyQty > 0 && sellQty > 0 {
+				e.reject(o, models.OrderNetted, "netted against opposite order")
+			}
+			continue
+		}
+		if o.Quantity > remaining {
+			o.Quantity = remaining
+		}
+		remaining -= o.Quantity
+		if o.Quantity == 0 {
+			e.reject(o, models.OrderNetted, "netted against opposite order")
+			continue
+		}
+		sub.price = o.Price
+		sub.idx = append(sub.idx, i)
+	}
+	if netQty == 0 || len(sub.idx) == 0 {
+		return sub, false
+	}
+	sub.side = side
+	sub.qty = netQty
+	return sub, true
+}
+
+// prepare 매도는 보유 수량까지로 줄이고, 매수는 종목당 비중(기존 보유분 포함)을 확인한다.
+// 통과하지 못하면 intent 들을 rejected/failed 로 표시하고 false.
+func (e *Executor) prepare(ctx context.Context, equity float64, out []*models.Order, sub *submission) bool {
+	tag := "[" + sub.strategy + "]"
+	fail := func(status, msg string) bool {
+		logger.Error.Printf("%s %s %s x %d %s: %s\n", tag, sub.side, sub.code, sub.qty, status, msg)
+		for _, i := range sub.idx {
+			e.reject(out[i], status, msg)
+		}
+		return false
+	}
+
+	positions, err := e.heldPositions(ctx, sub.strategy)
+	if err != nil {
+		return fail(models.OrderFailed, fmt.Sprintf("positions: %v", err))
+	}
+	var held *models.Position
+	for _, p := range positions {
+		if p.Code == sub.code {
+			held = p
+		}
+	}
+
+	if sub.side == "SELL" {
+		// 보유 수량을 넘는 매도는 보유분까지만
+		if held == nil || held.Quantity <= 0 {
+			return fail(models.OrderRejected, "no position to sell")
+		}
+		if sub.qty > held.Quantity {
+			logger.Info.Printf("%s sell %s capped to held quantity %d (wanted %d)\n", tag, sub.code, held.Quantity, sub.qty)
+			e.shrink(out, sub.idx, sub.qty-held.Quantity)
+			sub.qty = held.Quantity
+		}
+		return true
+	}
+
+	if !e.noPositionCheck[sub.strategy] {
+		existing := 0.0
+		if held != nil {
+			existing = held.AvgPrice * float64(held.Quantity)
+		}
+		if err := e.risk.CheckPositionSize(ctx, equity, existing+float64(sub.qty)*sub.price); err != nil {
+			return fail(models.OrderRejected, fmt.Sprintf("risk: %v", err))
+		}
+	}
+	return true
+}
+
+func (e *Executor) heldPositions(ctx context.Context, strategy string) ([]*models.Position, error) {
+	if e.positions != nil {
+		return e.positions.Positions(ctx, strategy)
+	}
+	trades, err := e.trades.ListTradesByStrategy(ctx, strategy)
+	if err != nil {
+		return nil, err
+	}
+	return PositionsFromTrades(strategy, trades), nil
+}
+
+// submit 내부 체결분을 뺀 나머지를 브로커에 제출하고 결과를 기록한다.
+func (e *Executor) submit(ctx context.Context, out []*models.Order, sub *submission) {
+	rest := sub.qty - sub.crossed
+	if rest <= 0 {
+		e.complete(ctx, out, sub, sub.crossed, "")
+		return
+	}
+
+	if sub.session != models.SessionRegular {
+		e.submitSession(ctx, out, sub, rest)
+		return
+	}
+	if e.algo != nil && float64(rest)*sub.price >= e.algoMinValue {
+		e.submitAlgo(ctx, out, sub, rest)
+		return
+	}
+
+	var (
+		bo  *models.BrokerOrder
+		err error
+	)
+	if sub.side == "SELL" {
+		bo, err = e.broker.Sell(ctx, sub.code, rest)
+	} else {
+		bo, err = e.broker.Buy(ctx, sub.code, rest)
+	}
+	if err != nil {
+		logger.Error.Printf("[%s] %s %s x %d failed: %v\n", sub.strategy, sub.side, sub.code, rest, err)
+		e.complete(ctx, out, sub, sub.crossed, err.Error())
+		return
+	}
+	e.confirm(ctx, out, sub, bo, rest, e.fillTimeout)
+}
+
+// confirm 접수된 주문의 체결을 확인해 체결된 수량/평균가만 기록한다. 확인하지 못한 잔량은 실패로 남긴다.
+func (e *Executor) confirm(ctx context.Context, out []*models.Order, sub *submission, bo *models.BrokerOrder, rest int64, timeout time.Duration) {
+	fill, err := awaitFill(ctx, e.broker, bo, rest, timeout, e.wait)
+	if err != nil {
+		logger.Error.Printf("[%s] %s %s x %d: %v\n", sub.strategy, sub.side, sub.code, rest, err)
+		e.completeFill(ctx, out, sub, 0, 0, err.Error())
+		return
+	}
+	errMsg := ""
+	if fill.Filled < rest {
+		errMsg = fmt.Sprintf("filled %d/%d, remainder canceled", fill.Filled, rest)
+		logger.Error.Printf("[%s] %s %s %s\n", sub.strategy, sub.side, sub.code, errMsg)
+	}
+	e.completeFill(ctx, out, sub, min(fill.Filled, rest), fill.AvgPrice, errMsg)
+}
+
+// submitSession 동시호가/시간외 세션 주문. 체결 시점이 세션 안에서 정해지므로 sessionWait 동안 체결을 확인하고,
+// 그때까지 체결된 수량/평균가만 기록한다 (남은 수량은 취소). 시간외 단일가는 intent 의 LimitPrice (없으면 현재가) 를 지정가로 낸다.
+func (e *Executor) submitSession(ctx context.Context, out []*models.Order, sub *submission, rest int64) {
+	sb, ok := e.broker.(SessionBroker)
+	if !ok {
+		e.complete(ctx, out, sub, sub.crossed, fmt.Sprintf("broker does not support %s orders", sub.session))
+		return
+	}
+	var (
+		bo  *models.BrokerOrder
+		err error
+	)
+	if sub.side == "SELL" {
+		bo, err = sb.SellSession(ctx, sub.code, rest, sub.session, sub.price)
+	} else {
+		bo, err = sb.BuySession(ctx, sub.code, rest, sub.session, sub.price)
+	}
+	if err != nil {
+		logger.Error.Printf("[%s] %s %s x %d (%s) failed: %v\n", sub.strategy, sub.side, sub.code, rest, sub.session, err)
+		e.complete(ctx, out, sub, sub.crossed, err.Error())
+		return
+	}
+	logger.Info.Printf("[%s] %s %s x %d (%s) accepted, waiting up to %s for fills\n", sub.strategy, sub.side, sub.code, rest, sub.session, e.sessionWait)
+	e.confirm(ctx, out, sub, bo, rest, e.sessionWait)
+}
+
+// submitAlgo 큰 주문을 알고리즘으로 나눠 내고, 실제 체결 수량/평균가로 기록한다.
+// 내부 체결분은 현재가, 알고리즘 체결분은 평균 체결가로 가중 평균한다.
+func (e *Executor) submitAlgo(ctx context.Context, out []*models.Order, sub *submission, rest int64) {
+	rep, err := e.algo.Run(ctx, ParentOrder{Strategy: sub.strategy, Code: sub.code, Side: sub.side, Quantity: rest})
+	errMsg := ""
+	if err != nil {
+		logger.Error.Printf("[%s] algo %s %s x %d: %v\n", sub.strategy, sub.side, sub.code, rest, err)
+		errMsg = err.Error()
+	}
+	sub.arrival = rep.ArrivalPrice
+	e.completeFill(ctx, out, sub, rep.Filled, rep.AvgPrice, errMsg)
+}
+
+// completeFill 내부 체결분(현재가)과 브로커 체결분(평균 체결가)을 가중 평균해 기록한다.
+func (e *Executor) completeFill(ctx context.Context, out []*models.Order, sub *submission, filled int64, avgPrice float64, errMsg string) {
+	total := sub.crossed + filled
+	if filled > 0 && avgPrice > 0 {
+		sub.price = (sub.price*float64(sub.crossed) + avgPrice*float64(filled)) / float64(total)
+	}
+	e.complete(ctx, out, sub, total, errMsg)
+}
+
+// complete filled 수량만큼 체결 기록을 남기고 intent 별 상태를 채운다.
+// 내부 체결은 제출 시점 현재가, 브로커 체결은 확인된 체결가 (completeFill 에서 sub.price 에 반영) 로 기록한다.
+func (e *Executor) complete(ctx context.Context, out []*models.Order, sub *submission, filled int64, errMsg string) {
+	tag := "[" + sub.strategy + "]"
+	if filled > 0 {
+		trade := &models.Trade{
+			Code:     sub.code,
+			Side:     sub.side,
+			Quantity: filled,
+			Price:    sub.price,
+			Time:     time.Now(),
+			Strategy: sub.strategy,
+		}
+		if err := e.trades.InsertTrade(ctx, trade); err != nil {
+			logger.Error.Printf("%s failed to insert trade for %s: %v\n", tag, sub.code, err)
+		}
+		logger.Info.Printf("%s %s %s x %d @ %.2f\n", tag, sub.side, sub.code, filled, sub.price)
+	}
+
+	// 앞쪽 intent 부터 체결 수량을 배정
+	left := filled
+	for _, i := range sub.idx {
+		o := out[i]
+		q := min(o.Quantity, left)
+		left -= q
+		switch {
+		case q > 0:
+			o.Status = models.OrderFilled
+			o.Quantity = q
+			o.Price = sub.price
+			o.ArrivalPrice = sub.arrival
+			o.Error = errMsg
+		case errMsg != "":
+			e.reject(o, models.OrderFailed, errMsg)
+		default:
+			e.reject(o, models.OrderRejected, "capped to held quantity")
+		}
+	}
+}
+
+// shrink 뒤쪽 intent 부터 n 주를 줄인다.
+func (e *Executor) shrink(out []*models.Order, idx []int, n int64) {
+	for j := len(idx) - 1; j >= 0 && n > 0; j-- {
+		o := out[idx[j]]
+		d := min(o.Quantity, n)
+		o.Quantity -= d
+		n -= d
+	}
+}
+
+func (e *Executor) reject(o *models.Order, status, msg string) {
+	o.Status = status
+	o.Error = msg
+	if status != models.OrderFilled {
+		o.Quantity = 0
+	}
+}
+
+func (e *Executor) record(ctx context.Context, o *models.Order) {
+	if o.Status == "" {
+		o.Status = models.OrderRejected
+	}
+	if o.Status != models.OrderFilled && o.Status != models.OrderNetted && o.Error != "" {
+		logger.Info.Printf("[%s] order %s %s not filled (%s): %s\n", o.Intent.Strategy, o.Intent.Side, o.Intent.Code, o.Status, o.Error)
+	}
+	if e.orders == nil {
+		return
+	}
+	if err := e.orders.InsertOrder(ctx, o); err != nil {
+		logger.Error.Printf("[%s] failed to record order for %s: %v\n", o.Intent.Strategy, o.Intent.Code, err)
+	}
+}
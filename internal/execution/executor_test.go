@@ -0,0 +1,156 @@
+package execution
+
+import (
+	"testing"
+
+	"stock-investing/internal/models"
+)
+
+func TestNet(t *testing.T) {
+	type leg struct {
+		side string
+		qty  int64
+	}
+	tests := []struct {
+		name     string
+		legs     []leg
+		ok       bool
+		side     string
+		qty      int64
+		quantity []int64  // intent 별 남은 수량
+		status   []string // intent 별 상태 ("" = 제출 대기)
+	}{
+		{
+			name: "single buy",
+			legs: []leg{{"BUY", 10}},
+			ok:   true, side: "BUY", qty: 10,
+			quantity: []int64{10},
+			status:   []string{""},
+		},
+		{
+			name: "same side is summed",
+			legs: []leg{{"BUY", 3}, {"BUY", 2}},
+			ok:   true, side: "BUY", qty: 5,
+			quantity: []int64{3, 2},
+			status:   []string{"", ""},
+		},
+		{
+			name: "buy larger than sell",
+			legs: []leg{{"BUY", 10}, {"SELL", 4}},
+			ok:   true, side: "BUY", qty: 6,
+			quantity: []int64{6, 0},
+			status:   []string{"", models.OrderNetted},
+		},
+		{
+			name: "sells filled front to back",
+			legs: []leg{{"SELL", 3}, {"SELL", 2}, {"BUY", 1}},
+			ok:   true, side: "SELL", qty: 4,
+			quantity: []int64{3, 1, 0},
+			status:   []string{"", "", models.OrderNetted},
+		},
+		{
+			name: "last sell absorbed entirely",
+			legs: []leg{{"SELL", 4}, {"SELL", 2}, {"BUY", 2}},
+			ok:   true, side: "SELL", qty: 4,
+			quantity: []int64{4, 0, 0},
+			status:   []string{"", models.OrderNetted, models.OrderNetted},
+		},
+		{
+			name:     "fully netted",
+			legs:     []leg{{"BUY", 5}, {"SELL", 5}},
+			ok:       false,
+			quantity: []int64{0, 0},
+			status:   []string{models.OrderNetted, models.OrderNetted},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out []*models.Order
+			var idx []int
+			for i, l := range tt.legs {
+				out = append(out, &models.Order{
+					Intent:   models.OrderIntent{Strategy: "s", Code: "005930", Side: l.side, Quantity: l.qty},
+					Quantity: l.qty,
+					Price:    100,
+				})
+				idx = append(idx, i)
+			}
+
+			sub, ok := (&Executor{}).net(out, idx)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && (sub.side != tt.side || sub.qty != tt.qty) {
+				t.Errorf("net = %s %d, want %s %d", sub.side, sub.qty, tt.side, tt.qty)
+			}
+			for i, o := range out {
+				if o.Quantity != tt.quantity[i] || o.Status != tt.status[i] {
+					t.Errorf("intent %d = %d %q, want %d %q", i, o.Quantity, o.Status, tt.quantity[i], tt.status[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCrossInternally(t *testing.T) {
+	tests := []struct {
+		name    string
+		subs    []*submission
+		crossed []int64
+	}{
+		{
+			name: "sell larger than buy",
+			subs: []*submission{
+				{strategy: "a", code: "X", side: "SELL", qty: 10},
+				{strategy: "b", code: "X", side: "BUY", qty: 4},
+			},
+			crossed: []int64{4, 4},
+		},
+		{
+			name: "buys matched in order",
+			subs: []*submission{
+				{strategy: "a", code: "X", side: "SELL", qty: 3},
+				{strategy: "b", code: "X", side: "BUY", qty: 5},
+				{strategy: "c", code: "X", side: "BUY", qty: 5},
+			},
+			crossed: []int64{3, 3, 0},
+		},
+		{
+			name: "several sells into one buy",
+			subs: []*submission{
+				{strategy: "a", code: "X", side: "SELL", qty: 2},
+				{strategy: "b", code: "X", side: "SELL", qty: 5},
+				{strategy: "c", code: "X", side: "BUY", qty: 6},
+			},
+			crossed: []int64{2, 4, 6},
+		},
+		{
+			name: "different codes never cross",
+			subs: []*submission{
+				{strategy: "a", code: "X", side: "SELL", qty: 5},
+				{strategy: "b", code: "Y", side: "BUY", qty: 5},
+			},
+			crossed: []int64{0, 0},
+		},
+		{
+			name: "same side never crosses",
+			subs: []*submission{
+				{strategy: "a", code: "X", side: "BUY", qty: 5},
+				{strategy: "b", code: "X", side: "BUY", qty: 5},
+			},
+			crossed: []int64{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crossInternally(tt.subs)
+			for i, sub := range tt.subs {
+				if sub.crossed != tt.crossed[i] {
+					t.Errorf("%s %s %s crossed = %d, want %d", sub.strategy, sub.side, sub.code, sub.crossed, tt.crossed[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+// FillSource 주문번호별 체결 조회 (kis.Client 가 구현)
+type FillSource interface {
+	GetOrderFill(ctx context.Context, o *models.BrokerOrder) (*models.OrderFill, error)
+}
+
+// OrderCanceler 미체결 잔량 취소 (kis.Client 가 구현). 없으면 잔량은 취소하지 않고 실패로만 남긴다.
+type OrderCanceler interface {
+	CancelOrder(ctx context.Context, o *models.BrokerOrder) error
+}
+
+// 체결 확인 기본값
+const (
+	DefaultFillTimeout = 30 * time.Second // 시장가
+	DefaultSessionWait = 30 * time.Minute // 동시호가/시간외 (체결 시점이 세션 안에서 정해진다)
+)
+
+// pollInterval 대기 시간의 1/15, 1초 ~ 30초
+func pollInterval(timeout time.Duration) time.Duration {
+	return min(max(timeout/15, time.Second), 30*time.Second)
+}
+
+// awaitFill o 가 qty 만큼 체결되거나 timeout 이 지날 때까지 체결을 확인한다.
+// 다 체결되지 않으면 남은 수량을 취소하고 (브로커가 지원하면) 취소 뒤 다시 조회한 체결을 반환한다.
+// 체결을 한 번도 확인하지 못하면 에러. 반환된 fill 의 Filled 만 체결로 기록해야 한다.
+func awaitFill(ctx context.Context, src FillSource, o *models.BrokerOrder, qty int64, timeout time.Duration,
+	wait func(ctx context.Context, d time.Duration) error) (*models.OrderFill, error) {
+	deadline := time.Now().Add(timeout)
+	poll := pollInterval(timeout)
+
+	var (
+		fill    *models.OrderFill
+		lastErr error
+	)
+	for {
+		f, err := src.GetOrderFill(ctx, o)
+		if err == nil {
+			fill, lastErr = f, nil
+			if f.Filled >= qty {
+				return f, nil
+			}
+		} else {
+			lastErr = err
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+		if err := wait(ctx, min(poll, time.Until(deadline))); err != nil {
+			break
+		}
+	}
+
+	// 남은 수량 정리. 중단된 ctx 로도 취소/조회는 끝낸다.
+	ctx = context.WithoutCancel(ctx)
+	if c, ok := src.(OrderCanceler); ok {
+		if err := c.CancelOrder(ctx, o); err != nil {
+			// 취소 직전 전량 체결됐거나 이미 만료된 주문은 취소가 거부될 수 있다. 아래 재조회 결과를 따른다.
+			logger.Info.Printf("[execution] cancel %s: %v\n", o.ID, err)
+		}
+		if f, err := src.GetOrderFill(ctx, o); err == nil {
+			fill, lastErr = f, nil
+		} else if fill == nil {
+			lastErr = err
+		}
+	}
+	if fill == nil {
+		return nil, fmt.Errorf("fill unconfirmed for order %s: %w", o.ID, lastErr)
+	}
+	return fill, nil
+}
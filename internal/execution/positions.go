@@ -0,0 +1,108 @@
+package execution
+
+import (
+	"sort"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// PositionsFromTrades 한 전략의 체결 기록(오래된 순)을 누적해 현재 보유 포지션을 계산한다.
+func PositionsFromTrades(strategy string, trades []*models.Trade) []*models.Position {
+	byCode := map[string]*models.Position{}
+	carry := RemapCarry{}
+	for _, t := range trades {
+		p, ok := byCode[t.Code]
+		if !ok {
+			p = &models.Position{Code: t.Code, Strategy: strategy}
+			byCode[t.Code] = p
+		}
+		carry.Apply(p, t)
+	}
+
+	var out []*models.Position
+	for _, p := range byCode {
+		if p.Quantity > 0 {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+// ApplyTrade 체결 하나를 포지션에 반영하고 이번 체결의 실현 손익을 반환한다.
+// 평균단가는 매수 시 가중평균, 매도 시 유지(보유 수량을 넘는 매도는 보유분까지만), 0주가 되면 초기화한다.
+// 기업 행위 보정(ADJUST/REMAP)은 총원가를 유지한다. 실현 손익은 p.Realized 에 진입 이후 누적된다.
+func ApplyTrade(p *models.Position, t *models.Trade) float64 {
+	var realized float64
+	switch t.Side {
+	case "BUY":
+		if p.Quantity == 0 {
+			p.OpenedAt = t.Time
+			p.AvgPrice = 0
+			p.Realized = 0
+		}
+		cost := p.AvgPrice*float64(p.Quantity) + t.Price*float64(t.Quantity)
+		p.Quantity += t.Quantity
+		p.AvgPrice = cost / float64(p.Quantity)
+	case "SELL":
+		qty := min(t.Quantity, p.Quantity)
+		realized = (t.Price - p.AvgPrice) * float64(qty)
+		p.Realized += realized
+		p.Quantity -= qty
+		if p.Quantity <= 0 {
+			p.Quantity = 0
+			p.AvgPrice = 0
+		}
+	case models.SideAdjust, models.SideRemap:
+		// 기업 행위 보정: 총원가에 Price × 증감 수량을 더한다 (분할/무상증자는 Price 0 이라 총원가 유지).
+		if p.Quantity == 0 {
+			p.OpenedAt = t.Time
+			p.AvgPrice = 0
+			p.Realized = 0
+		}
+		cost := p.AvgPrice*float64(p.Quantity) + t.Price*float64(t.Quantity)
+		p.Quantity += t.Quantity
+		if p.Quantity <= 0 {
+			p.Quantity = 0
+			p.AvgPrice = 0
+		} else {
+			p.AvgPrice = cost / float64(p.Quantity)
+		}
+	}
+	return realized
+}
+
+// RemapCarry 코드 변경(REMAP) 보정 체결 쌍 사이에서 전략별 실현 손익과 진입 시각을 넘긴다.
+// 두 체결은 같은 권리락일 시각에 차감 → 편입 순(id 순)으로 기록되므로, 차감 직전의 Realized/OpenedAt 을
+// 맡아 두었다가 새 코드 편입 뒤 되돌린다.
+type RemapCarry map[string]RemapLeg
+
+// RemapLeg 코드 변경으로 넘어가는 기존 코드의 실현 손익과 진입 시각
+type RemapLeg struct {
+	Realized float64
+	OpenedAt time.Time
+}
+
+// Apply ApplyTrade 와 같고, REMAP 체결이면 실현 손익과 진입 시각을 새 코드로 옮긴다.
+// remapped 가 true 면 기존 코드가 코드 변경으로 0주가 된 것이라 청산으로 기록하지 않는다.
+func (c RemapCarry) Apply(p *models.Position, t *models.Trade) (realized float64, remapped bool) {
+	if t.Side != models.SideRemap {
+		return ApplyTrade(p, t), false
+	}
+	if t.Quantity < 0 {
+		c[t.Strategy] = RemapLeg{Realized: c[t.Strategy].Realized + p.Realized, OpenedAt: p.OpenedAt}
+		ApplyTrade(p, t)
+		p.Realized = 0
+		return 0, true
+	}
+	ApplyTrade(p, t)
+	if leg, ok := c[t.Strategy]; ok {
+		p.Realized += leg.Realized
+		if !leg.OpenedAt.IsZero() {
+			p.OpenedAt = leg.OpenedAt
+		}
+		delete(c, t.Strategy)
+	}
+	return 0, false
+}
@@ -0,0 +1,97 @@
+package execution
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+func TestApplyTrade(t *testing.T) {
+	day := time.Date(2025, 3, 3, 9, 0, 0, 0, models.KST)
+	trade := func(side string, qty int64, price float64, d int) *models.Trade {
+		return &models.Trade{Code: "X", Side: side, Quantity: qty, Price: price, Time: day.AddDate(0, 0, d), Strategy: "s"}
+	}
+
+	tests := []struct {
+		name     string
+		trades   []*models.Trade
+		realized float64 // 마지막 체결의 실현 손익
+		qty      int64
+		avg      float64
+		total    float64 // p.Realized
+		opened   time.Time
+	}{
+		{
+			name:   "buys average the price",
+			trades: []*models.Trade{trade("BUY", 10, 100, 0), trade("BUY", 10, 120, 1)},
+			qty:    20, avg: 110, opened: day,
+		},
+		{
+			name:     "partial sell keeps the average",
+			trades:   []*models.Trade{trade("BUY", 10, 100, 0), trade("SELL", 4, 110, 1)},
+			realized: 40, qty: 6, avg: 100, total: 40, opened: day,
+		},
+		{
+			name:     "sell beyond held is capped",
+			trades:   []*models.Trade{trade("BUY", 10, 100, 0), trade("SELL", 15, 90, 1)},
+			realized: -100, qty: 0, avg: 0, total: -100, opened: day,
+		},
+		{
+			name:   "reopening resets entry and realized",
+			trades: []*models.Trade{trade("BUY", 10, 100, 0), trade("SELL", 10, 110, 1), trade("BUY", 5, 200, 2)},
+			qty:    5, avg: 200, total: 0, opened: day.AddDate(0, 0, 2),
+		},
+		{
+			name:   "split adjust keeps total cost",
+			trades: []*models.Trade{trade("BUY", 10, 100, 0), trade(models.SideAdjust, 40, 0, 1)},
+			qty:    50, avg: 20, opened: day,
+		},
+		{
+			name:   "reverse split drops to zero",
+			trades: []*models.Trade{trade("BUY", 3, 100, 0), trade(models.SideAdjust, -3, 0, 1)},
+			qty:    0, avg: 0, opened: day,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &models.Position{Code: "X", Strategy: "s"}
+			var realized float64
+			for _, tr := range tt.trades {
+				realized = ApplyTrade(p, tr)
+			}
+			if !near(realized, tt.realized) || p.Quantity != tt.qty || !near(p.AvgPrice, tt.avg) || !near(p.Realized, tt.total) {
+				t.Errorf("got realized=%.2f qty=%d avg=%.2f total=%.2f, want %.2f %d %.2f %.2f",
+					realized, p.Quantity, p.AvgPrice, p.Realized, tt.realized, tt.qty, tt.avg, tt.total)
+			}
+			if !p.OpenedAt.Equal(tt.opened) {
+				t.Errorf("opened = %s, want %s", p.OpenedAt, tt.opened)
+			}
+		})
+	}
+}
+
+func TestRemapCarry(t *testing.T) {
+	day := time.Date(2025, 3, 3, 9, 0, 0, 0, models.KST)
+	exDate := time.Date(2025, 3, 10, 0, 0, 0, 0, models.KST)
+	// 저장소가 돌려주는 (time, id) 순서. 두 REMAP 은 같은 권리락일 시각에 차감 → 편입 순으로 기록된다.
+	trades := []*models.Trade{
+		{ID: 1, Code: "A", Side: "BUY", Quantity: 10, Price: 100, Time: day, Strategy: "s"},
+		{ID: 2, Code: "A", Side: "SELL", Quantity: 5, Price: 120, Time: day.AddDate(0, 0, 1), Strategy: "s"},
+		{ID: 3, Code: "A", Side: models.SideRemap, Quantity: -5, Time: exDate, Strategy: "s"},
+		{ID: 4, Code: "B", Side: models.SideRemap, Quantity: 10, Price: 50, Time: exDate, Strategy: "s"},
+	}
+	got := PositionsFromTrades("s", trades)
+	if len(got) != 1 {
+		t.Fatalf("positions = %d, want 1", len(got))
+	}
+	p := got[0]
+	if p.Code != "B" || p.Quantity != 10 || !near(p.AvgPrice, 50) || !near(p.Realized, 100) || !p.OpenedAt.Equal(day) {
+		t.Errorf("got %s %d @ %.2f realized %.2f opened %s, want B 10 @ 50 realized 100 opened %s",
+			p.Code, p.Quantity, p.AvgPrice, p.Realized, p.OpenedAt, day)
+	}
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
@@ -0,0 +1,184 @@
+package indicator
+
+import (
+	"errors"
+	"math"
+
+	"stock-investing/internal/models"
+)
+
+// ErrInsufficientData 지표 계산에 필요한 봉 수가 부족할 때 반환
+var ErrInsufficientData = errors.New("insufficient candle data")
+
+// 모든 함수는 candles 가 날짜 오름차순(마지막 원소가 최신 봉)이라고 가정한다.
+
+func closes(candles []models.Candle) []float64 {
+	out := make([]float64, len(candles))
+	for i, c := range candles {
+		out[i] = c.Close
+	}
+	return out
+}
+
+// SMA 최근 n개 종가 단순이동평균
+func SMA(candles []models.Candle, n int) (float64, error) {
+	if n <= 0 || len(candles) < n {
+		return 0, ErrInsufficientData
+	}
+	sum := 0.0
+	for _, c := range candles[len(candles)-n:] {
+		sum += c.Close
+	}
+	return sum / float64(n), nil
+}
+
+// EMA 종가 지수이동평균 (첫 n개 SMA 로 시드)
+func EMA(candles []models.Candle, n int) (float64, error) {
+	if n <= 0 || len(candles) < n {
+		return 0, ErrInsufficientData
+	}
+	cl := closes(candles)
+	ema := 0.0
+	for _, v := range cl[:n] {
+		ema += v
+	}
+	ema /= float64(n)
+	k := 2.0 / float64(n+1)
+	for _, v := range cl[n:] {
+		ema = v*k + ema*(1-k)
+	}
+	return ema, nil
+}
+
+// RSI Wilder 방식 RSI(n). n+1개 이상의 봉이 필요하다.
+func RSI(candles []models.Candle, n int) (float64, error) {
+	if n <= 0 || len(candles) < n+1 {
+		return 0, ErrInsufficientData
+	}
+	cl := closes(candles)
+	var gain, loss float64
+	for i := 1; i <= n; i++ {
+		d := cl[i] - cl[i-1]
+		if d > 0 {
+			gain += d
+		} else {
+			loss -= d
+		}
+	}
+	gain /= float64(n)
+	loss /= float64(n)
+	for i := n + 1; i < len(cl); i++ {
+		d := cl[i] - cl[i-1]
+		g, l := 0.0, 0.0
+		if d > 0 {
+			g = d
+		} else {
+			l = -d
+		}
+		gain = (gain*float64(n-1) + g) / float64(n)
+		loss = (loss*float64(n-1) + l) / float64(n)
+	}
+	if loss == 0 {
+		if gain == 0 {
+			return 50, nil
+		}
+		return 100, nil
+	}
+	rs := gain / loss
+	return 100 - 100/(1+rs), nil
+}
+
+// AvgVolume 최근 n개 봉 평균 거래량
+func AvgVolume(candles []models.Candle, n int) (float64, error) {
+	if n <= 0 || len(candles) < n {
+		return 0, ErrInsufficientData
+	}
+	sum := 0.0
+	for _, c := range candles[len(candles)-n:] {
+		sum += float64(c.Volume)
+	}
+	return sum / float64(n), nil
+}
+
+// ATR Wilder 방식 Average True Range. n+1개 이상의 봉이 필요하다.
+func ATR(candles []models.Candle, n int) (float64, error) {
+	if n <= 0 || len(candles) < n+1 {
+		return 0, ErrInsufficientData
+	}
+	tr := func(i int) float64 {
+		c, prev := candles[i], candles[i-1]
+		return math.Max(c.High-c.Low, math.Max(math.Abs(c.High-prev.Close), math.Abs(c.Low-prev.Close)))
+	}
+	atr := 0.0
+	for i := 1; i <= n; i++ {
+		atr += tr(i)
+	}
+	atr /= float64(n)
+	for i := n + 1; i < len(candles); i++ {
+		atr = (atr*float64(n-1) + tr(i)) / float64(n)
+	}
+	return atr, nil
+}
+
+// Highest 최근 n개 봉 고가 중 최댓값
+func Highest(candles []models.Candle, n int) (float64, error) {
+	if n <= 0 || len(candles) < n {
+		return 0, ErrInsufficientData
+	}
+	h := math.Inf(-1)
+	for _, c := range candles[len(candles)-n:] {
+		h = math.Max(h, c.High)
+	}
+	return h, nil
+}
+
+// Lowest 최근 n개 봉 저가 중 최솟값
+func Lowest(candles []models.Candle, n int) (float64, error) {
+	if n <= 0 || len(candles) < n {
+		return 0, ErrInsufficientData
+	}
+	l := math.Inf(1)
+	for _, c := range candles[len(candles)-n:] {
+		l = math.Min(l, c.Low)
+	}
+	return l, nil
+}
+
+// StdDev 최근 n개 종가 모표준편차
+func StdDev(candles []models.Candle, n int) (float64, error) {
+	mean, err := SMA(candles, n)
+	if err != nil {
+		return 0, err
+	}
+	v := 0.0
+	for _, c := range candles[len(candles)-n:] {
+		d := c.Close - mean
+		v += d * d
+	}
+	return math.Sqrt(v / float64(n)), nil
+}
+
+// Bollinger n일 SMA ± k·표준편차 밴드 (middle, upper, lower)
+func Bollinger(candles []models.Candle, n int, k float64) (middle, upper, lower float64, err error) {
+	middle, err = SMA(candles, n)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	sd, err := StdDev(candles, n)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return middle, middle + k*sd, middle - k*sd, nil
+}
+
+// ROC n봉 전 종가 대비 변화율 (0.1 = +10%)
+func ROC(candles []models.Candle, n int) (float64, error) {
+	if n <= 0 || len(candles) < n+1 {
+		return 0, ErrInsufficientData
+	}
+	base := candles[len(candles)-1-n].Close
+	if base <= 0 {
+		return 0, ErrInsufficientData
+	}
+	return candles[len(candles)-1].Close/base - 1, nil
+}
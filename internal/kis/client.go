@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
+	"stock-investing/internal/models"
 	"stock-investing/pkg/logger"
 )
 
@@ -17,15 +19,17 @@ type Client struct {
 	auth      *AuthClient
 	baseURL   string
 	accountNo string
+	mock      bool // 모의투자 서버 (계좌/주문 TR_ID 가 V 로 시작)
 
 	httpClient *http.Client
 }
 
-func NewClient(appKey, appSecret, baseURL, accountNo string) *Client {
+func NewClient(appKey, appSecret, baseURL, accountNo string, mock bool) *Client {
 	return &Client{
 		auth:      NewAuthClient(appKey, appSecret, baseURL, accountNo),
 		baseURL:   baseURL,
 		accountNo: accountNo,
+		mock:      mock,
 		httpClient: &http.Client{
 			Timeout: 5 * time.Second,
 		},
@@ -75,6 +79,14 @@ func (c *Client) doGet(ctx context.Context, path string, query string, trID stri
 type quoteOutput struct {
 	// 실제 문서 기준 필드 이름/타입 확인 필요
 	StockPrice string `json:"stck_prpr"` // 문자열로 내려오면 string으로 받고 나중에 float 변환
+
+	Volume       string `json:"acml_vol"`           // 누적 거래량
+	TradingValue string `json:"acml_tr_pbmn"`       // 누적 거래대금 (원)
+	MarketCap    string `json:"hts_avls"`           // HTS 시가총액 (억원)
+	StatusCode   string `json:"iscd_stat_cls_code"` // 종목상태구분: 51 관리, 52 투자위험, 53 투자경고, 54 투자주의, 58 거래정지
+	WarnCode     string `json:"mrkt_warn_cls_code"` // 시장경고: 00 없음, 01 투자주의, 02 투자경고, 03 투자위험
+	TempStop     string `json:"temp_stop_yn"`       // 임시정지 여부
+	Liquidation  string `json:"sltr_yn"`            // 정리매매 여부
 }
 
 type quoteResponse struct {
@@ -100,6 +112,134 @@ func (c *Client) GetQuote(ctx context.Context, code string) (float64, error) {
 	return price, nil
 }
 
+// GetQuoteDetail 현재가와 함께 거래대금/시가총액/종목 상태를 반환한다.
+func (c *Client) GetQuoteDetail(ctx context.Context, code string) (*models.Quote, error) {
+	path := "/uapi/domestic-stock/v1/quotations/inquire-price"
+	query := fmt.Sprintf("fid_cond_mrkt_div_code=J&fid_input_iscd=%s", code)
+	trID := "FHKST01010100"
+
+	var resp quoteResponse
+	if err := c.doGet(ctx, path, query, trID, &resp); err != nil {
+		return nil, err
+	}
+	o := resp.Output
+
+	price, err := parsePrice(o.StockPrice)
+	if err != nil {
+		return nil, err
+	}
+	q := &models.Quote{
+		Code:        code,
+		Price:       price,
+		Halted:      o.StatusCode == "58" || o.TempStop == "Y",
+		Managed:     o.StatusCode == "51",
+		Warning:     o.StatusCode == "52" || o.StatusCode == "53" || o.WarnCode == "02" || o.WarnCode == "03",
+		Caution:     o.StatusCode == "54" || o.WarnCode == "01",
+		Liquidation: o.Liquidation == "Y",
+	}
+	// 부가 필드는 비어 있을 수 있으므로 파싱 실패 시 0으로 둔다.
+	q.Volume, _ = strconv.ParseInt(o.Volume, 10, 64)
+	q.TradingValue, _ = parsePrice(o.TradingValue)
+	if capEok, err := parsePrice(o.MarketCap); err == nil {
+		q.MarketCap = capEok * 1e8
+	}
+	return q, nil
+}
+
+// ==== 국내주식 기간별 시세 (일봉) ====
+
+type dailyCandleOutput struct {
+	Date   string `json:"stck_bsop_date"` // YYYYMMDD
+	Open   string `json:"stck_oprc"`
+	High   string `json:"stck_hgpr"`
+	Low    string `json:"stck_lwpr"`
+	Close  string `json:"stck_clpr"`
+	Volume string `json:"acml_vol"`
+}
+
+type dailyCandleResponse struct {
+	RtCd   string              `json:"rt_cd"`
+	Msg    string              `json:"msg1"`
+	Output []dailyCandleOutput `json:"output2"`
+}
+
+// KIS 기간별 시세 API 는 한 번에 최대 100봉까지만 내려준다.
+const maxCandlesPerCall = 100
+
+// GetDailyCandles from~to 구간의 수정주가 일봉을 날짜 오름차순으로 반환한다.
+func (c *Client) GetDailyCandles(ctx context.Context, code string, from, to time.Time) ([]models.Candle, error) {
+	path := "/uapi/domestic-stock/v1/quotations/inquire-daily-itemchartprice"
+	trID := "FHKST03010100"
+
+	var out []models.Candle
+	end := to
+	for !end.Before(from) {
+		query := fmt.Sprintf(
+			"FID_COND_MRKT_DIV_CODE=J&FID_INPUT_ISCD=%s&FID_INPUT_DATE_1=%s&FID_INPUT_DATE_2=%s&FID_PERIOD_DIV_CODE=D&FID_ORG_ADJ_PRC=0",
+			code, from.In(models.KST).Format("20060102"), end.In(models.KST).Format("20060102"),
+		)
+
+		var resp dailyCandleResponse
+		if err := c.doGet(ctx, path, query, trID, &resp); err != nil {
+			return nil, err
+		}
+		if resp.RtCd != "" && resp.RtCd != "0" {
+			return nil, fmt.Errorf("daily candles %s: %s", code, resp.Msg)
+		}
+
+		oldest := end
+		n := 0
+		for _, o := range resp.Output {
+			if o.Date == "" {
+				continue
+			}
+			candle, err := o.toCandle(code)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, candle)
+			if candle.Date.Before(oldest) {
+				oldest = candle.Date
+			}
+			n++
+		}
+		// 100봉 미만이면 구간 끝까지 다 받은 것
+		if n < maxCandlesPerCall {
+			break
+		}
+		end = oldest.AddDate(0, 0, -1)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, nil
+}
+
+func (o dailyCandleOutput) toCandle(code string) (models.Candle, error) {
+	date, err := time.ParseInLocation("20060102", o.Date, models.KST)
+	if err != nil {
+		return models.Candle{}, err
+	}
+	vals := make([]float64, 4)
+	for i, s := range []string{o.Open, o.High, o.Low, o.Close} {
+		if vals[i], err = parsePrice(s); err != nil {
+			return models.Candle{}, fmt.Errorf("candle %s %s: %w", code, o.Date, err)
+		}
+	}
+	vol, err := strconv.ParseInt(o.Volume, 10, 64)
+	if err != nil {
+		return models.Candle{}, fmt.Errorf("candle %s %s: %w", code, o.Date, err)
+	}
+	return models.Candle{
+		Code:   code,
+		Date:   date,
+		Open:   vals[0],
+		High:   vals[1],
+		Low:    vals[2],
+		Close:  vals[3],
+		Volume: vol,
+	}, nil
+}
+
 // ===== 주문 예시 (현금 매수) =====
 
 type orderRequest struct {
@@ -113,10 +253,6 @@ type orderRequest struct {
 	//   ORD_UNPR: 주문단가
 }
 
-type orderResponse struct {
-	// rescode, resmsg, output 등 문서 기준으로 정의
-}
-
 func (c *Client) getHashKey(ctx context.Context, body []byte) (string, error) {
 	tok, err := c.auth.GetToken(ctx)
 	if err != nil {
@@ -153,26 +289,83 @@ func (c *Client) getHashKey(ctx context.Context, body []byte) (string, error) {
 	return hk.Hash, nil
 }
 
-func (c *Client) Buy(ctx context.Context, code string, quantity int64) error {
-	tok, err := c.auth.GetToken(ctx)
-	if err != nil {
-		return err
+// trPair 계좌/주문 TR_ID 는 실전(T...)과 모의투자(V...)가 다르다.
+type trPair struct{ live, mock string }
+
+// trID 클라이언트가 붙은 서버에 맞는 TR_ID
+func (c *Client) trID(p trPair) string {
+	if c.mock {
+		return p.mock
 	}
+	return p.live
+}
+
+// 현금주문 TR_ID
+var (
+	trCashBuy  = trPair{live: "TTTC0802U", mock: "VTTC0802U"}
+	trCashSell = trPair{live: "TTTC0801U", mock: "VTTC0801U"}
+)
+
+// Buy 시장가 매수. 접수만 확인하므로 체결은 반환된 주문번호로 GetOrderFill 에서 확인한다.
+func (c *Client) Buy(ctx context.Context, code string, quantity int64) (*models.BrokerOrder, error) {
+	return c.orderCash(ctx, c.trID(trCashBuy), "Buy", code, quantity, ordDvsnMarket, 0)
+}
+
+// Sell 시장가 매도
+func (c *Client) Sell(ctx context.Context, code string, quantity int64) (*models.BrokerOrder, error) {
+	return c.orderCash(ctx, c.trID(trCashSell), "Sell", code, quantity, ordDvsnMarket, 0)
+}
+
+// 주문구분 (ORD_DVSN)
+const (
+	ordDvsnLimit  = "00" // 지정가
+	ordDvsnMarket = "01" // 시장가
+)
 
+type orderResponse struct {
+	RtCd   string `json:"rt_cd"`
+	Msg    string `json:"msg1"`
+	Output struct {
+		Branch  string `json:"KRX_FWDG_ORD_ORGNO"` // 주문 조직번호 (정정/취소 시 필요)
+		OrderNo string `json:"ODNO"`
+	} `json:"output"`
+}
+
+// orderCash 현금 주문 공통 (매수/매도는 TR_ID 로만 구분된다). 시장가면 price 는 0.
+func (c *Client) orderCash(ctx context.Context, trID, label, code string, quantity int64, ordDvsn string, price int64) (*models.BrokerOrder, error) {
 	// 계좌번호 분리 (8자리 계좌번호라면 "01" 고정)
 	cano := c.accountNo // 8자리 그대로
 	acntPrdtCd := "01"  // 종합계좌 고정
 
 	logger.Info.Printf("[kis] account: CANO=%s ACNT_PRDT_CD=%s", cano, acntPrdtCd)
 
-	// KIS 공식 현금주문 Body (TR_ID: TTTC0802U 모의투자용)
+	// KIS 공식 현금주문 Body (매수/매도 동일)
 	reqBody := map[string]interface{}{
 		"CANO":         cano,       // 계좌번호 앞 8자리
 		"ACNT_PRDT_CD": acntPrdtCd, // "01"
 		"PDNO":         code,       // 종목코드 (6자리)
-		"ORD_DVSN":     "01",       // 주문구분: 지정가
+		"ORD_DVSN":     ordDvsn,
 		"ORD_QTY":      fmt.Sprintf("%d", quantity),
-		"ORD_UNPR":     "0", // 시장가
+		"ORD_UNPR":     fmt.Sprintf("%d", price), // 시장가는 "0"
+	}
+
+	var resp orderResponse
+	if err := c.doPost(ctx, "/uapi/domestic-stock/v1/trading/order-cash", trID, label, reqBody, &resp); err != nil {
+		return nil, err
+	}
+	if resp.RtCd != "" && resp.RtCd != "0" {
+		return nil, fmt.Errorf("%s rejected: %s", label, resp.Msg)
+	}
+
+	logger.Info.Printf("[kis] %s SUCCESS: %s x %d (ODNO=%s)", label, code, quantity, resp.Output.OrderNo)
+	return &models.BrokerOrder{Branch: resp.Output.Branch, ID: resp.Output.OrderNo}, nil
+}
+
+// doPost hashkey 를 붙인 주문 계열 POST 공통 래퍼
+func (c *Client) doPost(ctx context.Context, path, trID, label string, reqBody interface{}, out interface{}) error {
+	tok, err := c.auth.GetToken(ctx)
+	if err != nil {
+		return err
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
@@ -187,8 +380,7 @@ func (c *Client) Buy(ctx context.Context, code string, quantity int64) error {
 		return err
 	}
 
-	// 2) 주문 요청 (모의투자 TR_ID: TTTC0802U)
-	path := "/uapi/domestic-stock/v1/trading/order-cash"
+	// 2) 주문 요청
 	url := c.baseURL + path
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
@@ -199,7 +391,7 @@ func (c *Client) Buy(ctx context.Context, code string, quantity int64) error {
 	httpReq.Header.Set("authorization", fmt.Sprintf("Bearer %s", tok.AccessToken))
 	httpReq.Header.Set("appkey", c.auth.appKey)
 	httpReq.Header.Set("appsecret", c.auth.appSecret)
-	httpReq.Header.Set("tr_id", "VTTC0802U") // 모의투자 현금매수 TR_ID
+	httpReq.Header.Set("tr_id", trID)
 	httpReq.Header.Set("hashkey", hash)
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json;v=1.0")
@@ -211,21 +403,64 @@ func (c *Client) Buy(ctx context.Context, code string, quantity int64) error {
 	defer resp.Body.Close()
 
 	bodyResp, _ := io.ReadAll(resp.Body)
-	logger.Info.Printf("[kis] Buy response: status=%d body=%s", resp.StatusCode, string(bodyResp))
+	logger.Info.Printf("[kis] %s response: status=%d body=%s", label, resp.StatusCode, string(bodyResp))
 
 	if resp.StatusCode != http.StatusOK {
-		logger.Error.Printf("[kis] Buy failed: status=%d body=%s", resp.StatusCode, string(bodyResp))
-		return fmt.Errorf("Buy failed: status %d", resp.StatusCode)
+		logger.Error.Printf("[kis] %s failed: status=%d body=%s", label, resp.StatusCode, string(bodyResp))
+		return fmt.Errorf("%s failed: status %d", label, resp.StatusCode)
 	}
 
-	logger.Info.Printf("[kis] Buy SUCCESS: %s x %d", code, quantity)
-	return nil
+	if out == nil || len(bodyResp) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bodyResp, out)
+}
+
+// ==== 계좌 잔고 ====
+
+// 잔고조회 TR_ID
+var trBalance = trPair{live: "TTTC8434R", mock: "VTTC8434R"}
+
+type balanceSummary struct {
+	Cash      string `json:"dnca_tot_amt"`  // 예수금 총액
+	StockEval string `json:"scts_evlu_amt"` // 유가증권 평가금액
+	TotalEval string `json:"tot_evlu_amt"`  // 총평가금액 (예수금 + 유가증권)
+}
+
+type balanceResponse struct {
+	Output2 []balanceSummary `json:"output2"`
 }
 
-func (c *Client) Sell(ctx context.Context, code string, quantity int64) error {
-	// Buy와 거의 동일, TR_ID/주문구분만 매도용으로 변경
-	logger.Info.Printf("[kis] Sell stub for %s x %d (TODO: implement)\n", code, quantity)
-	return nil
+// GetBalance 계좌 예수금/평가금액 조회
+func (c *Client) GetBalance(ctx context.Context) (*models.Balance, error) {
+	path := "/uapi/domestic-stock/v1/trading/inquire-balance"
+	query := fmt.Sprintf(
+		"CANO=%s&ACNT_PRDT_CD=01&AFHR_FLPR_YN=N&OFL_YN=&INQR_DVSN=02&UNPR_DVSN=01"+
+			"&FUND_STTL_ICLD_YN=N&FNCG_AMT_AUTO_RDPT_YN=N&PRCS_DVSN=00&CTX_AREA_FK100=&CTX_AREA_NK100=",
+		c.accountNo,
+	)
+
+	var resp balanceResponse
+	if err := c.doGet(ctx, path, query, c.trID(trBalance), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Output2) == 0 {
+		return nil, fmt.Errorf("balance: empty output2")
+	}
+
+	s := resp.Output2[0]
+	b := &models.Balance{}
+	var err error
+	if b.Cash, err = parsePrice(s.Cash); err != nil {
+		return nil, fmt.Errorf("balance dnca_tot_amt %q: %w", s.Cash, err)
+	}
+	if b.StockValue, err = parsePrice(s.StockEval); err != nil {
+		return nil, fmt.Errorf("balance scts_evlu_amt %q: %w", s.StockEval, err)
+	}
+	if b.TotalEquity, err = parsePrice(s.TotalEval); err != nil {
+		return nil, fmt.Errorf("balance tot_evlu_amt %q: %w", s.TotalEval, err)
+	}
+	return b, nil
 }
 
 func parsePrice(s string) (float64, error) {
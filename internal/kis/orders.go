@@ -0,0 +1,121 @@
+package kis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+// 취소/체결조회 TR_ID. 지정가 주문은 현금주문과 같은 TR_ID 를 쓴다.
+var (
+	trCancel     = trPair{live: "TTTC0803U", mock: "VTTC0803U"}
+	trDailyFills = trPair{live: "TTTC8001R", mock: "VTTC8001R"}
+)
+
+// 호가 조회 (실전/모의 공통)
+const trIDAskingPrice = "FHKST01010200"
+
+// BuyLimit 지정가 매수. 반환된 주문번호로 취소/체결 조회를 한다.
+func (c *Client) BuyLimit(ctx context.Context, code string, quantity int64, price float64) (*models.BrokerOrder, error) {
+	return c.orderCash(ctx, c.trID(trCashBuy), "BuyLimit", code, quantity, ordDvsnLimit, int64(math.Round(price)))
+}
+
+// SellLimit 지정가 매도
+func (c *Client) SellLimit(ctx context.Context, code string, quantity int64, price float64) (*models.BrokerOrder, error) {
+	return c.orderCash(ctx, c.trID(trCashSell), "SellLimit", code, quantity, ordDvsnLimit, int64(math.Round(price)))
+}
+
+// CancelOrder 주문의 미체결 잔량 전부 취소
+func (c *Client) CancelOrder(ctx context.Context, o *models.BrokerOrder) error {
+	reqBody := map[string]interface{}{
+		"CANO":               c.accountNo,
+		"ACNT_PRDT_CD":       "01",
+		"KRX_FWDG_ORD_ORGNO": o.Branch,
+		"ORGN_ODNO":          o.ID,
+		"ORD_DVSN":           ordDvsnLimit,
+		"RVSE_CNCL_DVSN_CD":  "02", // 01 정정, 02 취소
+		"ORD_QTY":            "0",
+		"ORD_UNPR":           "0",
+		"QTY_ALL_ORD_YN":     "Y", // 잔량 전부
+	}
+
+	var resp orderResponse
+	if err := c.doPost(ctx, "/uapi/domestic-stock/v1/trading/order-rvsecncl", c.trID(trCancel), "Cancel", reqBody, &resp); err != nil {
+		return err
+	}
+	if resp.RtCd != "" && resp.RtCd != "0" {
+		return fmt.Errorf("cancel %s rejected: %s", o.ID, resp.Msg)
+	}
+	logger.Info.Printf("[kis] Cancel SUCCESS: ODNO=%s", o.ID)
+	return nil
+}
+
+type dailyFillItem struct {
+	OrderNo  string `json:"odno"`
+	OrderQty string `json:"ord_qty"`
+	FilledQt string `json:"tot_ccld_qty"`
+	AvgPrice string `json:"avg_prvs"`
+}
+
+type dailyFillResponse struct {
+	Output1 []dailyFillItem `json:"output1"`
+}
+
+// GetOrderFill 당일 주문체결 조회로 주문번호별 체결 수량/평균가를 얻는다.
+func (c *Client) GetOrderFill(ctx context.Context, o *models.BrokerOrder) (*models.OrderFill, error) {
+	today := time.Now().In(models.KST).Format("20060102")
+	path := "/uapi/domestic-stock/v1/trading/inquire-daily-ccld"
+	query := fmt.Sprintf(
+		"CANO=%s&ACNT_PRDT_CD=01&INQR_STRT_DT=%s&INQR_END_DT=%s&SLL_BUY_DVSN_CD=00&INQR_DVSN=00"+
+			"&PDNO=&CCLD_DVSN=00&ORD_GNO_BRNO=&ODNO=%s&INQR_DVSN_3=00&INQR_DVSN_1=&CTX_AREA_FK100=&CTX_AREA_NK100=",
+		c.accountNo, today, today, o.ID,
+	)
+
+	var resp dailyFillResponse
+	if err := c.doGet(ctx, path, query, c.trID(trDailyFills), &resp); err != nil {
+		return nil, err
+	}
+
+	for _, it := range resp.Output1 {
+		if strings.TrimLeft(it.OrderNo, "0") != strings.TrimLeft(o.ID, "0") {
+			continue
+		}
+		f := &models.OrderFill{}
+		f.Ordered, _ = strconv.ParseInt(it.OrderQty, 10, 64)
+		f.Filled, _ = strconv.ParseInt(it.FilledQt, 10, 64)
+		f.AvgPrice, _ = parsePrice(it.AvgPrice)
+		return f, nil
+	}
+	return nil, fmt.Errorf("order %s not found in daily fills", o.ID)
+}
+
+type askingPriceResponse struct {
+	Output1 struct {
+		Ask1 string `json:"askp1"` // 매도 1호가
+		Bid1 string `json:"bidp1"` // 매수 1호가
+	} `json:"output1"`
+}
+
+// GetBestQuote 최우선 매수/매도 호가
+func (c *Client) GetBestQuote(ctx context.Context, code string) (bid, ask float64, err error) {
+	path := "/uapi/domestic-stock/v1/quotations/inquire-asking-price-exp-ccn"
+	query := fmt.Sprintf("fid_cond_mrkt_div_code=J&fid_input_iscd=%s", code)
+
+	var resp askingPriceResponse
+	if err := c.doGet(ctx, path, query, trIDAskingPrice, &resp); err != nil {
+		return 0, 0, err
+	}
+	if bid, err = parsePrice(resp.Output1.Bid1); err != nil {
+		return 0, 0, fmt.Errorf("bidp1 %q: %w", resp.Output1.Bid1, err)
+	}
+	if ask, err = parsePrice(resp.Output1.Ask1); err != nil {
+		return 0, 0, fmt.Errorf("askp1 %q: %w", resp.Output1.Ask1, err)
+	}
+	return bid, ask, nil
+}
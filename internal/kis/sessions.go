@@ -0,0 +1,81 @@
+package kis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// 시간외 주문구분 (ORD_DVSN)
+const (
+	ordDvsnPreMarket   = "05" // 장전 시간외 (전일 종가)
+	ordDvsnAfterHours  = "06" // 장후 시간외 (당일 종가)
+	ordDvsnAfterSingle = "07" // 시간외 단일가 (지정가)
+)
+
+// sessionSpec 세션별 주문구분과 주문 가능 시간 (KST, [open, close))
+type sessionSpec struct {
+	ordDvsn     string
+	open, close string
+	limit       bool // 가격 필요 (시간외 단일가)
+}
+
+var sessionSpecs = map[string]sessionSpec{
+	models.SessionOpeningAuction: {ordDvsn: ordDvsnMarket, open: "08:30", close: "09:00"},
+	models.SessionPreMarket:      {ordDvsn: ordDvsnPreMarket, open: "08:30", close: "08:40"},
+	models.SessionClosingAuction: {ordDvsn: ordDvsnMarket, open: "15:20", close: "15:30"},
+	models.SessionAfterHours:     {ordDvsn: ordDvsnAfterHours, open: "15:40", close: "16:00"},
+	models.SessionAfterSingle:    {ordDvsn: ordDvsnAfterSingle, open: "16:00", close: "18:00", limit: true},
+}
+
+// CheckSession now(KST) 에 해당 세션 주문을 낼 수 있는지
+func CheckSession(session string, now time.Time) error {
+	spec, ok := sessionSpecs[session]
+	if !ok {
+		return fmt.Errorf("unknown order session %q", session)
+	}
+	hm := now.In(models.KST).Format("15:04")
+	if hm < spec.open || hm >= spec.close {
+		return fmt.Errorf("%s orders are accepted %s-%s KST (now %s)", session, spec.open, spec.close, hm)
+	}
+	return nil
+}
+
+// BuySession 정규장 외 세션 매수. 시간외 단일가만 price(지정가)를 쓰고, 나머지는 세션 기준가로 체결된다.
+func (c *Client) BuySession(ctx context.Context, code string, quantity int64, session string, price float64) (*models.BrokerOrder, error) {
+	return c.orderSession(ctx, c.trID(trCashBuy), "Buy", code, quantity, session, price)
+}
+
+// SellSession 정규장 외 세션 매도
+func (c *Client) SellSession(ctx context.Context, code string, quantity int64, session string, price float64) (*models.BrokerOrder, error) {
+	return c.orderSession(ctx, c.trID(trCashSell), "Sell", code, quantity, session, price)
+}
+
+func (c *Client) orderSession(ctx context.Context, trID, label, code string, quantity int64, session string, price float64) (*models.BrokerOrder, error) {
+	if err := CheckSession(session, time.Now()); err != nil {
+		return nil, err
+	}
+	ordDvsn, unitPrice, err := sessionOrder(session, price)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", session, code, err)
+	}
+	return c.orderCash(ctx, trID, label+"["+session+"]", code, quantity, ordDvsn, unitPrice)
+}
+
+// sessionOrder 세션 주문의 주문구분(ORD_DVSN)과 주문단가. 시간외 단일가만 지정가(원 단위 반올림)이고 나머지는 0.
+func sessionOrder(session string, price float64) (string, int64, error) {
+	spec, ok := sessionSpecs[session]
+	if !ok {
+		return "", 0, fmt.Errorf("unknown order session %q", session)
+	}
+	if !spec.limit {
+		return spec.ordDvsn, 0, nil
+	}
+	if price <= 0 {
+		return "", 0, fmt.Errorf("limit price required")
+	}
+	return spec.ordDvsn, int64(math.Round(price)), nil
+}
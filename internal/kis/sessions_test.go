@@ -0,0 +1,87 @@
+package kis
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+func TestCheckSession(t *testing.T) {
+	at := func(hm string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", "2025-06-02 "+hm, models.KST)
+		return t
+	}
+
+	tests := []struct {
+		session string
+		now     time.Time
+		ok      bool
+	}{
+		{session: models.SessionOpeningAuction, now: at("08:30"), ok: true},
+		{session: models.SessionOpeningAuction, now: at("08:59"), ok: true},
+		{session: models.SessionOpeningAuction, now: at("09:00")},
+		{session: models.SessionPreMarket, now: at("08:35"), ok: true},
+		{session: models.SessionPreMarket, now: at("08:40")},
+		{session: models.SessionClosingAuction, now: at("15:19")},
+		{session: models.SessionClosingAuction, now: at("15:25"), ok: true},
+		{session: models.SessionAfterHours, now: at("15:30")},
+		{session: models.SessionAfterHours, now: at("15:45"), ok: true},
+		{session: models.SessionAfterHours, now: at("16:00")},
+		{session: models.SessionAfterSingle, now: at("16:00"), ok: true},
+		{session: models.SessionAfterSingle, now: at("17:59"), ok: true},
+		{session: models.SessionAfterSingle, now: at("18:00")},
+		// 다른 시간대로 들어와도 KST 로 비교한다 (06:45 UTC = 15:45 KST).
+		{session: models.SessionAfterHours, now: at("15:45").UTC(), ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.session+" "+tt.now.Format("15:04"), func(t *testing.T) {
+			err := CheckSession(tt.session, tt.now)
+			if (err == nil) != tt.ok {
+				t.Errorf("CheckSession = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+
+	if err := CheckSession("night", at("20:00")); err == nil || !strings.Contains(err.Error(), "unknown order session") {
+		t.Errorf("CheckSession(night) = %v", err)
+	}
+}
+
+func TestSessionOrder(t *testing.T) {
+	tests := []struct {
+		session string
+		price   float64
+		ordDvsn string
+		unit    int64
+		err     string
+	}{
+		{session: models.SessionOpeningAuction, price: 35000, ordDvsn: "01"},
+		{session: models.SessionPreMarket, ordDvsn: "05"},
+		{session: models.SessionClosingAuction, ordDvsn: "01"},
+		{session: models.SessionAfterHours, price: 35000, ordDvsn: "06"},
+		{session: models.SessionAfterSingle, price: 35049.6, ordDvsn: "07", unit: 35050},
+		{session: models.SessionAfterSingle, err: "limit price required"},
+		{session: "night", err: "unknown order session"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.session, func(t *testing.T) {
+			ordDvsn, unit, err := sessionOrder(tt.session, tt.price)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("sessionOrder error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ordDvsn != tt.ordDvsn || unit != tt.unit {
+				t.Errorf("sessionOrder = %s %d, want %s %d", ordDvsn, unit, tt.ordDvsn, tt.unit)
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package marketdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/korean"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+)
+
+// CSV 레이아웃
+const (
+	LayoutKRX     = "krx"     // KRX 정보데이터시스템 내보내기 (일자,종가,대비,등락률,시가,...)
+	LayoutGeneric = "generic" // date,open,high,low,close,volume
+	LayoutKorean  = "korean"  // 날짜,시가,고가,저가,종가,거래량
+)
+
+type csvField int
+
+const (
+	fieldDate csvField = iota
+	fieldCode
+	fieldOpen
+	fieldHigh
+	fieldLow
+	fieldClose
+	fieldVolume
+)
+
+// 헤더 별칭 (소문자/공백 제거 후 비교)
+var headerAliases = map[string]csvField{
+	"date": fieldDate, "일자": fieldDate, "날짜": fieldDate, "기준일자": fieldDate,
+	"code": fieldCode, "symbol": fieldCode, "ticker": fieldCode, "종목코드": fieldCode, "단축코드": fieldCode,
+	"open": fieldOpen, "시가": fieldOpen,
+	"high": fieldHigh, "고가": fieldHigh,
+	"low": fieldLow, "저가": fieldLow,
+	"close": fieldClose, "종가": fieldClose, "현재가": fieldClose,
+	"volume": fieldVolume, "vol": fieldVolume, "거래량": fieldVolume,
+}
+
+// KRX 내보내기에만 있는 컬럼
+var krxOnlyHeaders = []string{"대비", "등락률", "거래대금", "상장주식수"}
+
+var dateLayouts = []string{"2006-01-02", "2006/01/02", "2006.01.02", "20060102"}
+
+// ImportOptions CSV 해석 옵션
+type ImportOptions struct {
+	// Code 종목코드 컬럼이 없는 파일에 쓸 코드. 비어 있으면 파일명(확장자 제외)을 쓴다.
+	Code string
+	// Date 날짜 컬럼이 없는 파일(KRX 전종목 시세)에 쓸 날짜
+	Date time.Time
+	// MaxGapDays 연속된 두 봉 사이 허용하는 최대 영업일 공백 (연휴 감안, 기본 5)
+	MaxGapDays int
+}
+
+// ImportReport 파일 하나의 해석/검증 결과
+type ImportReport struct {
+	Source      string
+	Layout      string
+	Rows        int
+	Candles     []models.Candle
+	Duplicates  []string // "code date" — 같은 값이면 하나만, 값이 다르면 나중 행을 쓴다
+	NonPositive []string // 가격 0 이하로 버린 행
+	Gaps        []string // "code from~to (N영업일)"
+}
+
+// HasGaps 큰 공백이 있으면 캐시 커버리지를 갱신하지 않는다.
+func (r *ImportReport) HasGaps() bool { return len(r.Gaps) > 0 }
+
+// ReadCSVFiles path 가 .zip 이면 안의 모든 .csv 를, 아니면 파일 하나를 읽는다.
+func ReadCSVFiles(path string, opt ImportOptions) ([]*ImportReport, error) {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		var out []*ImportReport
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(f.Name), ".csv") {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			rep, err := ParseCSV(rc, path+"!"+f.Name, optForFile(opt, f.Name))
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, rep)
+		}
+		return out, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rep, err := ParseCSV(f, path, optForFile(opt, path))
+	if err != nil {
+		return nil, err
+	}
+	return []*ImportReport{rep}, nil
+}
+
+func optForFile(opt ImportOptions, name string) ImportOptions {
+	if opt.Code == "" {
+		base := filepath.Base(name)
+		opt.Code = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	return opt
+}
+
+// ParseCSV 헤더로 레이아웃을 판별해 봉 데이터로 변환하고 검증한다.
+func ParseCSV(r io.Reader, source string, opt ImportOptions) (*ImportReport, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	raw = bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	if !utf8.Valid(raw) {
+		// KRX 정보데이터시스템 내보내기는 EUC-KR(CP949) 이다.
+		if raw, err = korean.EUCKR.NewDecoder().Bytes(raw); err != nil {
+			return nil, fmt.Errorf("%s: neither UTF-8 nor EUC-KR: %w", source, err)
+		}
+	}
+
+	cr := csv.NewReader(bytes.NewReader(raw))
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: empty file", source)
+	}
+
+	cols, layout, err := detectLayout(records[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	if _, ok := cols[fieldDate]; !ok && opt.Date.IsZero() {
+		return nil, fmt.Errorf("%s: no date column, date option required", source)
+	}
+
+	rep := &ImportReport{Source: source, Layout: layout}
+	byKey := map[string]int{}
+	for i, rec := range records[1:] {
+		line := i + 2
+		if isBlank(rec) {
+			continue
+		}
+		rep.Rows++
+		c, err := parseRow(rec, cols, opt)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", source, line, err)
+		}
+		key := c.Code + " " + storage.FormatDate(c.Date)
+		if c.Open <= 0 || c.High <= 0 || c.Low <= 0 || c.Close <= 0 {
+			rep.NonPositive = append(rep.NonPositive, fmt.Sprintf("%s (line %d)", key, line))
+			continue
+		}
+		if idx, dup := byKey[key]; dup {
+			rep.Duplicates = append(rep.Duplicates, key)
+			rep.Candles[idx] = c
+			continue
+		}
+		byKey[key] = len(rep.Candles)
+		rep.Candles = append(rep.Candles, c)
+	}
+
+	sort.Slice(rep.Candles, func(i, j int) bool {
+		if rep.Candles[i].Code != rep.Candles[j].Code {
+			return rep.Candles[i].Code < rep.Candles[j].Code
+		}
+		return rep.Candles[i].Date.Before(rep.Candles[j].Date)
+	})
+	rep.Gaps = findGaps(rep.Candles, opt.MaxGapDays)
+	return rep, nil
+}
+
+func detectLayout(header []string) (map[csvField]int, string, error) {
+	cols := map[csvField]int{}
+	korean, krx := false, false
+	for i, h := range header {
+		key := strings.ToLower(strings.Join(strings.Fields(h), ""))
+		if f, ok := headerAliases[key]; ok {
+			if _, seen := cols[f]; !seen {
+				cols[f] = i
+			}
+			if key[0] >= utf8.RuneSelf {
+				korean = true
+			}
+		}
+		for _, k := range krxOnlyHeaders {
+			if key == k {
+				krx = true
+			}
+		}
+	}
+	for _, f := range []csvField{fieldOpen, fieldHigh, fieldLow, fieldClose, fieldVolume} {
+		if _, ok := cols[f]; !ok {
+			return nil, "", fmt.Errorf("unrecognized header %v (need date/open/high/low/close/volume)", header)
+		}
+	}
+	switch {
+	case krx:
+		return cols, LayoutKRX, nil
+	case korean:
+		return cols, LayoutKorean, nil
+	default:
+		return cols, LayoutGeneric, nil
+	}
+}
+
+func parseRow(rec []string, cols map[csvField]int, opt ImportOptions) (models.Candle, error) {
+	get := func(f csvField) string {
+		i, ok := cols[f]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	c := models.Candle{Code: opt.Code}
+	if code := get(fieldCode); code != "" {
+		// HTS 식 "A005930" 표기 허용
+		if len(code) == 7 && code[0] == 'A' {
+			code = code[1:]
+		}
+		c.Code = code
+	}
+	if c.Code == "" {
+		return c, errors.New("no code")
+	}
+
+	if s := get(fieldDate); s != "" {
+		d, err := parseDate(s)
+		if err != nil {
+			return c, err
+		}
+		c.Date = d
+	} else {
+		c.Date = day(opt.Date)
+	}
+
+	var err error
+	for _, p := range []struct {
+		f   csvField
+		dst *float64
+	}{{fieldOpen, &c.Open}, {fieldHigh, &c.High}, {fieldLow, &c.Low}, {fieldClose, &c.Close}} {
+		if *p.dst, err = parseNumber(get(p.f)); err != nil {
+			return c, err
+		}
+	}
+	vol, err := parseNumber(get(fieldVolume))
+	if err != nil {
+		return c, err
+	}
+	c.Volume = int64(vol)
+	return c, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, models.KST); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+func parseNumber(s string) (float64, error) {
+	s = strings.ReplaceAll(s, ",", "")
+	if s == "" || s == "-" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
+
+func isBlank(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// findGaps 종목별로 연속된 두 봉 사이 영업일(월~금) 공백이 maxGap 을 넘는 구간
+func findGaps(candles []models.Candle, maxGap int) []string {
+	if maxGap <= 0 {
+		maxGap = 5
+	}
+	var gaps []string
+	for i := 1; i < len(candles); i++ {
+		prev, cur := candles[i-1], candles[i]
+		if prev.Code != cur.Code {
+			continue
+		}
+		missing := 0
+		for d := prev.Date.AddDate(0, 0, 1); d.Before(cur.Date); d = d.AddDate(0, 0, 1) {
+			if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+				missing++
+			}
+		}
+		if missing > maxGap {
+			gaps = append(gaps, fmt.Sprintf("%s %s~%s (%d영업일)",
+				cur.Code, storage.FormatDate(prev.Date), storage.FormatDate(cur.Date), missing))
+		}
+	}
+	return gaps
+}
+
+// StoreImported 검증된 봉을 캐시에 저장한다. 공백이 없으면 기존 커버리지와 이어지는 경우 커버리지를 넓힌다.
+func StoreImported(ctx context.Context, repo storage.CandleRepository, rep *ImportReport) error {
+	if err := repo.UpsertCandles(ctx, storage.IntervalDaily, rep.Candles); err != nil {
+		return err
+	}
+	if rep.HasGaps() {
+		return nil
+	}
+
+	byCode := map[string][2]time.Time{}
+	for _, c := range rep.Candles {
+		r, ok := byCode[c.Code]
+		if !ok {
+			r = [2]time.Time{c.Date, c.Date}
+		}
+		if c.Date.Before(r[0]) {
+			r[0] = c.Date
+		}
+		if c.Date.After(r[1]) {
+			r[1] = c.Date
+		}
+		byCode[c.Code] = r
+	}
+	for code, r := range byCode {
+		from, to := r[0], r[1]
+		covFrom, covTo, ok, err := repo.Coverage(ctx, code, storage.IntervalDaily)
+		if err != nil {
+			return err
+		}
+		if ok {
+			// 기존 구간과 겹치거나 맞닿을 때만 합친다 (주말 사이는 맞닿은 것으로 본다).
+			if from.After(covTo.AddDate(0, 0, 4)) || to.Before(covFrom.AddDate(0, 0, -4)) {
+				continue
+			}
+			if covFrom.Before(from) {
+				from = covFrom
+			}
+			if covTo.After(to) {
+				to = covTo
+			}
+		}
+		if err := repo.SetCoverage(ctx, code, storage.IntervalDaily, from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
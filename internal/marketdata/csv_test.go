@@ -0,0 +1,125 @@
+package marketdata
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/korean"
+
+	"stock-investing/internal/models"
+)
+
+func TestParseCSV(t *testing.T) {
+	eucKR := func(s string) string {
+		out, err := korean.EUCKR.NewEncoder().String(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	listDate := time.Date(2025, 3, 4, 0, 0, 0, 0, models.KST)
+
+	tests := []struct {
+		name        string
+		src         string
+		opt         ImportOptions
+		layout      string
+		candles     []string // "code date close volume"
+		duplicates  []string
+		nonPositive int
+		gaps        int
+		err         string
+	}{
+		{
+			name:    "generic with code from options",
+			src:     "date,open,high,low,close,volume\n2025-03-04,10,12,9,11,1000\n2025-03-05,11,13,10,12,\"1,500\"\n",
+			opt:     ImportOptions{Code: "005930"},
+			layout:  LayoutGeneric,
+			candles: []string{"005930 2025-03-04 11 1000", "005930 2025-03-05 12 1500"},
+		},
+		{
+			name:    "korean headers, BOM and sorted output",
+			src:     "\xef\xbb\xbf날짜,시가,고가,저가,종가,거래량\n2025.03.05,11,13,10,12,20\n2025.03.04,10,12,9,11,10\n",
+			opt:     ImportOptions{Code: "069500"},
+			layout:  LayoutKorean,
+			candles: []string{"069500 2025-03-04 11 10", "069500 2025-03-05 12 20"},
+		},
+		{
+			name:    "KRX export in EUC-KR without a date column",
+			src:     eucKR("종목코드,종목명,종가,대비,등락률,시가,고가,저가,거래량\nA005930,삼성전자,\"55,000\",500,0.92,\"54,500\",\"55,500\",\"54,000\",\"12,345\"\n"),
+			opt:     ImportOptions{Date: listDate},
+			layout:  LayoutKRX,
+			candles: []string{"005930 2025-03-04 55000 12345"},
+		},
+		{
+			name:       "duplicates keep the later row",
+			src:        "date,code,open,high,low,close,volume\n20250304,A,10,10,10,10,1\n20250304,A,11,11,11,11,2\n",
+			layout:     LayoutGeneric,
+			candles:    []string{"A 2025-03-04 11 2"},
+			duplicates: []string{"A 2025-03-04"},
+		},
+		{
+			name:        "non-positive prices are dropped",
+			src:         "date,code,open,high,low,close,volume\n2025-03-04,A,0,0,0,0,0\n2025-03-05,A,1,1,1,1,1\n",
+			layout:      LayoutGeneric,
+			candles:     []string{"A 2025-03-05 1 1"},
+			nonPositive: 1,
+		},
+		{
+			name:    "long gaps are reported",
+			src:     "date,code,open,high,low,close,volume\n2025-03-03,A,1,1,1,1,1\n2025-03-17,A,1,1,1,1,1\n",
+			layout:  LayoutGeneric,
+			candles: []string{"A 2025-03-03 1 1", "A 2025-03-17 1 1"},
+			gaps:    1,
+		},
+		{
+			name:    "weekends and short holidays are not gaps",
+			src:     "date,code,open,high,low,close,volume\n2025-02-28,A,1,1,1,1,1\n2025-03-04,A,1,1,1,1,1\n",
+			layout:  LayoutGeneric,
+			candles: []string{"A 2025-02-28 1 1", "A 2025-03-04 1 1"},
+		},
+		{name: "empty file", src: "", err: "empty file"},
+		{name: "unknown header", src: "a,b,c\n1,2,3\n", err: "unrecognized header"},
+		{name: "date column required", src: "code,open,high,low,close,volume\nA,1,1,1,1,1\n", err: "date option required"},
+		{name: "invalid date", src: "date,code,open,high,low,close,volume\n03/04/2025,A,1,1,1,1,1\n", err: "x.csv:2: invalid date"},
+		{name: "invalid number", src: "date,code,open,high,low,close,volume\n2025-03-04,A,1,x,1,1,1\n", err: "invalid number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep, err := ParseCSV(strings.NewReader(tt.src), "x.csv", tt.opt)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseCSV error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rep.Layout != tt.layout {
+				t.Errorf("layout = %s, want %s", rep.Layout, tt.layout)
+			}
+			var got []string
+			for _, c := range rep.Candles {
+				got = append(got, strings.Join([]string{c.Code, c.Date.Format("2006-01-02"), ftoa(c.Close), ftoa(float64(c.Volume))}, " "))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.candles, "|") {
+				t.Errorf("candles = %v, want %v", got, tt.candles)
+			}
+			if strings.Join(rep.Duplicates, "|") != strings.Join(tt.duplicates, "|") {
+				t.Errorf("duplicates = %v, want %v", rep.Duplicates, tt.duplicates)
+			}
+			if len(rep.NonPositive) != tt.nonPositive || len(rep.Gaps) != tt.gaps {
+				t.Errorf("non-positive %v gaps %v, want %d %d", rep.NonPositive, rep.Gaps, tt.nonPositive, tt.gaps)
+			}
+			if rep.HasGaps() != (tt.gaps > 0) {
+				t.Errorf("HasGaps = %v", rep.HasGaps())
+			}
+		})
+	}
+}
+
+func ftoa(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
@@ -0,0 +1,238 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+// CandleFetcher 원천 일봉 공급자 (kis.Client)
+type CandleFetcher interface {
+	GetDailyCandles(ctx context.Context, code string, from, to time.Time) ([]models.Candle, error)
+}
+
+// QuoteFetcher 원천 시세 공급자 (kis.Client)
+type QuoteFetcher interface {
+	GetQuoteDetail(ctx context.Context, code string) (*models.Quote, error)
+}
+
+// 장 마감(15:30) 후 종가 확정까지 여유를 두고 당일 봉을 캐시 대상으로 본다.
+const sessionCloseHour, sessionCloseMinute = 16, 0
+
+// 캐시가 없는 종목을 장 마감 갱신할 때 가져올 기본 기간 (달력일)
+const defaultBackfillDays = 400
+
+// Service 일봉은 SQLite 캐시를 거쳐 빠진 구간만 KIS 에서 받고,
+// 시세는 짧은 TTL 메모리 캐시로 같은 실행 안의 중복 호출을 없앤다.
+// kis.Client 와 같은 메서드 시그니처라 스크리너/전략에 그대로 끼울 수 있다.
+type Service struct {
+	candles CandleFetcher
+	quotes  QuoteFetcher
+	repo    storage.CandleRepository
+
+	quoteTTL time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	quoteCache map[string]cachedQuote
+}
+
+type cachedQuote struct {
+	quote *models.Quote
+	at    time.Time
+}
+
+func NewService(candles CandleFetcher, quotes QuoteFetcher, repo storage.CandleRepository, quoteTTL time.Duration) *Service {
+	return &Service{
+		candles:    candles,
+		quotes:     quotes,
+		repo:       repo,
+		quoteTTL:   quoteTTL,
+		now:        time.Now,
+		quoteCache: map[string]cachedQuote{},
+	}
+}
+
+// ===== 일봉 =====
+
+// GetDailyCandles [from, to] 일봉을 날짜 오름차순으로 반환한다.
+// 확정된 거래일 구간은 캐시에서 읽고, 캐시 범위 밖 구간만 원천에서 받아 저장한다.
+// 장중 당일 봉은 저장하지 않고 매번 원천에서 받는다.
+func (s *Service) GetDailyCandles(ctx context.Context, code string, from, to time.Time) ([]models.Candle, error) {
+	from, to = day(from), day(to)
+	lastClosed := s.lastClosedDay()
+	closedTo := to
+	if closedTo.After(lastClosed) {
+		closedTo = lastClosed
+	}
+
+	var out []models.Candle
+	if !from.After(closedTo) {
+		if err := s.fill(ctx, code, from, closedTo); err != nil {
+			return nil, err
+		}
+		cached, err := s.repo.GetCandles(ctx, code, storage.IntervalDaily, from, closedTo)
+		if err != nil {
+			return nil, err
+		}
+		out = cached
+	}
+
+	if to.After(lastClosed) {
+		liveFrom := lastClosed.AddDate(0, 0, 1)
+		if from.After(liveFrom) {
+			liveFrom = from
+		}
+		live, err := s.candles.GetDailyCandles(ctx, code, liveFrom, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range live {
+			if len(out) == 0 || c.Date.After(out[len(out)-1].Date) {
+				out = append(out, c)
+			}
+		}
+	}
+	return out, nil
+}
+
+// fill 캐시 커버리지를 [from, to] 를 포함하도록 넓힌다. 커버리지는 항상 연속 구간으로 유지한다.
+func (s *Service) fill(ctx context.Context, code string, from, to time.Time) error {
+	covFrom, covTo, ok, err := s.repo.Coverage(ctx, code, storage.IntervalDaily)
+	if err != nil {
+		return err
+	}
+
+	type span struct{ from, to time.Time }
+	var missing []span
+	newFrom, newTo := from, to
+	if !ok {
+		missing = append(missing, span{from, to})
+	} else {
+		newFrom, newTo = covFrom, covTo
+		if from.Before(covFrom) {
+			missing = append(missing, span{from, covFrom.AddDate(0, 0, -1)})
+			newFrom = from
+		}
+		if to.After(covTo) {
+			// 요청 구간이 커버리지와 떨어져 있어도 사이 구간까지 받아 연속성을 유지한다.
+			missing = append(missing, span{covTo.AddDate(0, 0, 1), to})
+			newTo = to
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	for _, m := range missing {
+		candles, err := s.candles.GetDailyCandles(ctx, code, m.from, m.to)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.UpsertCandles(ctx, storage.IntervalDaily, candles); err != nil {
+			return err
+		}
+		logger.Info.Printf("[marketdata] %s: cached %d candles %s~%s\n",
+			code, len(candles), storage.FormatDate(m.from), storage.FormatDate(m.to))
+	}
+	return s.repo.SetCoverage(ctx, code, storage.IntervalDaily, newFrom, newTo)
+}
+
+// UpdateEndOfDay 캐시가 있는 모든 종목과 codes 의 일봉을 마지막 확정 거래일까지 갱신한다.
+// 장 마감 후 1회 실행하면 다음 날 전략/스크리너는 캐시만으로 동작한다.
+func (s *Service) UpdateEndOfDay(ctx context.Context, codes []string) (int, error) {
+	covered, err := s.repo.CoveredCodes(ctx, storage.IntervalDaily)
+	if err != nil {
+		return 0, err
+	}
+	seen := map[string]bool{}
+	var all []string
+	for _, c := range append(covered, codes...) {
+		if !seen[c] {
+			seen[c] = true
+			all = append(all, c)
+		}
+	}
+
+	lastClosed := s.lastClosedDay()
+	updated := 0
+	for _, code := range all {
+		select {
+		case <-ctx.Done():
+			return updated, ctx.Err()
+		default:
+		}
+
+		from := lastClosed.AddDate(0, 0, -defaultBackfillDays)
+		if covFrom, _, ok, err := s.repo.Coverage(ctx, code, storage.IntervalDaily); err != nil {
+			return updated, err
+		} else if ok {
+			from = covFrom
+		}
+		if err := s.fill(ctx, code, from, lastClosed); err != nil {
+			logger.Error.Printf("[marketdata] end-of-day update %s: %v\n", code, err)
+			continue
+		}
+		updated++
+	}
+	logger.Info.Printf("[marketdata] end-of-day update: %d/%d codes up to %s\n", updated, len(all), storage.FormatDate(lastClosed))
+	return updated, nil
+}
+
+// lastClosedDay 종가가 확정된 마지막 날짜 (휴장일 여부는 따지지 않는다)
+func (s *Service) lastClosedDay() time.Time {
+	now := s.now().In(models.KST)
+	today := day(now)
+	closeAt := today.Add(sessionCloseHour*time.Hour + sessionCloseMinute*time.Minute)
+	if now.Before(closeAt) {
+		return today.AddDate(0, 0, -1)
+	}
+	return today
+}
+
+func day(t time.Time) time.Time {
+	t = t.In(models.KST)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, models.KST)
+}
+
+// ===== 시세 =====
+
+// GetQuoteDetail quoteTTL 안에 같은 종목을 다시 조회하면 캐시를 돌려준다.
+func (s *Service) GetQuoteDetail(ctx context.Context, code string) (*models.Quote, error) {
+	s.mu.Lock()
+	if c, ok := s.quoteCache[code]; ok && s.now().Sub(c.at) < s.quoteTTL {
+		s.mu.Unlock()
+		return c.quote, nil
+	}
+	s.mu.Unlock()
+
+	q, err := s.quotes.GetQuoteDetail(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.quoteCache[code] = cachedQuote{quote: q, at: s.now()}
+	s.mu.Unlock()
+	return q, nil
+}
+
+// GetQuote kis.Client.GetQuote 와 같은 시그니처의 캐시 조회
+func (s *Service) GetQuote(ctx context.Context, code string) (float64, error) {
+	q, err := s.GetQuoteDetail(ctx, code)
+	if err != nil {
+		return 0, err
+	}
+	return q.Price, nil
+}
+
+// InvalidateQuotes 주문 체결 직후 등 최신 시세가 꼭 필요할 때 캐시를 비운다.
+func (s *Service) InvalidateQuotes() {
+	s.mu.Lock()
+	s.quoteCache = map[string]cachedQuote{}
+	s.mu.Unlock()
+}
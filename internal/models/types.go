@@ -2,6 +2,9 @@ package models
 
 import "time"
 
+// KST 한국 거래소 기준 시간대. 봉 날짜는 이 시간대의 자정으로 표현한다.
+var KST = time.FixedZone("KST", 9*60*60)
+
 type Stock struct {
 	Code   string
 	Name   string
@@ -11,22 +14,220 @@ type Stock struct {
 type Trade struct {
 	ID       int64
 	Code     string
-	Side     string // "BUY" or "SELL"
+	Side     string // "BUY", "SELL" 또는 기업 행위 보정 "ADJUST"/"REMAP" (Quantity 는 증감, Price 는 늘어난 주식의 주당 원가)
 	Quantity int64
 	Price    float64
 	Time     time.Time
 	Strategy string // "stable", "aggressive", ...
 }
 
+// OrderIntent 전략이 내는 주문 의도. 실제 수량 확정/리스크 체크/제출/기록은 execution 패키지가 한다.
+type OrderIntent struct {
+	Strategy    string
+	Code        string
+	Side        string  // "BUY" or "SELL"
+	Quantity    int64   // 0 이면 TargetValue 로 수량 계산
+	TargetValue float64 // 원 단위 목표 금액
+	LimitPrice  float64 // 0 이면 시장가 (수량 계산은 현재가 기준)
+	Reason      string
+	// Session 비어 있으면 정규장 주문. 시간외/동시호가 세션은 Session* 상수.
+	Session string
+}
+
+// 주문 세션 (KRX). 정규장 외 세션은 해당 시간대에만 낼 수 있다.
+const (
+	SessionRegular        = ""                   // 정규장 09:00~15:20 시장가/지정가
+	SessionOpeningAuction = "opening_auction"    // 장 시작 동시호가 08:30~09:00, 09:00 시가 단일가
+	SessionPreMarket      = "pre_market"         // 장전 시간외 08:30~08:40, 전일 종가
+	SessionClosingAuction = "closing_auction"    // 장 마감 동시호가 15:20~15:30, 종가 단일가
+	SessionAfterHours     = "after_hours"        // 장후 시간외 15:40~16:00, 당일 종가
+	SessionAfterSingle    = "after_hours_single" // 시간외 단일가 16:00~18:00, 종가 ±10% 지정가
+)
+
+// 주문 처리 결과
+const (
+	OrderFilled   = "filled"
+	OrderRejected = "rejected" // 리스크 체크/수량 부족 등으로 제출하지 않음
+	OrderFailed   = "failed"   // 제출 실패
+	OrderNetted   = "netted"   // 반대 주문과 상계되어 제출하지 않음
+)
+
+// Order 주문 의도 하나의 처리 기록
+type Order struct {
+	ID        int64
+	Intent    OrderIntent
+	Status    string
+	Quantity  int64 // 제출(체결) 수량
+	Price     float64
+	Error     string
+	CreatedAt time.Time
+	// ArrivalPrice 알고리즘 주문 시작 시점의 중간 호가 (Price 와 비교해 슬리피지를 본다, 0 이면 미사용)
+	ArrivalPrice float64
+}
+
 type Position struct {
 	Code     string
 	Quantity int64
 	AvgPrice float64
+	Strategy string
+	OpenedAt time.Time // 마지막으로 0주에서 진입한 시각
+	Realized float64   // 진입 이후 부분 매도로 실현한 손익
+}
+
+// ClosedPosition 0주가 되어 닫힌 포지션 (진입부터 청산까지의 실현 손익)
+type ClosedPosition struct {
+	Strategy string
+	Code     string
+	OpenedAt time.Time
+	ClosedAt time.Time
+	Realized float64
+}
+
+// Balance 계좌 평가 요약 (원)
+type Balance struct {
+	Cash        float64 // 예수금
+	StockValue  float64 // 보유주식 평가금액
+	TotalEquity float64 // 예수금 + 평가금액
+}
+
+// PositionStop 포지션별 스탑 상태. 실행 간에 유지된다.
+type PositionStop struct {
+	Strategy       string
+	Code           string
+	OpenedAt       time.Time // 어떤 진입에 대한 상태인지 (재진입 시 초기화)
+	EntryPrice     float64
+	HighWater      float64 // 진입 후 최고가
+	StopPrice      float64 // 현재 스탑 가격 (올라가기만 한다)
+	BreakevenArmed bool    // 본전 스탑으로 올라갔는지
+	UpdatedAt      time.Time
 }
 
 type DailyPnL struct {
+	Date        time.Time
+	StartEquity float64 // 장 시작 기준 평가금액 (전 거래일 마지막 기록)
+	Equity      float64 // 당일 마지막으로 확인한 평가금액
+	Profit      float64 // Equity - StartEquity
+	Drawdown    float64 // 기록된 최고 평가금액 대비 하락률 (0.05 = -5%)
+}
+
+// Candle 일봉(또는 분봉) OHLCV
+type Candle struct {
+	Code   string
+	Date   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume int64
+}
+
+// Quote 현재가 시세 + 스크리닝에 필요한 종목 상태 정보
+type Quote struct {
+	Code         string
+	Price        float64
+	Volume       int64
+	TradingValue float64 // 누적 거래대금 (원)
+	MarketCap    float64 // 시가총액 (원)
+	Halted       bool    // 거래정지
+	Managed      bool    // 관리종목
+	Warning      bool    // 투자경고/위험
+	Caution      bool    // 투자주의
+	Liquidation  bool    // 정리매매
+}
+
+// ScreenRun 스크리너 1회 실행 스냅샷
+type ScreenRun struct {
+	ID         int64
+	Name       string
+	Params     string // 스크리너 설정 JSON
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Candidates []ScreenCandidate
+}
+
+// ScreenCandidate 스크리닝에서 평가된 종목 1건
+type ScreenCandidate struct {
+	Code      string
+	Name      string
+	Market    string
+	Rank      int // 선정 순위 (1부터, 탈락 종목은 0)
+	Selected  bool
+	Score     float64
+	DroppedBy string             // 탈락시킨 단계/사유 (선정 종목은 "")
+	Values    map[string]float64 // 지표/점수 값
+}
+
+// BrokerOrder 브로커에 접수된 주문 번호 (취소/체결 조회 키)
+type BrokerOrder struct {
+	Branch string // 주문 조직번호 (KRX_FWDG_ORD_ORGNO)
+	ID     string // 주문번호 (ODNO)
+}
+
+// OrderFill 주문 번호별 체결 현황
+type OrderFill struct {
+	Ordered  int64
+	Filled   int64
+	AvgPrice float64 // 체결 평균가 (미체결이면 0)
+}
+
+// Dividend 보유 종목의 배당/분배금 이벤트 (전략별 권리 수량 기준)
+type Dividend struct {
+	ID           int64
+	Strategy     string
+	Code         string
+	ExDate       time.Time // 배당락일 (전일 종가 기준 보유분에 권리)
+	PayDate      time.Time
+	PerShare     float64
+	Quantity     int64
+	Gross        float64
+	Tax          float64
+	Net          float64 // 세후 입금액
+	Source       string
+	PostedAt     time.Time // 현금 원장 반영 시각 (zero 면 미반영)
+	ReinvestedAt time.Time // 재투자 예산에 넣은 시각 (zero 면 미사용)
+}
+
+// LedgerEntry 현금 원장 항목 (입금 +, 출금 -)
+type LedgerEntry struct {
+	ID       int64
+	Strategy string
 	Date     time.Time
-	Equity   float64
-	Profit   float64
-	Drawdown float64
+	Kind     string // dividend 등
+	Code     string
+	Amount   float64
+	Ref      string
+	Note     string
+}
+
+// 기업 행위 종류
+const (
+	CorpSplit = "split" // 액면분할/병합: 1주 → Ratio 주
+	CorpBonus = "bonus" // 무상증자: 1주당 Ratio 주 추가
+	CorpRemap = "remap" // 합병/코드 변경: 1주 → NewCode Ratio 주
+)
+
+// 기업 행위로 수량/원가를 맞추는 체결 기록
+const (
+	SideAdjust = "ADJUST" // 분할/무상증자 (단수주 정리 포함)
+	SideRemap  = "REMAP"  // 코드 변경: 기존 코드 전량 차감(음수) 뒤 새 코드 편입(양수). 청산이 아니라 실현 손익을 새 코드로 잇는다
+)
+
+// CorporateAction 분할/무상증자/코드 변경. ExDate 전날까지의 보유분과 시세에 적용한다.
+type CorporateAction struct {
+	ID        int64
+	Code      string
+	Kind      string
+	ExDate    time.Time
+	Ratio     float64
+	NewCode   string // remap 만
+	Note      string
+	AppliedAt time.Time // zero 면 미적용
+}
+
+// Factor 기존 1주가 몇 주가 되는지 (과거 가격은 이 값으로 나누고 거래량은 곱한다)
+func (a *CorporateAction) Factor() float64 {
+	if a.Kind == CorpBonus {
+		return 1 + a.Ratio
+	}
+	return a.Ratio
 }
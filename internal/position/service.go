@@ -0,0 +1,114 @@
+// Package position 체결 기록을 순서대로 반영해 전략별 보유 포지션(수량, 가중평균 단가, 실현 손익)을 유지한다.
+package position
+
+import (
+	"context"
+	"sync"
+
+	"stock-investing/internal/execution"
+	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+// TradeSource 아직 반영하지 않은 체결 조회 (storage.Repository 가 구현)
+type TradeSource interface {
+	ListTradesAfter(ctx context.Context, afterID int64) ([]*models.Trade, error)
+}
+
+type Service struct {
+	repo   storage.PositionRepository
+	trades TradeSource
+	mu     sync.Mutex
+}
+
+func NewService(repo storage.PositionRepository, trades TradeSource) *Service {
+	return &Service{repo: repo, trades: trades}
+}
+
+type key struct{ strategy, code string }
+
+// Sync 마지막으로 반영한 이후의 체결을 id 순으로 포지션에 반영한다. 반영한 체결 수를 반환한다.
+// 매수는 가중평균 단가, 매도는 실현 손익(보유분까지만), 0주가 되면 청산 기록으로 옮긴다.
+func (s *Service) Sync(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	from, err := s.repo.LastTradeID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	trades, err := s.trades.ListTradesAfter(ctx, from)
+	if err != nil || len(trades) == 0 {
+		return 0, err
+	}
+	open, err := s.repo.ListPositions(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+	book := map[key]*models.Position{}
+	for _, p := range open {
+		book[key{p.Strategy, p.Code}] = p
+	}
+
+	var changed []*models.Position
+	var closed []*models.ClosedPosition
+	seen := map[key]bool{}
+	carry := execution.RemapCarry{}
+	for _, t := range trades {
+		k := key{t.Strategy, t.Code}
+		p, ok := book[k]
+		if !ok {
+			p = &models.Position{Strategy: t.Strategy, Code: t.Code}
+			book[k] = p
+		}
+		wasOpen, openedAt := p.Quantity > 0, p.OpenedAt
+
+		if t.Side == "SELL" && t.Quantity > p.Quantity {
+			logger.Error.Printf("[positions] %s sell %s x %d exceeds held %d (trade %d)\n", t.Strategy, t.Code, t.Quantity, p.Quantity, t.ID)
+		}
+		realized, remapped := carry.Apply(p, t)
+		if realized != 0 {
+			logger.Info.Printf("[positions] %s %s realized %.0f (left %d @ %.2f)\n", t.Strategy, t.Code, realized, p.Quantity, p.AvgPrice)
+		}
+		if remapped {
+			logger.Info.Printf("[positions] %s %s remapped, realized %.0f carried to the new code\n", t.Strategy, t.Code, carry[t.Strategy].Realized)
+		} else if wasOpen && p.Quantity == 0 {
+			closed = append(closed, &models.ClosedPosition{
+				Strategy: t.Strategy, Code: t.Code, OpenedAt: openedAt, ClosedAt: t.Time, Realized: p.Realized,
+			})
+			logger.Info.Printf("[positions] %s %s closed, realized %.0f\n", t.Strategy, t.Code, p.Realized)
+		}
+		if !seen[k] {
+			seen[k] = true
+			changed = append(changed, p)
+		}
+	}
+
+	last := trades[len(trades)-1].ID
+	ok, err := s.repo.SavePositions(ctx, changed, closed, from, last)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		// 다른 프로세스가 먼저 반영했다.
+		return 0, nil
+	}
+	return len(trades), nil
+}
+
+// Positions Sync 후 전략의 보유 포지션 (종목코드 순)
+func (s *Service) Positions(ctx context.Context, strategy string) ([]*models.Position, error) {
+	if _, err := s.Sync(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPositions(ctx, strategy)
+}
+
+// Rebuild 포지션을 지우고 전체 체결 기록으로 다시 만든다.
+func (s *Service) Rebuild(ctx context.Context) (int, error) {
+	if err := s.repo.ResetPositions(ctx); err != nil {
+		return 0, err
+	}
+	return s.Sync(ctx)
+}
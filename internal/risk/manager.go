@@ -3,19 +3,33 @@ package risk
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
 
 	"stock-investing/internal/models"
+	"stock-investing/internal/storage"
 	"stock-investing/pkg/logger"
 )
 
 type Manager interface {
+	// CheckMaxLoss 당일 손실(장 시작 평가금액 대비)과 최고 평가금액 대비 낙폭을 한도와 비교한다.
+	// 한도를 넘으면 *LossLimitError (errors.Is(err, ErrLossLimit)) 를 반환하고, 전략은 신규 매수를 멈춘다.
 	CheckMaxLoss(ctx context.Context, equity float64) error
+	// RecordEquity 오늘의 평가금액을 daily_pnl 에 남긴다 (장 마감 후 스냅샷이 다음 날의 시작 평가금액이 된다).
+	RecordEquity(ctx context.Context, equity float64) (*models.DailyPnL, error)
 	CheckPositionSize(ctx context.Context, equity float64, newPositionValue float64) error
+	// MaxPositionValue 종목당 허용되는 최대 포지션 금액 (sizer 결과를 자르는 데 쓴다)
+	MaxPositionValue(equity float64) float64
 	CheckThemeConcentration(ctx context.Context, positions []models.Position) error
+	// CheckCashRatio 주문 후 예상 현금이 MinCashRatio × equity 이상인지
+	CheckCashRatio(ctx context.Context, equity float64, cashAfter float64) error
 }
 
 type Config struct {
-	MaxRiskRatio     float64 // e.g. 0.1 = 최대 손실 10%
+	MaxRiskRatio     float64 // e.g. 0.1 = 최고 평가금액 대비 누적 손실 10%
+	DailyLossLimit   float64 // e.g. 0.03 = 장 시작 대비 당일 손실 3%
 	MaxPositionRatio float64 // e.g. 0.05 = 종목당 5%
 	MaxThemeRatio    float64 // e.g. 0.5 = 테마당 50%
 	MinCashRatio     float64 // e.g. 0.2 = 현금 20% 유지
@@ -23,18 +37,113 @@ type Config struct {
 
 type manager struct {
 	cfg Config
+	pnl storage.PnLRepository // nil 이면 손실 한도를 확인하지 않는다
+	mu  sync.Mutex            // hybrid 슬리브들이 같은 날짜 행을 동시에 갱신하지 않도록
 }
 
-func NewManager(cfg Config) Manager {
-	return &manager{cfg: cfg}
+func NewManager(cfg Config, pnl storage.PnLRepository) Manager {
+	return &manager{cfg: cfg, pnl: pnl}
 }
 
+// 손실 한도 종류. errors.Is 로 ErrLossLimit 또는 종류별 sentinel 을 확인한다.
+var (
+	ErrLossLimit      = errors.New("loss limit reached")
+	ErrDailyLossLimit = errors.New("daily loss limit reached")
+	ErrDrawdownLimit  = errors.New("drawdown limit reached")
+)
+
+// LossLimitError 손실 한도 초과. Loss/Limit 은 비율 (0.035 = 3.5% 손실).
+type LossLimitError struct {
+	Kind      error   // ErrDailyLossLimit | ErrDrawdownLimit
+	Loss      float64 // 기준 대비 손실률
+	Limit     float64
+	Reference float64 // 기준 평가금액 (장 시작 또는 최고)
+	Equity    float64
+}
+
+func (e *LossLimitError) Error() string {
+	return fmt.Sprintf("%v: loss %.2f%% >= %.2f%% (equity %.0f vs %.0f)",
+		e.Kind, e.Loss*100, e.Limit*100, e.Equity, e.Reference)
+}
+
+func (e *LossLimitError) Is(target error) bool {
+	return target == ErrLossLimit || target == e.Kind
+}
+
+// CheckMaxLoss 평가금액을 기록한 뒤 당일 손실, 낙폭 순으로 한도를 확인한다.
+// 기록/조회 실패도 에러로 반환하므로 호출 측은 어느 쪽이든 매수를 멈춘다.
 func (m *manager) CheckMaxLoss(ctx context.Context, equity float64) error {
-	logger.Info.Printf("[risk] CheckMaxLoss stub: equity=%.2f, maxRisk=%.2f\n", equity, m.cfg.MaxRiskRatio)
-	// TODO: 실제 계좌 손실률 계산 후, 한도를 넘으면 에러 반환
+	if m.pnl == nil {
+		return nil
+	}
+	day, err := m.RecordEquity(ctx, equity)
+	if err != nil {
+		return fmt.Errorf("record equity: %w", err)
+	}
+	logger.Info.Printf("[risk] equity=%.0f start=%.0f daily=%+.2f%% drawdown=%.2f%%\n",
+		day.Equity, day.StartEquity, dailyReturn(day)*100, day.Drawdown*100)
+
+	if loss := -dailyReturn(day); m.cfg.DailyLossLimit > 0 && loss >= m.cfg.DailyLossLimit {
+		return &LossLimitError{Kind: ErrDailyLossLimit, Loss: loss, Limit: m.cfg.DailyLossLimit, Reference: day.StartEquity, Equity: equity}
+	}
+	if m.cfg.MaxRiskRatio > 0 && day.Drawdown >= m.cfg.MaxRiskRatio {
+		peak := equity / (1 - day.Drawdown)
+		return &LossLimitError{Kind: ErrDrawdownLimit, Loss: day.Drawdown, Limit: m.cfg.MaxRiskRatio, Reference: peak, Equity: equity}
+	}
 	return nil
 }
 
+// RecordEquity 오늘(KST) 행을 갱신한다. 시작 평가금액은 그날 처음 기록할 때 전 거래일 마지막 평가금액으로
+// 정하고 (기록이 없으면 지금 평가금액), 낙폭은 지금까지의 최고 평가금액 기준이다.
+func (m *manager) RecordEquity(ctx context.Context, equity float64) (*models.DailyPnL, error) {
+	if m.pnl == nil {
+		return nil, errors.New("daily pnl store not configured")
+	}
+	if equity <= 0 {
+		return nil, fmt.Errorf("invalid equity %.0f", equity)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	today := time.Now().In(models.KST)
+	day, err := m.pnl.GetDailyPnL(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+	if day == nil || day.StartEquity <= 0 {
+		start := equity
+		prev, err := m.pnl.LastDailyPnLBefore(ctx, today)
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil && prev.Equity > 0 {
+			start = prev.Equity
+		}
+		day = &models.DailyPnL{Date: today, StartEquity: start}
+	}
+
+	peak, err := m.pnl.PeakEquity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	peak = math.Max(math.Max(peak, day.StartEquity), equity)
+
+	day.Equity = equity
+	day.Profit = equity - day.StartEquity
+	day.Drawdown = (peak - equity) / peak
+	if err := m.pnl.SaveDailyPnL(ctx, day); err != nil {
+		return nil, err
+	}
+	return day, nil
+}
+
+func dailyReturn(d *models.DailyPnL) float64 {
+	if d.StartEquity <= 0 {
+		return 0
+	}
+	return d.Profit / d.StartEquity
+}
+
 func (m *manager) CheckPositionSize(ctx context.Context, equity float64, newPositionValue float64) error {
 	ratio := newPositionValue / equity
 	if ratio > m.cfg.MaxPositionRatio {
@@ -44,6 +153,21 @@ func (m *manager) CheckPositionSize(ctx context.Context, equity float64, newPosi
 	return nil
 }
 
+func (m *manager) CheckCashRatio(ctx context.Context, equity float64, cashAfter float64) error {
+	if equity <= 0 || m.cfg.MinCashRatio <= 0 {
+		return nil
+	}
+	ratio := cashAfter / equity
+	if ratio < m.cfg.MinCashRatio {
+		return fmt.Errorf("cash ratio %.2f below minimum %.2f", ratio, m.cfg.MinCashRatio)
+	}
+	return nil
+}
+
+func (m *manager) MaxPositionValue(equity float64) float64 {
+	return equity * m.cfg.MaxPositionRatio
+}
+
 func (m *manager) CheckThemeConcentration(ctx context.Context, positions []models.Position) error {
 	logger.Info.Println("[risk] CheckThemeConcentration stub")
 	// TODO: 섹터/테마 정보 기반 집중도 계산
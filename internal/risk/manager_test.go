@@ -0,0 +1,112 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// memPnL 메모리 daily_pnl (날짜 문자열 기준)
+type memPnL map[string]*models.DailyPnL
+
+func day(t time.Time) string { return t.In(models.KST).Format("2006-01-02") }
+
+func (m memPnL) GetDailyPnL(_ context.Context, date time.Time) (*models.DailyPnL, error) {
+	if p, ok := m[day(date)]; ok {
+		c := *p
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (m memPnL) LastDailyPnLBefore(_ context.Context, date time.Time) (*models.DailyPnL, error) {
+	var last *models.DailyPnL
+	for d, p := range m {
+		if d < day(date) && (last == nil || d > day(last.Date)) {
+			last = p
+		}
+	}
+	return last, nil
+}
+
+func (m memPnL) PeakEquity(context.Context) (float64, error) {
+	var peak float64
+	for _, p := range m {
+		peak = max(peak, p.Equity)
+	}
+	return peak, nil
+}
+
+func (m memPnL) SaveDailyPnL(_ context.Context, p *models.DailyPnL) error {
+	c := *p
+	m[day(p.Date)] = &c
+	return nil
+}
+
+func (m memPnL) ListDailyPnL(_ context.Context, limit int) ([]*models.DailyPnL, error) {
+	var out []*models.DailyPnL
+	for _, p := range m {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.After(out[j].Date) })
+	return out[:min(limit, len(out))], nil
+}
+
+func TestCheckMaxLoss(t *testing.T) {
+	today := time.Now().In(models.KST)
+	record := func(daysAgo int, start, equity float64) *models.DailyPnL {
+		return &models.DailyPnL{Date: today.AddDate(0, 0, -daysAgo), StartEquity: start, Equity: equity}
+	}
+
+	limits := Config{DailyLossLimit: 0.03, MaxRiskRatio: 0.1}
+	tests := []struct {
+		name    string
+		history []*models.DailyPnL
+		cfg     Config
+		equity  float64
+		want    error // nil 또는 ErrDailyLossLimit / ErrDrawdownLimit
+		start   float64
+	}{
+		{name: "first record starts the day", cfg: limits, equity: 1000, start: 1000},
+		{name: "small daily loss", history: []*models.DailyPnL{record(1, 1000, 1000)}, cfg: limits, equity: 980, start: 1000},
+		{name: "daily loss limit", history: []*models.DailyPnL{record(1, 1000, 1000)}, cfg: limits, equity: 960,
+			want: ErrDailyLossLimit, start: 1000},
+		{name: "intraday row keeps its start", history: []*models.DailyPnL{record(0, 1000, 990)}, cfg: limits, equity: 965,
+			want: ErrDailyLossLimit, start: 1000},
+		{name: "drawdown from an older peak", history: []*models.DailyPnL{record(5, 1150, 1200), record(1, 1080, 1080)},
+			cfg: limits, equity: 1070, want: ErrDrawdownLimit, start: 1080},
+		{name: "limits disabled", history: []*models.DailyPnL{record(5, 1200, 1200), record(1, 1000, 1000)},
+			cfg: Config{}, equity: 500, start: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pnl := memPnL{}
+			for _, h := range tt.history {
+				pnl.SaveDailyPnL(context.Background(), h)
+			}
+
+			err := NewManager(tt.cfg, pnl).CheckMaxLoss(context.Background(), tt.equity)
+			switch {
+			case tt.want == nil && err != nil:
+				t.Fatalf("CheckMaxLoss = %v, want nil", err)
+			case tt.want != nil && (!errors.Is(err, tt.want) || !errors.Is(err, ErrLossLimit)):
+				t.Fatalf("CheckMaxLoss = %v, want %v", err, tt.want)
+			}
+			got := pnl[day(today)]
+			if got == nil || got.Equity != tt.equity || got.StartEquity != tt.start {
+				t.Errorf("today's row = %+v, want equity %.0f start %.0f", got, tt.equity, tt.start)
+			}
+		})
+	}
+
+	t.Run("no store", func(t *testing.T) {
+		if err := NewManager(Config{DailyLossLimit: 0.03}, nil).CheckMaxLoss(context.Background(), 1); err != nil {
+			t.Errorf("CheckMaxLoss = %v, want nil", err)
+		}
+	})
+}
@@ -0,0 +1,314 @@
+package screener
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+// Candidate 파이프라인을 흘러가는 후보 종목. 각 단계가 필요한 데이터를 채워 넣는다.
+type Candidate struct {
+	Stock   *models.Stock
+	Quote   *models.Quote
+	Candles []models.Candle
+	Score   float64
+	// Values 단계별로 계산된 지표/점수 (예: "momentum(60)" -> 0.12)
+	Values map[string]float64
+}
+
+func newCandidate(s *models.Stock) *Candidate {
+	return &Candidate{Stock: s, Values: map[string]float64{}}
+}
+
+// UniverseProvider 파이프라인의 시작 종목군
+type UniverseProvider interface {
+	Universe(ctx context.Context) ([]*Candidate, error)
+}
+
+// Stage 후보 목록을 받아 걸러내거나 정렬하는 파이프라인 단계
+type Stage interface {
+	Name() string
+	Apply(ctx context.Context, in []*Candidate) ([]*Candidate, error)
+}
+
+// Pipeline Universe -> Stage... 순서로 실행되는 조합형 스크리너
+type Pipeline struct {
+	name     string
+	universe UniverseProvider
+	stages   []Stage
+	params   interface{}
+}
+
+func NewPipeline(name string, universe UniverseProvider, stages ...Stage) *Pipeline {
+	return &Pipeline{name: name, universe: universe, stages: stages}
+}
+
+// WithParams 스냅샷에 남길 설정값을 지정한다 (설정 파일 로더가 PipelineSpec 을 넣는다).
+func (p *Pipeline) WithParams(params interface{}) *Pipeline {
+	p.params = params
+	return p
+}
+
+func (p *Pipeline) Name() string { return p.name }
+
+func (p *Pipeline) Params() interface{} {
+	if p.params != nil {
+		return p.params
+	}
+	names := make([]string, len(p.stages))
+	for i, st := range p.stages {
+		names[i] = st.Name()
+	}
+	return map[string]interface{}{"stages": names}
+}
+
+func (p *Pipeline) Screen(ctx context.Context) ([]*models.Stock, error) {
+	cands, err := p.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*models.Stock, len(cands))
+	for i, c := range cands {
+		out[i] = c.Stock
+	}
+	return out, nil
+}
+
+// Run Screen 과 같지만 점수/지표가 채워진 후보를 그대로 반환한다.
+func (p *Pipeline) Run(ctx context.Context) ([]*Candidate, error) {
+	selected, _, err := p.run(ctx)
+	return selected, err
+}
+
+// ScreenDetailed 선정 종목과 함께 각 단계에서 탈락한 종목과 그 단계 이름을 반환한다.
+func (p *Pipeline) ScreenDetailed(ctx context.Context) ([]models.ScreenCandidate, error) {
+	selected, dropped, err := p.run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.ScreenCandidate, 0, len(selected)+len(dropped))
+	for i, c := range selected {
+		sc := c.snapshot()
+		sc.Selected = true
+		sc.Rank = i + 1
+		out = append(out, sc)
+	}
+	for _, d := range dropped {
+		sc := d.cand.snapshot()
+		sc.DroppedBy = d.stage
+		out = append(out, sc)
+	}
+	return out, nil
+}
+
+type droppedCandidate struct {
+	cand  *Candidate
+	stage string
+}
+
+func (p *Pipeline) run(ctx context.Context) ([]*Candidate, []droppedCandidate, error) {
+	cands, err := p.universe.Universe(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("screener %s: universe: %w", p.name, err)
+	}
+	logger.Info.Printf("[screener] %s: universe %d codes\n", p.name, len(cands))
+
+	var dropped []droppedCandidate
+
+	for _, st := range p.stages {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		default:
+		}
+
+		before := len(cands)
+		// Apply 가 in 슬라이스를 재사용할 수 있으므로 탈락 비교용 사본을 남긴다.
+		in := append([]*Candidate(nil), cands...)
+		out, err := st.Apply(ctx, cands)
+		if err != nil {
+			return nil, nil, fmt.Errorf("screener %s: %s: %w", p.name, st.Name(), err)
+		}
+		removed := removedCandidates(in, out)
+		for _, c := range removed {
+			dropped = append(dropped, droppedCandidate{cand: c, stage: st.Name()})
+		}
+		logger.Info.Printf("[screener] %s: %-16s removed %d, %d left %s\n",
+			p.name, st.Name(), before-len(out), len(out), codesForLog(removed))
+		cands = out
+	}
+
+	return cands, dropped, nil
+}
+
+// removedCandidates in 에는 있고 out 에는 없는 후보
+func removedCandidates(in, out []*Candidate) []*Candidate {
+	kept := make(map[string]bool, len(out))
+	for _, c := range out {
+		kept[c.Stock.Code] = true
+	}
+	var removed []*Candidate
+	for _, c := range in {
+		if !kept[c.Stock.Code] {
+			removed = append(removed, c)
+		}
+	}
+	return removed
+}
+
+// codesForLog 로그용 종목코드 목록 (많으면 앞부분만)
+func codesForLog(cands []*Candidate) string {
+	const maxShown = 10
+	codes := make([]string, 0, maxShown)
+	for i, c := range cands {
+		if i == maxShown {
+			return fmt.Sprintf("%v...", codes)
+		}
+		codes = append(codes, c.Stock.Code)
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%v", codes)
+}
+
+func (c *Candidate) snapshot() models.ScreenCandidate {
+	sc := models.ScreenCandidate{
+		Code:   c.Stock.Code,
+		Name:   c.Stock.Name,
+		Market: c.Stock.Market,
+		Score:  c.Score,
+		Values: map[string]float64{},
+	}
+	for k, v := range c.Values {
+		sc.Values[k] = v
+	}
+	if c.Quote != nil {
+		sc.Values["price"] = c.Quote.Price
+		sc.Values["trading_value"] = c.Quote.TradingValue
+		sc.Values["market_cap"] = c.Quote.MarketCap
+	}
+	return sc
+}
+
+// ===== Universe =====
+
+// StaticUniverse 고정 종목코드 목록
+type StaticUniverse struct {
+	Codes  []string
+	Market string
+}
+
+func (u StaticUniverse) Universe(ctx context.Context) ([]*Candidate, error) {
+	out := make([]*Candidate, len(u.Codes))
+	for i, code := range u.Codes {
+		out[i] = newCandidate(&models.Stock{Code: code, Market: u.Market})
+	}
+	return out, nil
+}
+
+// ScreenerUniverse 다른 스크리너(예: 규칙 DSL)의 결과를 유니버스로 사용
+type ScreenerUniverse struct {
+	Screener Screener
+}
+
+func (u ScreenerUniverse) Universe(ctx context.Context) ([]*Candidate, error) {
+	// 상세 결과를 줄 수 있으면 앞 스크리너의 지표 값도 후보에 넘긴다.
+	if d, ok := u.Screener.(DetailedScreener); ok {
+		cands, err := d.ScreenDetailed(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var out []*Candidate
+		for _, sc := range cands {
+			if !sc.Selected {
+				continue
+			}
+			c := newCandidate(&models.Stock{Code: sc.Code, Name: sc.Name, Market: sc.Market})
+			for k, v := range sc.Values {
+				c.Values[k] = v
+			}
+			out = append(out, c)
+		}
+		return out, nil
+	}
+
+	stocks, err := u.Screener.Screen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Candidate, len(stocks))
+	for i, s := range stocks {
+		out[i] = newCandidate(s)
+	}
+	return out, nil
+}
+
+// ===== 데이터 보강 단계 =====
+
+// QuoteSource 시세/종목상태 공급자 (kis.Client 가 구현)
+type QuoteSource interface {
+	GetQuoteDetail(ctx context.Context, code string) (*models.Quote, error)
+}
+
+type quoteStage struct {
+	src QuoteSource
+}
+
+// WithQuotes 후보마다 현재가/거래대금/시총/상태를 채운다. 조회 실패 종목은 제외된다.
+func WithQuotes(src QuoteSource) Stage { return quoteStage{src: src} }
+
+func (quoteStage) Name() string { return "quotes" }
+
+func (s quoteStage) Apply(ctx context.Context, in []*Candidate) ([]*Candidate, error) {
+	out := in[:0:0]
+	for _, c := range in {
+		if c.Quote == nil {
+			q, err := s.src.GetQuoteDetail(ctx, c.Stock.Code)
+			if err != nil {
+				logger.Error.Printf("[screener] quote for %s: %v\n", c.Stock.Code, err)
+				continue
+			}
+			c.Quote = q
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+type candleStage struct {
+	src      CandleSource
+	lookback int
+	now      func() time.Time
+}
+
+// WithCandles 후보마다 최근 lookbackDays(달력일) 일봉을 채운다. 조회 실패 종목은 제외된다.
+func WithCandles(src CandleSource, lookbackDays int) Stage {
+	if lookbackDays <= 0 {
+		lookbackDays = defaultLookbackDays
+	}
+	return candleStage{src: src, lookback: lookbackDays, now: time.Now}
+}
+
+func (candleStage) Name() string { return "candles" }
+
+func (s candleStage) Apply(ctx context.Context, in []*Candidate) ([]*Candidate, error) {
+	to := s.now()
+	from := to.AddDate(0, 0, -s.lookback)
+	out := in[:0:0]
+	for _, c := range in {
+		if c.Candles == nil {
+			candles, err := s.src.GetDailyCandles(ctx, c.Stock.Code, from, to)
+			if err != nil {
+				logger.Error.Printf("[screener] candles for %s: %v\n", c.Stock.Code, err)
+				continue
+			}
+			c.Candles = candles
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
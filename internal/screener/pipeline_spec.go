@@ -0,0 +1,148 @@
+package screener
+
+import (
+	"fmt"
+)
+
+// PipelineSpec 설정 파일의 조합형 스크리너 정의
+//
+//	{
+//	  "name": "kosdaq_composite",
+//	  "universe": {"screener": "kosdaq_momentum"},
+//	  "use_lists": true,
+//	  "filters": {"min_trading_value": 1e9, "min_market_cap": 2e11, "max_market_cap": 2e12, "exclude_status": true},
+//	  "rank": [{"scorer": "momentum", "period": 20, "weight": 1}, {"scorer": "volume_surge", "period": 20, "weight": 0.5}],
+//	  "top": 6
+//	}
+type PipelineSpec struct {
+	Name         string       `json:"name"`
+	Universe     UniverseSpec `json:"universe"`
+	UseLists     bool         `json:"use_lists"`
+	Filters      FilterSpec   `json:"filters"`
+	Rank         []ScorerSpec `json:"rank"`
+	Top          int          `json:"top"`
+	LookbackDays int          `json:"lookback_days"`
+}
+
+// UniverseSpec Screener(이미 정의된 스크리너 이름) 또는 Codes 중 하나
+type UniverseSpec struct {
+	Screener string   `json:"screener"`
+	Codes    []string `json:"codes"`
+	Market   string   `json:"market"`
+}
+
+// FilterSpec 0 값 항목은 적용하지 않는다.
+type FilterSpec struct {
+	MinTradingValue float64 `json:"min_trading_value"` // 원
+	MinPrice        float64 `json:"min_price"`
+	MaxPrice        float64 `json:"max_price"`
+	MinMarketCap    float64 `json:"min_market_cap"` // 원
+	MaxMarketCap    float64 `json:"max_market_cap"` // 원
+	ExcludeStatus   bool    `json:"exclude_status"`
+	ExcludeCaution  bool    `json:"exclude_caution"`
+}
+
+type ScorerSpec struct {
+	Scorer string  `json:"scorer"` // momentum | volume_surge | trading_value
+	Period int     `json:"period"`
+	Weight float64 `json:"weight"`
+}
+
+func (f FilterSpec) needsQuotes() bool {
+	return f.MinTradingValue > 0 || f.MinPrice > 0 || f.MaxPrice > 0 ||
+		f.MinMarketCap > 0 || f.MaxMarketCap > 0 || f.ExcludeStatus || f.ExcludeCaution
+}
+
+func (spec PipelineSpec) build(defined *Set, src Sources) (*Pipeline, error) {
+	if spec.Name == "" {
+		return nil, fmt.Errorf("screener: pipeline name is empty")
+	}
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("screener %s: "+format, append([]interface{}{spec.Name}, args...)...)
+	}
+
+	var universe UniverseProvider
+	switch {
+	case spec.Universe.Screener != "":
+		scr, err := defined.Get(spec.Universe.Screener)
+		if err != nil {
+			return nil, errorf("%v", err)
+		}
+		universe = ScreenerUniverse{Screener: scr}
+	case len(spec.Universe.Codes) > 0:
+		universe = StaticUniverse{Codes: spec.Universe.Codes, Market: spec.Universe.Market}
+	default:
+		return nil, errorf("universe needs screener or codes")
+	}
+
+	var scorers []Scorer
+	needCandles, needQuotes := false, spec.Filters.needsQuotes()
+	for _, r := range spec.Rank {
+		w := r.Weight
+		if w == 0 {
+			w = 1
+		}
+		switch r.Scorer {
+		case "momentum":
+			scorers = append(scorers, MomentumScorer(orDefault(r.Period, 20), w))
+			needCandles = true
+		case "volume_surge":
+			scorers = append(scorers, VolumeSurgeScorer(orDefault(r.Period, 20), w))
+			needCandles = true
+		case "trading_value":
+			scorers = append(scorers, TradingValueScorer(w))
+			needQuotes = true
+		default:
+			return nil, errorf("unknown scorer %q", r.Scorer)
+		}
+	}
+
+	// 싼 단계(리스트) -> 시세 -> 필터 -> 일봉 -> 점수 -> 상위 N 순서로 배치해 API 호출을 줄인다.
+	var stages []Stage
+	if spec.UseLists {
+		if src.Lists == nil {
+			return nil, errorf("use_lists requires a list store")
+		}
+		stages = append(stages, ExclusionLists(src.Lists))
+	}
+	if needQuotes {
+		if src.Quotes == nil {
+			return nil, errorf("filters require a quote source")
+		}
+		stages = append(stages, WithQuotes(src.Quotes))
+	}
+	f := spec.Filters
+	if f.ExcludeStatus || f.ExcludeCaution {
+		stages = append(stages, StatusFilter(f.ExcludeCaution))
+	}
+	if f.MinTradingValue > 0 {
+		stages = append(stages, LiquidityFilter(f.MinTradingValue))
+	}
+	if f.MinPrice > 0 || f.MaxPrice > 0 {
+		stages = append(stages, PriceBandFilter(f.MinPrice, f.MaxPrice))
+	}
+	if f.MinMarketCap > 0 || f.MaxMarketCap > 0 {
+		stages = append(stages, MarketCapFilter(f.MinMarketCap, f.MaxMarketCap))
+	}
+	if needCandles {
+		if src.Candles == nil {
+			return nil, errorf("scorers require a candle source")
+		}
+		stages = append(stages, WithCandles(src.Candles, spec.LookbackDays))
+	}
+	if len(scorers) > 0 {
+		stages = append(stages, Rank(scorers...))
+	}
+	if spec.Top > 0 {
+		stages = append(stages, TopN(spec.Top))
+	}
+
+	return NewPipeline(spec.Name, universe, stages...).WithParams(spec), nil
+}
+
+func orDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,90 @@
+package screener
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+// DetailedScreener 탈락 종목과 지표 값까지 돌려줄 수 있는 스크리너 (RuleScreener, Pipeline)
+type DetailedScreener interface {
+	Screener
+	Name() string
+	Params() interface{}
+	// ScreenDetailed 평가한 전 종목. 선정 종목은 Selected=true, Rank 순.
+	ScreenDetailed(ctx context.Context) ([]models.ScreenCandidate, error)
+}
+
+// RunStore 스냅샷 저장소 (storage.ScreenRunRepository 가 구현)
+type RunStore interface {
+	InsertScreenRun(ctx context.Context, run *models.ScreenRun) error
+}
+
+// Recorder Screen 호출마다 입력 설정과 후보별 지표/점수를 저장하는 래퍼.
+// 저장 실패는 로그만 남기고 스크리닝 결과는 그대로 돌려준다.
+type Recorder struct {
+	inner Screener
+	store RunStore
+	name  string
+}
+
+func NewRecorder(inner Screener, name string, store RunStore) *Recorder {
+	return &Recorder{inner: inner, store: store, name: name}
+}
+
+func (r *Recorder) Screen(ctx context.Context) ([]*models.Stock, error) {
+	started := time.Now()
+
+	var cands []models.ScreenCandidate
+	var params interface{}
+	if d, ok := r.inner.(DetailedScreener); ok {
+		var err error
+		if cands, err = d.ScreenDetailed(ctx); err != nil {
+			return nil, err
+		}
+		params = d.Params()
+	} else {
+		stocks, err := r.inner.Screen(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i, s := range stocks {
+			cands = append(cands, models.ScreenCandidate{
+				Code: s.Code, Name: s.Name, Market: s.Market, Rank: i + 1, Selected: true,
+			})
+		}
+	}
+
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		logger.Error.Printf("[screener] %s: marshal params: %v\n", r.name, err)
+		rawParams = []byte("null")
+	}
+	run := &models.ScreenRun{
+		Name:       r.name,
+		Params:     string(rawParams),
+		StartedAt:  started,
+		FinishedAt: time.Now(),
+		Candidates: cands,
+	}
+	if err := r.store.InsertScreenRun(ctx, run); err != nil {
+		logger.Error.Printf("[screener] %s: failed to save snapshot: %v\n", r.name, err)
+	} else {
+		logger.Info.Printf("[screener] %s: snapshot #%d saved (%d candidates)\n", r.name, run.ID, len(cands))
+	}
+
+	return selectedStocks(cands), nil
+}
+
+func selectedStocks(cands []models.ScreenCandidate) []*models.Stock {
+	var out []*models.Stock
+	for _, c := range cands {
+		if c.Selected {
+			out = append(out, &models.Stock{Code: c.Code, Name: c.Name, Market: c.Market})
+		}
+	}
+	return out
+}
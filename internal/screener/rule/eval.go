@@ -0,0 +1,342 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"stock-investing/internal/indicator"
+	"stock-investing/internal/models"
+)
+
+// ErrInsufficientData 규칙이 참조하는 지표를 계산할 봉이 부족할 때 반환
+var ErrInsufficientData = indicator.ErrInsufficientData
+
+// Rule 파싱된 스크리닝 규칙
+type Rule struct {
+	src  string
+	root node
+}
+
+// Result 한 종목에 대한 평가 결과
+type Result struct {
+	Matched bool
+	// Values 규칙에 등장한 지표/필드 값 (예: "sma(20)" -> 10250)
+	Values map[string]float64
+}
+
+// Parse 규칙 문자열을 파싱한다. 알 수 없는 필드/함수, 인자 개수 오류는 여기서 걸러진다.
+func Parse(src string) (*Rule, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.errorf("unexpected token %q", p.peek().text)
+	}
+	return &Rule{src: src, root: root}, nil
+}
+
+// MustParse 파싱 실패 시 panic (상수 규칙 정의용)
+func MustParse(src string) *Rule {
+	r, err := Parse(src)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r *Rule) String() string { return r.src }
+
+// Eval 날짜 오름차순 봉 데이터(마지막이 최신)에 대해 규칙을 평가한다.
+func (r *Rule) Eval(candles []models.Candle) (*Result, error) {
+	ec := &evalCtx{candles: candles, values: map[string]float64{}}
+	v, err := ec.eval(r.root)
+	if err != nil {
+		return nil, err
+	}
+	if !v.isBool {
+		return nil, fmt.Errorf("rule: expression %q is not boolean", r.src)
+	}
+	return &Result{Matched: v.b, Values: ec.values}, nil
+}
+
+type value struct {
+	num    float64
+	b      bool
+	isBool bool
+}
+
+func numVal(f float64) value { return value{num: f} }
+func boolVal(b bool) value   { return value{b: b, isBool: true} }
+
+type evalCtx struct {
+	candles []models.Candle
+	// prev() 안에서 평가 중이면 true. 과거 시점 값은 Values 에 기록하지 않는다.
+	shifted bool
+	values  map[string]float64
+}
+
+func (ec *evalCtx) record(key string, v float64) {
+	if !ec.shifted {
+		ec.values[key] = v
+	}
+}
+
+var fields = map[string]func(c []models.Candle) (float64, error){
+	"open":   func(c []models.Candle) (float64, error) { return c[len(c)-1].Open, nil },
+	"high":   func(c []models.Candle) (float64, error) { return c[len(c)-1].High, nil },
+	"low":    func(c []models.Candle) (float64, error) { return c[len(c)-1].Low, nil },
+	"close":  func(c []models.Candle) (float64, error) { return c[len(c)-1].Close, nil },
+	"price":  func(c []models.Candle) (float64, error) { return c[len(c)-1].Close, nil },
+	"volume": func(c []models.Candle) (float64, error) { return float64(c[len(c)-1].Volume), nil },
+	"change": func(c []models.Candle) (float64, error) { return indicator.ROC(c, 1) },
+}
+
+type funcSpec struct {
+	minArgs, maxArgs int
+	fn               func(c []models.Candle, args []float64) (float64, error)
+}
+
+func period(args []float64, i int) (int, error) {
+	n := args[i]
+	if n < 1 || n != math.Trunc(n) {
+		return 0, fmt.Errorf("period must be a positive integer, got %g", n)
+	}
+	return int(n), nil
+}
+
+func withPeriod(f func([]models.Candle, int) (float64, error)) funcSpec {
+	return funcSpec{minArgs: 1, maxArgs: 1, fn: func(c []models.Candle, args []float64) (float64, error) {
+		n, err := period(args, 0)
+		if err != nil {
+			return 0, err
+		}
+		return f(c, n)
+	}}
+}
+
+func bollinger(upper bool) funcSpec {
+	return funcSpec{minArgs: 1, maxArgs: 2, fn: func(c []models.Candle, args []float64) (float64, error) {
+		n, err := period(args, 0)
+		if err != nil {
+			return 0, err
+		}
+		k := 2.0
+		if len(args) > 1 {
+			k = args[1]
+		}
+		_, up, lo, err := indicator.Bollinger(c, n, k)
+		if upper {
+			return up, err
+		}
+		return lo, err
+	}}
+}
+
+var funcs = map[string]funcSpec{
+	"sma":        withPeriod(indicator.SMA),
+	"ema":        withPeriod(indicator.EMA),
+	"rsi":        withPeriod(indicator.RSI),
+	"avg_volume": withPeriod(indicator.AvgVolume),
+	"atr":        withPeriod(indicator.ATR),
+	"highest":    withPeriod(indicator.Highest),
+	"lowest":     withPeriod(indicator.Lowest),
+	"stddev":     withPeriod(indicator.StdDev),
+	"roc":        withPeriod(indicator.ROC),
+	"bb_upper":   bollinger(true),
+	"bb_lower":   bollinger(false),
+	"abs": {minArgs: 1, maxArgs: 1, fn: func(_ []models.Candle, args []float64) (float64, error) {
+		return math.Abs(args[0]), nil
+	}},
+}
+
+// prev(expr[, k]) 는 k봉 전(기본 1) 시점으로 expr 을 평가한다.
+// 예: "close > sma(20) && prev(close) <= prev(sma(20))" = 20일선 상향 돌파
+const prevFunc = "prev"
+
+func checkCall(c callNode) error {
+	if c.name == prevFunc {
+		if len(c.args) < 1 || len(c.args) > 2 {
+			return fmt.Errorf("prev expects 1 or 2 arguments, got %d", len(c.args))
+		}
+		return nil
+	}
+	spec, ok := funcs[c.name]
+	if !ok {
+		return fmt.Errorf("unknown function %q", c.name)
+	}
+	if len(c.args) < spec.minArgs || len(c.args) > spec.maxArgs {
+		return fmt.Errorf("%s expects %d..%d arguments, got %d", c.name, spec.minArgs, spec.maxArgs, len(c.args))
+	}
+	return nil
+}
+
+func (ec *evalCtx) evalNum(n node) (float64, error) {
+	v, err := ec.eval(n)
+	if err != nil {
+		return 0, err
+	}
+	if v.isBool {
+		return 0, fmt.Errorf("rule: %s is boolean, number expected", n)
+	}
+	return v.num, nil
+}
+
+func (ec *evalCtx) evalBool(n node) (bool, error) {
+	v, err := ec.eval(n)
+	if err != nil {
+		return false, err
+	}
+	if !v.isBool {
+		return false, fmt.Errorf("rule: %s is a number, boolean expected", n)
+	}
+	return v.b, nil
+}
+
+func (ec *evalCtx) eval(n node) (value, error) {
+	if len(ec.candles) == 0 {
+		return value{}, ErrInsufficientData
+	}
+	switch n := n.(type) {
+	case numberNode:
+		return numVal(n.v), nil
+	case boolNode:
+		return boolVal(n.v), nil
+	case identNode:
+		v, err := fields[n.name](ec.candles)
+		if err != nil {
+			return value{}, err
+		}
+		ec.record(n.name, v)
+		return numVal(v), nil
+	case callNode:
+		return ec.evalCall(n)
+	case unaryNode:
+		if n.op == "!" {
+			b, err := ec.evalBool(n.x)
+			return boolVal(!b), err
+		}
+		f, err := ec.evalNum(n.x)
+		return numVal(-f), err
+	case betweenNode:
+		x, err := ec.evalNum(n.x)
+		if err != nil {
+			return value{}, err
+		}
+		lo, err := ec.evalNum(n.lo)
+		if err != nil {
+			return value{}, err
+		}
+		hi, err := ec.evalNum(n.hi)
+		if err != nil {
+			return value{}, err
+		}
+		return boolVal(x >= lo && x <= hi), nil
+	case binaryNode:
+		return ec.evalBinary(n)
+	}
+	return value{}, fmt.Errorf("rule: unsupported node %T", n)
+}
+
+func (ec *evalCtx) evalBinary(n binaryNode) (value, error) {
+	switch n.op {
+	case "&&", "||":
+		l, err := ec.evalBool(n.l)
+		if err != nil {
+			return value{}, err
+		}
+		// 단락 평가: 앞 조건으로 결론이 나면 뒤 지표는 계산하지 않는다.
+		if (n.op == "&&" && !l) || (n.op == "||" && l) {
+			return boolVal(l), nil
+		}
+		r, err := ec.evalBool(n.r)
+		return boolVal(r), err
+	}
+
+	l, err := ec.evalNum(n.l)
+	if err != nil {
+		return value{}, err
+	}
+	r, err := ec.evalNum(n.r)
+	if err != nil {
+		return value{}, err
+	}
+	switch n.op {
+	case "+":
+		return numVal(l + r), nil
+	case "-":
+		return numVal(l - r), nil
+	case "*":
+		return numVal(l * r), nil
+	case "/":
+		if r == 0 {
+			return value{}, errors.New("rule: division by zero")
+		}
+		return numVal(l / r), nil
+	case ">":
+		return boolVal(l > r), nil
+	case ">=":
+		return boolVal(l >= r), nil
+	case "<":
+		return boolVal(l < r), nil
+	case "<=":
+		return boolVal(l <= r), nil
+	case "==":
+		return boolVal(l == r), nil
+	case "!=":
+		return boolVal(l != r), nil
+	}
+	return value{}, fmt.Errorf("rule: unknown operator %q", n.op)
+}
+
+func (ec *evalCtx) evalCall(n callNode) (value, error) {
+	if n.name == prevFunc {
+		k := 1
+		if len(n.args) == 2 {
+			kf, err := ec.evalNum(n.args[1])
+			if err != nil {
+				return value{}, err
+			}
+			if k, err = period([]float64{kf}, 0); err != nil {
+				return value{}, fmt.Errorf("rule: prev: %v", err)
+			}
+		}
+		if len(ec.candles) <= k {
+			return value{}, ErrInsufficientData
+		}
+		sub := &evalCtx{candles: ec.candles[:len(ec.candles)-k], shifted: true, values: ec.values}
+		v, err := sub.eval(n.args[0])
+		if err != nil {
+			return value{}, err
+		}
+		if !v.isBool {
+			ec.record(n.String(), v.num)
+		}
+		return v, nil
+	}
+
+	spec := funcs[n.name]
+	args := make([]float64, len(n.args))
+	for i, a := range n.args {
+		f, err := ec.evalNum(a)
+		if err != nil {
+			return value{}, err
+		}
+		args[i] = f
+	}
+	v, err := spec.fn(ec.candles, args)
+	if err != nil {
+		if errors.Is(err, ErrInsufficientData) {
+			return value{}, err
+		}
+		return value{}, fmt.Errorf("rule: %s: %w", n, err)
+	}
+	ec.record(n.String(), v)
+	return numVal(v), nil
+}
@@ -0,0 +1,98 @@
+package rule
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// 두 글자 연산자를 먼저 매칭해야 ">=" 가 ">" "=" 로 쪼개지지 않는다.
+var twoCharOps = []string{">=", "<=", "==", "!=", "&&", "||"}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	rs := []rune(src)
+	i := 0
+	for i < len(rs) {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			start := i
+			for i < len(rs) && (unicode.IsDigit(rs[i]) || rs[i] == '.' || rs[i] == '_') {
+				i++
+			}
+			text := string(rs[start:i])
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("rule: invalid number %q at %d", text, start)
+			}
+			// 1.5% 처럼 퍼센트 표기를 허용한다.
+			if i < len(rs) && rs[i] == '%' {
+				v /= 100
+				i++
+			}
+			toks = append(toks, token{kind: tokNumber, text: text, num: v, pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '_') {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: string(rs[start:i]), pos: start})
+		case r == '(':
+			toks = append(toks, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			toks = append(toks, token{kind: tokComma, text: ",", pos: i})
+			i++
+		default:
+			matched := false
+			if i+1 < len(rs) {
+				two := string(rs[i : i+2])
+				for _, op := range twoCharOps {
+					if two == op {
+						toks = append(toks, token{kind: tokOp, text: op, pos: i})
+						i += 2
+						matched = true
+						break
+					}
+				}
+			}
+			if matched {
+				continue
+			}
+			switch r {
+			case '>', '<', '+', '-', '*', '/', '!':
+				toks = append(toks, token{kind: tokOp, text: string(r), pos: i})
+				i++
+			default:
+				return nil, fmt.Errorf("rule: unexpected character %q at %d", r, i)
+			}
+		}
+	}
+	toks = append(toks, token{kind: tokEOF, pos: len(rs)})
+	return toks, nil
+}
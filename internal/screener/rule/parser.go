@@ -0,0 +1,286 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 문법 (우선순위 낮은 순)
+//
+//	or      := and ( "||" and )*
+//	and     := not ( "&&" not )*
+//	not     := "!" not | cmp
+//	cmp     := sum [ ( ">" | ">=" | "<" | "<=" | "==" | "!=" ) sum | "between" sum "and" sum ]
+//	sum     := product ( ( "+" | "-" ) product )*
+//	product := unary ( ( "*" | "/" ) unary )*
+//	unary   := "-" unary | primary
+//	primary := number | "true" | "false" | ident | ident "(" [ or ( "," or )* ] ")" | "(" or ")"
+
+type node interface {
+	String() string
+}
+
+type numberNode struct{ v float64 }
+
+type boolNode struct{ v bool }
+
+type identNode struct{ name string }
+
+type callNode struct {
+	name string
+	args []node
+}
+
+type unaryNode struct {
+	op string
+	x  node
+}
+
+type binaryNode struct {
+	op   string
+	l, r node
+}
+
+type betweenNode struct {
+	x, lo, hi node
+}
+
+func (n numberNode) String() string { return fmt.Sprintf("%g", n.v) }
+func (n boolNode) String() string   { return fmt.Sprintf("%v", n.v) }
+func (n identNode) String() string  { return n.name }
+func (n callNode) String() string {
+	parts := make([]string, len(n.args))
+	for i, a := range n.args {
+		parts[i] = a.String()
+	}
+	return n.name + "(" + strings.Join(parts, ",") + ")"
+}
+func (n unaryNode) String() string { return n.op + n.x.String() }
+func (n binaryNode) String() string {
+	return "(" + n.l.String() + " " + n.op + " " + n.r.String() + ")"
+}
+func (n betweenNode) String() string {
+	return "(" + n.x.String() + " between " + n.lo.String() + " and " + n.hi.String() + ")"
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("rule: "+format+" at %d", append(args, p.peek().pos)...)
+}
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: "||", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: "&&", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isOp("!") {
+		p.next()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "!", x: x}, nil
+	}
+	return p.parseCmp()
+}
+
+func (p *parser) parseCmp() (node, error) {
+	l, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp(">", ">=", "<", "<=", "==", "!=") {
+		op := p.next().text
+		r, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: op, l: l, r: r}, nil
+	}
+	if p.isKeyword("between") {
+		p.next()
+		lo, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isKeyword("and") {
+			return nil, p.errorf("expected 'and' in between")
+		}
+		p.next()
+		hi, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return betweenNode{x: l, lo: lo, hi: hi}, nil
+	}
+	return l, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	l, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+", "-") {
+		op := p.next().text
+		r, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseProduct() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*", "/") {
+		op := p.next().text
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOp("-") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "-", x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokNumber:
+		p.next()
+		return numberNode{v: t.num}, nil
+	case tokLParen:
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorf("expected ')'")
+		}
+		p.next()
+		return x, nil
+	case tokIdent:
+		p.next()
+		name := strings.ToLower(t.text)
+		switch name {
+		case "true":
+			return boolNode{v: true}, nil
+		case "false":
+			return boolNode{v: false}, nil
+		case "between", "and":
+			return nil, fmt.Errorf("rule: unexpected keyword %q at %d", t.text, t.pos)
+		}
+		if p.peek().kind != tokLParen {
+			if _, ok := fields[name]; !ok {
+				return nil, fmt.Errorf("rule: unknown field %q at %d", t.text, t.pos)
+			}
+			return identNode{name: name}, nil
+		}
+		p.next()
+		var args []node
+		if p.peek().kind != tokRParen {
+			for {
+				a, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, a)
+				if p.peek().kind != tokComma {
+					break
+				}
+				p.next()
+			}
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorf("expected ')' after arguments of %s", name)
+		}
+		p.next()
+		call := callNode{name: name, args: args}
+		if err := checkCall(call); err != nil {
+			return nil, fmt.Errorf("rule: %v at %d", err, t.pos)
+		}
+		return call, nil
+	}
+	if t.kind == tokEOF {
+		return nil, p.errorf("unexpected end of expression")
+	}
+	return nil, p.errorf("unexpected token %q", t.text)
+}
@@ -0,0 +1,115 @@
+package rule
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src  string
+		tree string // 파싱 결과 (괄호로 우선순위 표시)
+		err  string // 비어 있지 않으면 에러 메시지에 포함될 문자열
+	}{
+		{src: "close > sma(20)", tree: "(close > sma(20))"},
+		{src: "close > 1 + 2 * 3", tree: "(close > (1 + (2 * 3)))"},
+		{src: "(1 + 2) * 3 > close", tree: "(((1 + 2) * 3) > close)"},
+		{src: "close > 1 || volume > 2 && open > 3", tree: "((close > 1) || ((volume > 2) && (open > 3)))"},
+		{src: "!close > 1 && volume > 0", tree: "(!(close > 1) && (volume > 0))"},
+		{src: "rsi(14) between 50 and 70", tree: "(rsi(14) between 50 and 70)"},
+		{src: "RSI(14) BETWEEN 50 AND 70", tree: "(rsi(14) between 50 and 70)"},
+		{src: "close > prev(close, 2)", tree: "(close > prev(close,2))"},
+		{src: "volume > 1.5 * avg_volume(20)", tree: "(volume > (1.5 * avg_volume(20)))"},
+
+		{src: "close >", err: "at"},
+		{src: "close > sma(20", err: ""},
+		{src: "rsi(14) between 50 70", err: "expected 'and' in between"},
+		{src: "close > 1 2", err: "unexpected token"},
+		{src: "close # 1", err: "unexpected character"},
+		{src: "foo > 1", err: `unknown field "foo"`},
+		{src: "close > foo(3)", err: `unknown function "foo"`},
+		{src: "close > sma(1, 2)", err: "sma expects"},
+		{src: "close > prev()", err: "prev expects 1 or 2 arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			r, err := Parse(tt.src)
+			if tt.tree == "" {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %s, want error", tt.src, r.root)
+				}
+				if !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Parse(%q) error = %v, want %q", tt.src, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.src, err)
+			}
+			if got := r.root.String(); got != tt.tree {
+				t.Errorf("Parse(%q) = %s, want %s", tt.src, got, tt.tree)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	// 종가 1..30, 거래량 100 (마지막 봉만 300)
+	var candles []models.Candle
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, models.KST)
+	for i := 1; i <= 30; i++ {
+		c := float64(i)
+		candles = append(candles, models.Candle{Date: start.AddDate(0, 0, i), Open: c, High: c + 0.5, Low: c - 0.5, Close: c, Volume: 100})
+	}
+	candles[len(candles)-1].Volume = 300
+
+	tests := []struct {
+		src     string
+		matched bool
+		values  map[string]float64
+		err     error
+	}{
+		{src: "close > sma(5)", matched: true, values: map[string]float64{"close": 30, "sma(5)": 28}},
+		{src: "close between 29 and 31", matched: true},
+		{src: "volume > 1.5 * avg_volume(3)", matched: true},
+		{src: "prev(close) == 29 && prev(close, 2) == 28", matched: true,
+			values: map[string]float64{"prev(close)": 29, "prev(close,2)": 28}},
+		{src: "close < 10 && sma(5) > 0", matched: false, values: map[string]float64{"close": 30}},
+		{src: "!(close < 10)", matched: true},
+		{src: "close > sma(40)", err: ErrInsufficientData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			res, err := MustParse(tt.src).Eval(candles)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Eval = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Matched != tt.matched {
+				t.Errorf("Matched = %v, want %v", res.Matched, tt.matched)
+			}
+			if tt.values == nil {
+				return
+			}
+			if len(res.Values) != len(tt.values) {
+				t.Errorf("Values = %v, want %v", res.Values, tt.values)
+			}
+			for k, v := range tt.values {
+				if res.Values[k] != v {
+					t.Errorf("Values[%s] = %v, want %v", k, res.Values[k], v)
+				}
+			}
+		})
+	}
+}
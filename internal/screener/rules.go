@@ -0,0 +1,227 @@
+package screener
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/screener/rule"
+	"stock-investing/pkg/logger"
+)
+
+// CandleSource 스크리너가 지표 계산에 사용하는 일봉 공급자 (kis.Client 가 구현)
+type CandleSource interface {
+	GetDailyCandles(ctx context.Context, code string, from, to time.Time) ([]models.Candle, error)
+}
+
+// RuleSpec 설정 파일의 스크리너 정의 한 건
+//
+//	{
+//	  "name": "kosdaq_momentum",
+//	  "market": "KOSDAQ",
+//	  "universe": ["247540", "086520"],
+//	  "rule": "close > sma(20) && volume > 1.5 * avg_volume(20) && rsi(14) between 50 and 70",
+//	  "lookback_days": 120,
+//	  "limit": 10
+//	}
+type RuleSpec struct {
+	Name         string   `json:"name"`
+	Market       string   `json:"market"`
+	Universe     []string `json:"universe"`
+	Rule         string   `json:"rule"`
+	LookbackDays int      `json:"lookback_days"`
+	Limit        int      `json:"limit"`
+}
+
+type ruleFile struct {
+	Screeners []RuleSpec     `json:"screeners"`
+	Pipelines []PipelineSpec `json:"pipelines"`
+}
+
+// 기본 조회 기간(달력일). 20일 지표 + RSI(14) 수렴에 충분한 여유를 둔다.
+const defaultLookbackDays = 120
+
+// RuleScreener 설정 파일의 규칙식으로 유니버스를 필터링하는 스크리너
+type RuleScreener struct {
+	spec    RuleSpec
+	rule    *rule.Rule
+	candles CandleSource
+	now     func() time.Time
+}
+
+func NewRuleScreener(spec RuleSpec, candles CandleSource) (*RuleScreener, error) {
+	if spec.Name == "" {
+		return nil, errors.New("screener: rule screener name is empty")
+	}
+	r, err := rule.Parse(spec.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("screener %s: %w", spec.Name, err)
+	}
+	if spec.LookbackDays <= 0 {
+		spec.LookbackDays = defaultLookbackDays
+	}
+	return &RuleScreener{spec: spec, rule: r, candles: candles, now: time.Now}, nil
+}
+
+func (s *RuleScreener) Name() string { return s.spec.Name }
+
+func (s *RuleScreener) Params() interface{} { return s.spec }
+
+func (s *RuleScreener) Screen(ctx context.Context) ([]*models.Stock, error) {
+	cands, err := s.ScreenDetailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return selectedStocks(cands), nil
+}
+
+// ScreenDetailed 유니버스 전 종목의 평가 결과(지표 값, 탈락 사유)를 반환한다.
+func (s *RuleScreener) ScreenDetailed(ctx context.Context) ([]models.ScreenCandidate, error) {
+	logger.Info.Printf("[screener] %s: evaluating %q over %d codes\n", s.spec.Name, s.rule, len(s.spec.Universe))
+
+	to := s.now()
+	from := to.AddDate(0, 0, -s.spec.LookbackDays)
+
+	var out []models.ScreenCandidate
+	selected := 0
+	for _, code := range s.spec.Universe {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		cand := models.ScreenCandidate{Code: code, Market: s.spec.Market}
+		if s.spec.Limit > 0 && selected >= s.spec.Limit {
+			cand.DroppedBy = "limit"
+			out = append(out, cand)
+			continue
+		}
+
+		candles, err := s.candles.GetDailyCandles(ctx, code, from, to)
+		if err != nil {
+			logger.Error.Printf("[screener] %s: candles for %s: %v\n", s.spec.Name, code, err)
+			cand.DroppedBy = "candles_error"
+			out = append(out, cand)
+			continue
+		}
+		res, err := s.rule.Eval(candles)
+		if err != nil {
+			if errors.Is(err, rule.ErrInsufficientData) {
+				logger.Info.Printf("[screener] %s: %s skipped, only %d candles\n", s.spec.Name, code, len(candles))
+				cand.DroppedBy = "insufficient_data"
+				out = append(out, cand)
+				continue
+			}
+			return nil, fmt.Errorf("screener %s: %s: %w", s.spec.Name, code, err)
+		}
+		cand.Values = res.Values
+		if !res.Matched {
+			cand.DroppedBy = "rule"
+			out = append(out, cand)
+			continue
+		}
+		selected++
+		cand.Selected = true
+		cand.Rank = selected
+		logger.Info.Printf("[screener] %s: %s matched %s\n", s.spec.Name, code, formatValues(res.Values))
+		out = append(out, cand)
+	}
+
+	logger.Info.Printf("[screener] %s: %d/%d codes matched\n", s.spec.Name, selected, len(s.spec.Universe))
+	return out, nil
+}
+
+func formatValues(values map[string]float64) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%.2f", k, values[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+// Set 이름으로 선택 가능한 스크리너 모음
+type Set struct {
+	byName map[string]Screener
+	names  []string
+}
+
+func NewSet() *Set {
+	return &Set{byName: map[string]Screener{}}
+}
+
+func (s *Set) Add(name string, scr Screener) error {
+	if _, dup := s.byName[name]; dup {
+		return fmt.Errorf("screener: duplicate name %q", name)
+	}
+	s.byName[name] = scr
+	s.names = append(s.names, name)
+	return nil
+}
+
+func (s *Set) Get(name string) (Screener, error) {
+	scr, ok := s.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("screener: unknown screener %q (available: %v)", name, s.names)
+	}
+	return scr, nil
+}
+
+// Names 등록 순서대로의 스크리너 이름
+func (s *Set) Names() []string {
+	return append([]string(nil), s.names...)
+}
+
+// Sources 설정 파일로 만드는 스크리너들이 사용하는 데이터 공급자.
+// Lists 가 nil 이면 use_lists 가 켜진 파이프라인은 로드 오류가 된다.
+type Sources struct {
+	Candles CandleSource
+	Quotes  QuoteSource
+	Lists   ExclusionSource
+}
+
+// LoadRuleFile JSON 규칙 파일을 읽어 RuleScreener/Pipeline 들을 Set 에 담아 반환한다.
+// 규칙식 파싱 오류는 실행 전에 여기서 드러난다.
+func LoadRuleFile(path string, src Sources) (*Set, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f ruleFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("screener: parse %s: %w", path, err)
+	}
+
+	set := NewSet()
+	for _, spec := range f.Screeners {
+		scr, err := NewRuleScreener(spec, src.Candles)
+		if err != nil {
+			return nil, err
+		}
+		if err := set.Add(spec.Name, scr); err != nil {
+			return nil, err
+		}
+	}
+	// 파이프라인은 위에서 정의한 규칙 스크리너를 유니버스로 참조할 수 있다.
+	for _, spec := range f.Pipelines {
+		p, err := spec.build(set, src)
+		if err != nil {
+			return nil, err
+		}
+		if err := set.Add(spec.Name, p); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
@@ -0,0 +1,278 @@
+package screener
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"stock-investing/internal/indicator"
+	"stock-investing/internal/storage"
+	"stock-investing/pkg/logger"
+)
+
+// ===== 불리언 필터 =====
+
+type filterStage struct {
+	name string
+	keep func(c *Candidate) bool
+}
+
+func (f filterStage) Name() string { return f.name }
+
+func (f filterStage) Apply(ctx context.Context, in []*Candidate) ([]*Candidate, error) {
+	out := in[:0:0]
+	for _, c := range in {
+		if f.keep(c) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// Filter 임의의 조건으로 후보를 거르는 단계
+func Filter(name string, keep func(c *Candidate) bool) Stage {
+	return filterStage{name: name, keep: keep}
+}
+
+// 아래 시세 기반 필터들은 WithQuotes 단계 뒤에 두어야 한다. Quote 가 없으면 제외한다.
+
+// LiquidityFilter 당일 누적 거래대금이 minTradingValue(원) 이상인 종목만 남긴다.
+func LiquidityFilter(minTradingValue float64) Stage {
+	return Filter("liquidity", func(c *Candidate) bool {
+		return c.Quote != nil && c.Quote.TradingValue >= minTradingValue
+	})
+}
+
+// PriceBandFilter 현재가가 [min, max] 범위인 종목만 남긴다. max<=0 이면 상한 없음.
+func PriceBandFilter(min, max float64) Stage {
+	return Filter("price_band", func(c *Candidate) bool {
+		if c.Quote == nil || c.Quote.Price < min {
+			return false
+		}
+		return max <= 0 || c.Quote.Price <= max
+	})
+}
+
+// MarketCapFilter 시가총액이 [min, max] 원 범위인 종목만 남긴다. max<=0 이면 상한 없음.
+func MarketCapFilter(min, max float64) Stage {
+	return Filter("market_cap", func(c *Candidate) bool {
+		if c.Quote == nil || c.Quote.MarketCap < min {
+			return false
+		}
+		return max <= 0 || c.Quote.MarketCap <= max
+	})
+}
+
+// StatusFilter 거래정지/관리/투자경고·위험/정리매매 종목을 제외한다. excludeCaution 이면 투자주의도 제외.
+func StatusFilter(excludeCaution bool) Stage {
+	return Filter("status", func(c *Candidate) bool {
+		q := c.Quote
+		if q == nil || q.Halted || q.Managed || q.Warning || q.Liquidation {
+			return false
+		}
+		return !(excludeCaution && q.Caution)
+	})
+}
+
+// ===== 블랙/화이트리스트, 쿨다운 =====
+
+// ExclusionSource 영속화된 종목 리스트 (storage.ScreenerListRepository 가 구현)
+type ExclusionSource interface {
+	ListCodes(ctx context.Context, list string) ([]string, error)
+	ActiveCooldowns(ctx context.Context, now time.Time) ([]string, error)
+}
+
+type listStage struct {
+	src ExclusionSource
+	now func() time.Time
+}
+
+// ExclusionLists 블랙리스트/쿨다운 종목을 제외하고, 화이트리스트가 있으면 그 안의 종목만 남긴다.
+func ExclusionLists(src ExclusionSource) Stage {
+	return listStage{src: src, now: time.Now}
+}
+
+func (listStage) Name() string { return "lists" }
+
+func (s listStage) Apply(ctx context.Context, in []*Candidate) ([]*Candidate, error) {
+	black, err := s.src.ListCodes(ctx, storage.ListBlack)
+	if err != nil {
+		return nil, err
+	}
+	white, err := s.src.ListCodes(ctx, storage.ListWhite)
+	if err != nil {
+		return nil, err
+	}
+	cooling, err := s.src.ActiveCooldowns(ctx, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := toSet(black)
+	for code := range toSet(cooling) {
+		excluded[code] = true
+	}
+	allowed := toSet(white)
+
+	out := in[:0:0]
+	var nBlack, nWhite int
+	for _, c := range in {
+		switch {
+		case excluded[c.Stock.Code]:
+			nBlack++
+		case len(allowed) > 0 && !allowed[c.Stock.Code]:
+			nWhite++
+		default:
+			out = append(out, c)
+		}
+	}
+	if nBlack+nWhite > 0 {
+		logger.Info.Printf("[screener] lists: %d blacklisted/cooling down, %d not in whitelist\n", nBlack, nWhite)
+	}
+	return out, nil
+}
+
+func toSet(codes []string) map[string]bool {
+	m := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		m[c] = true
+	}
+	return m
+}
+
+// ===== 점수 산정 =====
+
+// Scorer 후보 하나의 원점수를 계산한다. 여러 Scorer 는 순위 정규화 후 가중합된다.
+type Scorer struct {
+	Name   string
+	Weight float64
+	Score  func(c *Candidate) (float64, error)
+}
+
+// MomentumScorer n봉 수익률 (WithCandles 필요)
+func MomentumScorer(n int, weight float64) Scorer {
+	return Scorer{
+		Name:   fmt.Sprintf("momentum(%d)", n),
+		Weight: weight,
+		Score:  func(c *Candidate) (float64, error) { return indicator.ROC(c.Candles, n) },
+	}
+}
+
+// VolumeSurgeScorer 최근 거래량 / n일 평균 거래량 (WithCandles 필요)
+func VolumeSurgeScorer(n int, weight float64) Scorer {
+	return Scorer{
+		Name:   fmt.Sprintf("volume_surge(%d)", n),
+		Weight: weight,
+		Score: func(c *Candidate) (float64, error) {
+			avg, err := indicator.AvgVolume(c.Candles, n)
+			if err != nil || avg == 0 {
+				return 0, indicator.ErrInsufficientData
+			}
+			return float64(c.Candles[len(c.Candles)-1].Volume) / avg, nil
+		},
+	}
+}
+
+// TradingValueScorer 당일 거래대금 (WithQuotes 필요)
+func TradingValueScorer(weight float64) Scorer {
+	return Scorer{
+		Name:   "trading_value",
+		Weight: weight,
+		Score: func(c *Candidate) (float64, error) {
+			if c.Quote == nil {
+				return 0, fmt.Errorf("no quote")
+			}
+			return c.Quote.TradingValue, nil
+		},
+	}
+}
+
+type rankStage struct {
+	scorers []Scorer
+}
+
+// Rank 각 Scorer 의 원점수를 0~1 백분위 순위로 바꿔 가중합한 뒤 내림차순 정렬한다.
+// 점수 계산이 불가능한 후보(데이터 부족 등)는 제외된다.
+func Rank(scorers ...Scorer) Stage { return rankStage{scorers: scorers} }
+
+func (rankStage) Name() string { return "rank" }
+
+func (s rankStage) Apply(ctx context.Context, in []*Candidate) ([]*Candidate, error) {
+	raw := make([][]float64, len(s.scorers))
+	out := in[:0:0]
+	for _, c := range in {
+		vals := make([]float64, len(s.scorers))
+		ok := true
+		for i, sc := range s.scorers {
+			v, err := sc.Score(c)
+			if err != nil {
+				logger.Info.Printf("[screener] rank: %s dropped, %s: %v\n", c.Stock.Code, sc.Name, err)
+				ok = false
+				break
+			}
+			vals[i] = v
+		}
+		if !ok {
+			continue
+		}
+		for i, sc := range s.scorers {
+			c.Values[sc.Name] = vals[i]
+			raw[i] = append(raw[i], vals[i])
+		}
+		out = append(out, c)
+	}
+
+	for i, sc := range s.scorers {
+		pct := percentileRanks(raw[i])
+		for j, c := range out {
+			c.Score += sc.Weight * pct[j]
+		}
+	}
+	for _, c := range out {
+		c.Values["score"] = c.Score
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out, nil
+}
+
+// percentileRanks 값의 순위를 0(최저)~1(최고)로 환산. 동점은 같은 순위.
+func percentileRanks(vals []float64) []float64 {
+	n := len(vals)
+	out := make([]float64, n)
+	if n <= 1 {
+		for i := range out {
+			out[i] = 1
+		}
+		return out
+	}
+	for i, v := range vals {
+		below := 0
+		for _, w := range vals {
+			if w < v {
+				below++
+			}
+		}
+		out[i] = float64(below) / float64(n-1)
+	}
+	return out
+}
+
+// ===== 상위 N개 =====
+
+type topNStage struct {
+	n int
+}
+
+// TopN 앞에서부터 n개만 남긴다. Rank 뒤에 두면 점수 상위 n개.
+func TopN(n int) Stage { return topNStage{n: n} }
+
+func (s topNStage) Name() string { return fmt.Sprintf("top(%d)", s.n) }
+
+func (s topNStage) Apply(ctx context.Context, in []*Candidate) ([]*Candidate, error) {
+	if s.n <= 0 || len(in) <= s.n {
+		return in, nil
+	}
+	return in[:s.n], nil
+}
@@ -0,0 +1,147 @@
+package sizing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoStop 손절 가격이 없어 위험 기반 수량을 계산할 수 없음
+var ErrNoStop = errors.New("sizing: stop price required")
+
+// ErrNoATR ATR 을 구할 수 없음
+var ErrNoATR = errors.New("sizing: ATR required")
+
+// Input 포지션 크기 계산 입력
+type Input struct {
+	Equity float64 // 전체 평가금액
+	Price  float64 // 진입 예정가
+	Stop   float64 // 손절 가격 (0 이면 없음)
+	ATR    float64 // 0 이면 없음
+}
+
+// Sizer 진입 시 목표 포지션 금액(원)을 정한다. 최종 값은 호출 측에서 리스크 한도/예산으로 다시 자른다.
+type Sizer interface {
+	Name() string
+	Size(in Input) (float64, error)
+}
+
+// ATRUser ATR 이 필요한 Sizer 는 이 인터페이스를 구현한다 (호출 측이 일봉을 받을지 결정).
+type ATRUser interface {
+	NeedsATR() bool
+}
+
+// NeedsATR s 가 ATR 을 요구하는지
+func NeedsATR(s Sizer) bool {
+	u, ok := s.(ATRUser)
+	return ok && u.NeedsATR()
+}
+
+// ===== 고정 비율 =====
+
+type fixedFraction struct {
+	fraction float64
+}
+
+// FixedFraction 평가금액 × fraction
+func FixedFraction(fraction float64) Sizer { return fixedFraction{fraction: fraction} }
+
+func (s fixedFraction) Name() string { return fmt.Sprintf("fixed_fraction(%.3f)", s.fraction) }
+
+func (s fixedFraction) Size(in Input) (float64, error) {
+	return in.Equity * s.fraction, nil
+}
+
+// ===== 거래당 고정 위험 =====
+
+type fixedRisk struct {
+	riskPct float64
+}
+
+// FixedRisk 손절 시 손실이 평가금액 × riskPct 가 되도록: 수량 = equity × risk% ÷ (진입가 − 손절가)
+func FixedRisk(riskPct float64) Sizer { return fixedRisk{riskPct: riskPct} }
+
+func (s fixedRisk) Name() string { return fmt.Sprintf("fixed_risk(%.3f)", s.riskPct) }
+
+func (s fixedRisk) Size(in Input) (float64, error) {
+	if in.Stop <= 0 || in.Stop >= in.Price {
+		return 0, ErrNoStop
+	}
+	shares := in.Equity * s.riskPct / (in.Price - in.Stop)
+	return shares * in.Price, nil
+}
+
+// ===== ATR 변동성 목표 =====
+
+type volTarget struct {
+	target float64
+}
+
+// VolTarget 하루 ATR 만큼 움직였을 때 손익이 평가금액 × target 이 되도록: 수량 = equity × target ÷ ATR
+func VolTarget(target float64) Sizer { return volTarget{target: target} }
+
+func (s volTarget) Name() string   { return fmt.Sprintf("atr(%.3f)", s.target) }
+func (s volTarget) NeedsATR() bool { return true }
+
+func (s volTarget) Size(in Input) (float64, error) {
+	if in.ATR <= 0 {
+		return 0, ErrNoATR
+	}
+	shares := in.Equity * s.target / in.ATR
+	return shares * in.Price, nil
+}
+
+// ===== 켈리 =====
+
+type kelly struct {
+	winRate, payoff, fraction, cap float64
+}
+
+// Kelly 켈리 비율 f = W − (1−W)/R 에 fraction 을 곱하고 [0, cap] 으로 자른 평가금액 비율.
+func Kelly(winRate, payoff, fraction, cap float64) Sizer {
+	return kelly{winRate: winRate, payoff: payoff, fraction: fraction, cap: cap}
+}
+
+func (s kelly) Name() string {
+	return fmt.Sprintf("kelly(w=%.2f,r=%.2f,x%.2f,cap=%.2f)", s.winRate, s.payoff, s.fraction, s.cap)
+}
+
+func (s kelly) Size(in Input) (float64, error) {
+	if s.payoff <= 0 {
+		return 0, fmt.Errorf("sizing: kelly payoff must be positive")
+	}
+	f := (s.winRate - (1-s.winRate)/s.payoff) * s.fraction
+	f = math.Max(0, f)
+	if s.cap > 0 {
+		f = math.Min(f, s.cap)
+	}
+	return in.Equity * f, nil
+}
+
+// ===== 설정으로 선택 =====
+
+// Params New 에 쓰이는 값 (해당 방식만 사용)
+type Params struct {
+	Fraction      float64 // fixed_fraction
+	RiskPerTrade  float64 // fixed_risk
+	VolTarget     float64 // atr
+	KellyWinRate  float64
+	KellyPayoff   float64
+	KellyFraction float64
+	KellyCap      float64
+}
+
+// New 이름으로 Sizer 생성: fixed_fraction | fixed_risk | atr | kelly
+func New(name string, p Params) (Sizer, error) {
+	switch name {
+	case "", "fixed_fraction":
+		return FixedFraction(p.Fraction), nil
+	case "fixed_risk":
+		return FixedRisk(p.RiskPerTrade), nil
+	case "atr":
+		return VolTarget(p.VolTarget), nil
+	case "kelly":
+		return Kelly(p.KellyWinRate, p.KellyPayoff, p.KellyFraction, p.KellyCap), nil
+	}
+	return nil, fmt.Errorf("unknown sizer %q (fixed_fraction|fixed_risk|atr|kelly)", name)
+}
@@ -0,0 +1,88 @@
+package sizing
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSize(t *testing.T) {
+	in := Input{Equity: 1_000_000, Price: 100, Stop: 95, ATR: 4}
+
+	tests := []struct {
+		name  string
+		sizer Sizer
+		in    Input
+		value float64
+		err   error
+	}{
+		{name: "fixed fraction", sizer: FixedFraction(0.04), in: in, value: 40_000},
+		{name: "fixed risk", sizer: FixedRisk(0.01), in: in, value: 200_000},
+		{name: "fixed risk without stop", sizer: FixedRisk(0.01), in: Input{Equity: 1_000_000, Price: 100}, err: ErrNoStop},
+		{name: "fixed risk with stop above price", sizer: FixedRisk(0.01), in: Input{Equity: 1_000_000, Price: 100, Stop: 100}, err: ErrNoStop},
+		{name: "atr", sizer: VolTarget(0.002), in: in, value: 50_000},
+		{name: "atr unavailable", sizer: VolTarget(0.002), in: Input{Equity: 1_000_000, Price: 100}, err: ErrNoATR},
+		// f = 0.6 − 0.4/2 = 0.4, 절반 켈리 0.2
+		{name: "half kelly", sizer: Kelly(0.6, 2, 0.5, 0), in: in, value: 200_000},
+		{name: "kelly capped", sizer: Kelly(0.6, 2, 0.5, 0.03), in: in, value: 30_000},
+		{name: "negative edge sizes zero", sizer: Kelly(0.3, 1, 1, 0.05), in: in, value: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.sizer.Size(tt.in)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Size error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(v-tt.value) > 1e-6 {
+				t.Errorf("Size = %.2f, want %.2f", v, tt.value)
+			}
+		})
+	}
+
+	if _, err := Kelly(0.6, 0, 1, 0).Size(in); err == nil {
+		t.Error("kelly with zero payoff: want error")
+	}
+}
+
+func TestNew(t *testing.T) {
+	p := Params{Fraction: 0.04, RiskPerTrade: 0.01, VolTarget: 0.002, KellyWinRate: 0.6, KellyPayoff: 2, KellyFraction: 0.5, KellyCap: 0.03}
+
+	tests := []struct {
+		name     string
+		sizer    string
+		needsATR bool
+		err      bool
+	}{
+		{name: "", sizer: "fixed_fraction(0.040)"},
+		{name: "fixed_fraction", sizer: "fixed_fraction(0.040)"},
+		{name: "fixed_risk", sizer: "fixed_risk(0.010)"},
+		{name: "atr", sizer: "atr(0.002)", needsATR: true},
+		{name: "kelly", sizer: "kelly(w=0.60,r=2.00,x0.50,cap=0.03)"},
+		{name: "martingale", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.name, p)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("New(%q) = %s, want error", tt.name, s.Name())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Name() != tt.sizer || NeedsATR(s) != tt.needsATR {
+				t.Errorf("New(%q) = %s (atr %v), want %s (atr %v)", tt.name, s.Name(), NeedsATR(s), tt.sizer, tt.needsATR)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// 봉 주기
+const (
+	IntervalDaily = "1d"
+)
+
+const dateLayout = "2006-01-02"
+
+// CandleRepository 봉 데이터 캐시 저장소
+type CandleRepository interface {
+	// UpsertCandles 같은 (code, interval, date) 가 있으면 덮어쓴다.
+	UpsertCandles(ctx context.Context, interval string, candles []models.Candle) error
+	// GetCandles [from, to] (날짜 포함) 구간의 봉을 날짜 오름차순으로 반환
+	GetCandles(ctx context.Context, code, interval string, from, to time.Time) ([]models.Candle, error)
+
+	// Coverage 빈틈 없이 저장된 구간. 없으면 ok=false.
+	Coverage(ctx context.Context, code, interval string) (from, to time.Time, ok bool, err error)
+	SetCoverage(ctx context.Context, code, interval string, from, to time.Time) error
+	// CoveredCodes 캐시가 있는 종목 목록 (장 마감 후 일괄 갱신용)
+	CoveredCodes(ctx context.Context, interval string) ([]string, error)
+}
+
+type candleRepo struct {
+	store *SQLiteStore
+}
+
+func NewCandleRepository(store *SQLiteStore) CandleRepository {
+	return &candleRepo{store: store}
+}
+
+func (r *candleRepo) UpsertCandles(ctx context.Context, interval string, candles []models.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+	tx, err := r.store.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const q = `
+INSERT INTO candles (code, interval, date, open, high, low, close, volume)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(code, interval, date) DO UPDATE SET
+    open = excluded.open,
+    high = excluded.high,
+    low = excluded.low,
+    close = excluded.close,
+    volume = excluded.volume`
+	stmt, err := tx.PrepareContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range candles {
+		if _, err := stmt.ExecContext(ctx, c.Code, interval, FormatDate(c.Date), c.Open, c.High, c.Low, c.Close, c.Volume); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *candleRepo) GetCandles(ctx context.Context, code, interval string, from, to time.Time) ([]models.Candle, error) {
+	const q = `
+SELECT date, open, high, low, close, volume
+FROM candles
+WHERE code = ? AND interval = ? AND date >= ? AND date <= ?
+ORDER BY date`
+	rows, err := r.store.DB.QueryContext(ctx, q, code, interval, FormatDate(from), FormatDate(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.Candle
+	for rows.Next() {
+		c := models.Candle{Code: code}
+		var date string
+		if err := rows.Scan(&date, &c.Open, &c.High, &c.Low, &c.Close, &c.Volume); err != nil {
+			return nil, err
+		}
+		if c.Date, err = ParseDate(date); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r *candleRepo) Coverage(ctx context.Context, code, interval string) (time.Time, time.Time, bool, error) {
+	const q = `SELECT from_date, to_date FROM candle_coverage WHERE code = ? AND interval = ?`
+	var fromS, toS string
+	err := r.store.DB.QueryRowContext(ctx, q, code, interval).Scan(&fromS, &toS)
+	if err == sql.ErrNoRows {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	from, err := ParseDate(fromS)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	to, err := ParseDate(toS)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	return from, to, true, nil
+}
+
+func (r *candleRepo) SetCoverage(ctx context.Context, code, interval string, from, to time.Time) error {
+	const q = `
+INSERT INTO candle_coverage (code, interval, from_date, to_date, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(code, interval) DO UPDATE SET
+    from_date = excluded.from_date,
+    to_date = excluded.to_date,
+    updated_at = excluded.updated_at`
+	_, err := r.store.DB.ExecContext(ctx, q, code, interval, FormatDate(from), FormatDate(to), time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+func (r *candleRepo) CoveredCodes(ctx context.Context, interval string) ([]string, error) {
+	rows, err := r.store.DB.QueryContext(ctx, `SELECT code FROM candle_coverage WHERE interval = ? ORDER BY code`, interval)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		out = append(out, code)
+	}
+	return out, rows.Err()
+}
+
+// FormatDate 거래일(KST 기준) 문자열
+func FormatDate(t time.Time) string {
+	return t.In(models.KST).Format(dateLayout)
+}
+
+// ParseDate FormatDate 의 역. KST 자정으로 반환한다.
+func ParseDate(s string) (time.Time, error) {
+	return time.ParseInLocation(dateLayout, s, models.KST)
+}
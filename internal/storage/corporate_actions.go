@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// PositionAdjustment 기업 행위로 한 전략의 포지션을 맞추는 보정 체결과 감사 기록
+type PositionAdjustment struct {
+	Strategy string
+	Code     string
+	Before   string // "수량 @ 평균단가"
+	After    string
+	Trades   []*models.Trade
+}
+
+// CorporateActionLog 적용 시 바뀐 항목
+type CorporateActionLog struct {
+	ActionID  int64
+	Item      string // position | stop | candles
+	Strategy  string
+	Code      string
+	Before    string
+	After     string
+	CreatedAt time.Time
+}
+
+// CorporateActionRepository 분할/무상증자/코드 변경 이벤트와 적용
+type CorporateActionRepository interface {
+	// AddAction (code, kind, ex_date) 기준. 이미 적용된 건은 바꾸지 않는다.
+	AddAction(ctx context.Context, a *models.CorporateAction) error
+	ListActions(ctx context.Context, limit int) ([]*models.CorporateAction, error)
+	// ListPending ExDate 가 asOf 이전이고 아직 적용하지 않은 이벤트 (오래된 순)
+	ListPending(ctx context.Context, asOf time.Time) ([]*models.CorporateAction, error)
+	// ApplyAction 보정 체결, 과거 일봉, 스탑 가격을 한 트랜잭션으로 바꾼다.
+	// 이미 적용된 이벤트면 아무것도 하지 않고 false.
+	ApplyAction(ctx context.Context, a *models.CorporateAction, adjustments []PositionAdjustment, at time.Time) (bool, error)
+	ListActionLog(ctx context.Context, actionID int64) ([]*CorporateActionLog, error)
+}
+
+type corporateActionRepo struct {
+	store *SQLiteStore
+}
+
+func NewCorporateActionRepository(store *SQLiteStore) CorporateActionRepository {
+	return &corporateActionRepo{store: store}
+}
+
+const corporateActionColumns = `id, code, kind, ex_date, ratio, new_code, note, applied_at`
+
+func (r *corporateActionRepo) AddAction(ctx context.Context, a *models.CorporateAction) error {
+	const q = `
+INSERT INTO corporate_actions (code, kind, ex_date, ratio, new_code, note, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(code, kind, ex_date) DO UPDATE SET
+    ratio = excluded.ratio,
+    new_code = excluded.new_code,
+    note = excluded.note
+WHERE corporate_actions.applied_at = ''`
+	_, err := r.store.DB.ExecContext(ctx, q,
+		a.Code, a.Kind, FormatDate(a.ExDate), a.Ratio, a.NewCode, a.Note, time.Now().UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+func (r *corporateActionRepo) ListActions(ctx context.Context, limit int) ([]*models.CorporateAction, error) {
+	rows, err := r.store.DB.QueryContext(ctx,
+		`SELECT `+corporateActionColumns+` FROM corporate_actions ORDER BY ex_date DESC, id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanCorporateActions(rows)
+}
+
+func (r *corporateActionRepo) ListPending(ctx context.Context, asOf time.Time) ([]*models.CorporateAction, error) {
+	rows, err := r.store.DB.QueryContext(ctx,
+		`SELECT `+corporateActionColumns+` FROM corporate_actions WHERE applied_at = '' AND ex_date <= ? ORDER BY ex_date, id`,
+		FormatDate(asOf))
+	if err != nil {
+		return nil, err
+	}
+	return scanCorporateActions(rows)
+}
+
+func (r *corporateActionRepo) ApplyAction(ctx context.Context, a *models.CorporateAction, adjustments []PositionAdjustment, at time.Time) (bool, error) {
+	tx, err := r.store.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	ts := at.UTC().Format(time.RFC3339)
+	res, err := tx.ExecContext(ctx, `UPDATE corporate_actions SET applied_at = ? WHERE id = ? AND applied_at = ''`, ts, a.ID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	logItem := func(item, strategy, code, before, after string) error {
+		_, err := tx.ExecContext(ctx, `
+INSERT INTO corporate_action_log (action_id, item, strategy, code, before, after, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`, a.ID, item, strategy, code, before, after, ts)
+		return err
+	}
+
+	// 1) 포지션: 보정 체결 기록
+	for _, adj := range adjustments {
+		for _, t := range adj.Trades {
+			if _, err := tx.ExecContext(ctx, `
+INSERT INTO trades (code, side, quantity, price, time, strategy)
+VALUES (?, ?, ?, ?, ?, ?)`, t.Code, t.Side, t.Quantity, t.Price, t.Time.UTC().Format(time.RFC3339), t.Strategy); err != nil {
+				return false, err
+			}
+		}
+		if err := logItem("position", adj.Strategy, adj.Code, adj.Before, adj.After); err != nil {
+			return false, err
+		}
+	}
+
+	// 2) 과거 일봉: 배당락일 전 가격 ÷ factor, 거래량 × factor. 코드 변경이면 새 코드로 복사한다.
+	f := a.Factor()
+	ex := FormatDate(a.ExDate)
+	newCode := a.Code
+	if a.Kind == models.CorpRemap {
+		newCode = a.NewCode
+	}
+	var res2 sql.Result
+	if newCode == a.Code {
+		res2, err = tx.ExecContext(ctx, `
+UPDATE candles SET open = open / ?, high = high / ?, low = low / ?, close = close / ?, volume = CAST(ROUND(volume * ?) AS INTEGER)
+WHERE code = ? AND date < ?`, f, f, f, f, f, a.Code, ex)
+	} else {
+		res2, err = tx.ExecContext(ctx, `
+INSERT OR IGNORE INTO candles (code, interval, date, open, high, low, close, volume)
+SELECT ?, interval, date, open / ?, high / ?, low / ?, close / ?, CAST(ROUND(volume * ?) AS INTEGER)
+FROM candles WHERE code = ? AND date < ?`, newCode, f, f, f, f, f, a.Code, ex)
+	}
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res2.RowsAffected(); n > 0 {
+		if err := logItem("candles", "", newCode, fmt.Sprintf("%s < %s: %d rows", a.Code, ex, n), fmt.Sprintf("price / %g, volume x %g", f, f)); err != nil {
+			return false, err
+		}
+	}
+
+	// 3) 배당락일 전에 진입한 포지션의 스탑/고점/진입가
+	rows, err := tx.QueryContext(ctx,
+		`SELECT `+positionStopColumns+` FROM position_stops WHERE code = ? AND opened_at < ?`,
+		a.Code, a.ExDate.UTC().Format(time.RFC3339))
+	if err != nil {
+		return false, err
+	}
+	var stops []*models.PositionStop
+	for rows.Next() {
+		st, err := scanPositionStop(rows)
+		if err != nil {
+			rows.Close()
+			return false, err
+		}
+		stops = append(stops, st)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	for _, st := range stops {
+		before := fmt.Sprintf("entry %.2f high %.2f stop %.2f", st.EntryPrice, st.HighWater, st.StopPrice)
+		if _, err := tx.ExecContext(ctx, `DELETE FROM position_stops WHERE strategy = ? AND code = ?`, st.Strategy, st.Code); err != nil {
+			return false, err
+		}
+		st.Code = newCode
+		st.EntryPrice /= f
+		st.HighWater /= f
+		st.StopPrice /= f
+		st.UpdatedAt = at
+		if _, err := tx.ExecContext(ctx, `
+INSERT OR REPLACE INTO position_stops (`+positionStopColumns+`)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			st.Strategy, st.Code, st.OpenedAt.UTC().Format(time.RFC3339), st.EntryPrice, st.HighWater, st.StopPrice,
+			st.BreakevenArmed, ts,
+		); err != nil {
+			return false, err
+		}
+		after := fmt.Sprintf("entry %.2f high %.2f stop %.2f", st.EntryPrice, st.HighWater, st.StopPrice)
+		if err := logItem("stop", st.Strategy, newCode, before, after); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *corporateActionRepo) ListActionLog(ctx context.Context, actionID int64) ([]*CorporateActionLog, error) {
+	rows, err := r.store.DB.QueryContext(ctx, `
+SELECT action_id, item, strategy, code, before, after, created_at
+FROM corporate_action_log
+WHERE action_id = ?
+ORDER BY id`, actionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*CorporateActionLog
+	for rows.Next() {
+		var l CorporateActionLog
+		var created string
+		if err := rows.Scan(&l.ActionID, &l.Item, &l.Strategy, &l.Code, &l.Before, &l.After, &created); err != nil {
+			return nil, err
+		}
+		l.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		out = append(out, &l)
+	}
+	return out, rows.Err()
+}
+
+func scanCorporateActions(rows *sql.Rows) ([]*models.CorporateAction, error) {
+	defer rows.Close()
+
+	var out []*models.CorporateAction
+	for rows.Next() {
+		var a models.CorporateAction
+		var ex, applied string
+		if err := rows.Scan(&a.ID, &a.Code, &a.Kind, &ex, &a.Ratio, &a.NewCode, &a.Note, &applied); err != nil {
+			return nil, err
+		}
+		a.ExDate, _ = ParseDate(ex)
+		if applied != "" {
+			a.AppliedAt, _ = time.Parse(time.RFC3339, applied)
+		}
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// PnLRepository 일별 평가금액/손익 기록 (daily_pnl). 날짜는 KST 기준 하루에 한 행.
+type PnLRepository interface {
+	// GetDailyPnL 해당 날짜 기록 (없으면 nil)
+	GetDailyPnL(ctx context.Context, date time.Time) (*models.DailyPnL, error)
+	// LastDailyPnLBefore date 이전 가장 최근 기록 (없으면 nil)
+	LastDailyPnLBefore(ctx context.Context, date time.Time) (*models.DailyPnL, error)
+	// PeakEquity 지금까지 기록된 최고 평가금액 (기록이 없으면 0)
+	PeakEquity(ctx context.Context) (float64, error)
+	// SaveDailyPnL 날짜 기준 upsert
+	SaveDailyPnL(ctx context.Context, p *models.DailyPnL) error
+	// ListDailyPnL 최근 날짜 순
+	ListDailyPnL(ctx context.Context, limit int) ([]*models.DailyPnL, error)
+}
+
+type pnlRepo struct {
+	store *SQLiteStore
+}
+
+func NewPnLRepository(store *SQLiteStore) PnLRepository {
+	return &pnlRepo{store: store}
+}
+
+const dailyPnLColumns = `date, start_equity, equity, profit, drawdown`
+
+func (r *pnlRepo) GetDailyPnL(ctx context.Context, date time.Time) (*models.DailyPnL, error) {
+	row := r.store.DB.QueryRowContext(ctx,
+		`SELECT `+dailyPnLColumns+` FROM daily_pnl WHERE date = ?`, FormatDate(date))
+	return scanDailyPnL(row)
+}
+
+func (r *pnlRepo) LastDailyPnLBefore(ctx context.Context, date time.Time) (*models.DailyPnL, error) {
+	row := r.store.DB.QueryRowContext(ctx,
+		`SELECT `+dailyPnLColumns+` FROM daily_pnl WHERE date < ? ORDER BY date DESC LIMIT 1`, FormatDate(date))
+	return scanDailyPnL(row)
+}
+
+func (r *pnlRepo) PeakEquity(ctx context.Context) (float64, error) {
+	var peak sql.NullFloat64
+	if err := r.store.DB.QueryRowContext(ctx, `SELECT MAX(equity) FROM daily_pnl`).Scan(&peak); err != nil {
+		return 0, err
+	}
+	return peak.Float64, nil
+}
+
+func (r *pnlRepo) SaveDailyPnL(ctx context.Context, p *models.DailyPnL) error {
+	const q = `
+INSERT INTO daily_pnl (date, start_equity, equity, profit, drawdown)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(date) DO UPDATE SET
+    start_equity = excluded.start_equity,
+    equity = excluded.equity,
+    profit = excluded.profit,
+    drawdown = excluded.drawdown`
+	_, err := r.store.DB.ExecContext(ctx, q, FormatDate(p.Date), p.StartEquity, p.Equity, p.Profit, p.Drawdown)
+	return err
+}
+
+func (r *pnlRepo) ListDailyPnL(ctx context.Context, limit int) ([]*models.DailyPnL, error) {
+	rows, err := r.store.DB.QueryContext(ctx,
+		`SELECT `+dailyPnLColumns+` FROM daily_pnl ORDER BY date DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.DailyPnL
+	for rows.Next() {
+		p, err := scanDailyPnL(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func scanDailyPnL(row rowScanner) (*models.DailyPnL, error) {
+	var p models.DailyPnL
+	var date string
+	err := row.Scan(&date, &p.StartEquity, &p.Equity, &p.Profit, &p.Drawdown)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.Date, _ = ParseDate(date)
+	return &p, nil
+}
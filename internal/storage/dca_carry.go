@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// CarryRepository 적립 매수에서 주 단위 절사로 남은 ETF 별 미투자 금액
+type CarryRepository interface {
+	// ListCarry 종목코드 → 누적 미투자 금액 (원)
+	ListCarry(ctx context.Context, strategy string) (map[string]float64, error)
+	SetCarry(ctx context.Context, strategy, code string, amount float64) error
+}
+
+type carryRepo struct {
+	store *SQLiteStore
+}
+
+func NewCarryRepository(store *SQLiteStore) CarryRepository {
+	return &carryRepo{store: store}
+}
+
+func (r *carryRepo) ListCarry(ctx context.Context, strategy string) (map[string]float64, error) {
+	rows, err := r.store.DB.QueryContext(ctx, `SELECT code, amount FROM dca_carry WHERE strategy = ?`, strategy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[string]float64{}
+	for rows.Next() {
+		var code string
+		var amount float64
+		if err := rows.Scan(&code, &amount); err != nil {
+			return nil, err
+		}
+		out[code] = amount
+	}
+	return out, rows.Err()
+}
+
+func (r *carryRepo) SetCarry(ctx context.Context, strategy, code string, amount float64) error {
+	const q = `
+INSERT INTO dca_carry (strategy, code, amount, updated_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(strategy, code) DO UPDATE SET
+    amount = excluded.amount,
+    updated_at = excluded.updated_at`
+	_, err := r.store.DB.ExecContext(ctx, q, strategy, code, amount, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
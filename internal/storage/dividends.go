@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// DividendRepository 배당/분배금 이벤트와 현금 원장 반영
+type DividendRepository interface {
+	// UpsertDividend (strategy, code, ex_date) 기준. 이미 원장에 반영된 건은 바꾸지 않는다.
+	UpsertDividend(ctx context.Context, d *models.Dividend) error
+	// ListDividends 최신 배당락일 순. strategy 가 비어 있으면 전체.
+	ListDividends(ctx context.Context, strategy string, limit int) ([]*models.Dividend, error)
+	// ListUnposted 지급일이 asOf 이전이고 아직 원장에 반영하지 않은 배당
+	ListUnposted(ctx context.Context, asOf time.Time) ([]*models.Dividend, error)
+	// PostDividend 원장 입금 기록과 반영 표시를 한 트랜잭션으로 남긴다 (다시 호출해도 중복되지 않는다).
+	PostDividend(ctx context.Context, d *models.Dividend, at time.Time) error
+	// ListReinvestable 원장에 반영됐지만 아직 재투자 예산에 쓰지 않은 배당
+	ListReinvestable(ctx context.Context, strategy string) ([]*models.Dividend, error)
+	MarkReinvested(ctx context.Context, ids []int64, at time.Time) error
+}
+
+type dividendRepo struct {
+	store *SQLiteStore
+}
+
+func NewDividendRepository(store *SQLiteStore) DividendRepository {
+	return &dividendRepo{store: store}
+}
+
+const dividendColumns = `id, strategy, code, ex_date, pay_date, per_share, quantity, gross, tax, net, source, posted_at, reinvested_at`
+
+func (r *dividendRepo) UpsertDividend(ctx context.Context, d *models.Dividend) error {
+	const q = `
+INSERT INTO dividends (strategy, code, ex_date, pay_date, per_share, quantity, gross, tax, net, source)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(strategy, code, ex_date) DO UPDATE SET
+    pay_date = excluded.pay_date,
+    per_share = excluded.per_share,
+    quantity = excluded.quantity,
+    gross = excluded.gross,
+    tax = excluded.tax,
+    net = excluded.net,
+    source = excluded.source
+WHERE dividends.posted_at = ''`
+	_, err := r.store.DB.ExecContext(ctx, q,
+		d.Strategy, d.Code, d.ExDate.Format("2006-01-02"), d.PayDate.Format("2006-01-02"),
+		d.PerShare, d.Quantity, d.Gross, d.Tax, d.Net, d.Source,
+	)
+	return err
+}
+
+func (r *dividendRepo) ListDividends(ctx context.Context, strategy string, limit int) ([]*models.Dividend, error) {
+	q := `SELECT ` + dividendColumns + `
+FROM dividends
+WHERE (? = '' OR strategy = ?)
+ORDER BY ex_date DESC, id DESC
+LIMIT ?`
+	rows, err := r.store.DB.QueryContext(ctx, q, strategy, strategy, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanDividends(rows)
+}
+
+func (r *dividendRepo) ListUnposted(ctx context.Context, asOf time.Time) ([]*models.Dividend, error) {
+	q := `SELECT ` + dividendColumns + `
+FROM dividends
+WHERE posted_at = '' AND pay_date <= ?
+ORDER BY pay_date, id`
+	rows, err := r.store.DB.QueryContext(ctx, q, asOf.In(models.KST).Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	return scanDividends(rows)
+}
+
+func (r *dividendRepo) PostDividend(ctx context.Context, d *models.Dividend, at time.Time) error {
+	tx, err := r.store.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ts := at.UTC().Format(time.RFC3339)
+	const ledger = `
+INSERT OR IGNORE INTO cash_ledger (strategy, date, kind, code, amount, ref, note, created_at)
+VALUES (?, ?, 'dividend', ?, ?, ?, ?, ?)`
+	note := fmt.Sprintf("%d주 x %.2f, 세금 %.0f", d.Quantity, d.PerShare, d.Tax)
+	if _, err := tx.ExecContext(ctx, ledger,
+		d.Strategy, d.PayDate.Format("2006-01-02"), d.Code, d.Net, fmt.Sprintf("dividend:%d", d.ID), note, ts,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE dividends SET posted_at = ? WHERE id = ? AND posted_at = ''`, ts, d.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *dividendRepo) ListReinvestable(ctx context.Context, strategy string) ([]*models.Dividend, error) {
+	q := `SELECT ` + dividendColumns + `
+FROM dividends
+WHERE strategy = ? AND posted_at != '' AND reinvested_at = ''
+ORDER BY pay_date, id`
+	rows, err := r.store.DB.QueryContext(ctx, q, strategy)
+	if err != nil {
+		return nil, err
+	}
+	return scanDividends(rows)
+}
+
+func (r *dividendRepo) MarkReinvested(ctx context.Context, ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := []interface{}{at.UTC().Format(time.RFC3339)}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	q := `UPDATE dividends SET reinvested_at = ? WHERE reinvested_at = '' AND id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)`
+	_, err := r.store.DB.ExecContext(ctx, q, args...)
+	return err
+}
+
+func scanDividends(rows *sql.Rows) ([]*models.Dividend, error) {
+	defer rows.Close()
+
+	var out []*models.Dividend
+	for rows.Next() {
+		var d models.Dividend
+		var ex, pay, posted, reinvested string
+		if err := rows.Scan(
+			&d.ID, &d.Strategy, &d.Code, &ex, &pay, &d.PerShare, &d.Quantity,
+			&d.Gross, &d.Tax, &d.Net, &d.Source, &posted, &reinvested,
+		); err != nil {
+			return nil, err
+		}
+		d.ExDate, _ = time.ParseInLocation("2006-01-02", ex, models.KST)
+		d.PayDate, _ = time.ParseInLocation("2006-01-02", pay, models.KST)
+		if posted != "" {
+			d.PostedAt, _ = time.Parse(time.RFC3339, posted)
+		}
+		if reinvested != "" {
+			d.ReinvestedAt, _ = time.Parse(time.RFC3339, reinvested)
+		}
+		out = append(out, &d)
+	}
+	return out, rows.Err()
+}
+
+// LedgerRepository 현금 원장 조회
+type LedgerRepository interface {
+	// ListLedger 최신순. strategy 가 비어 있으면 전체.
+	ListLedger(ctx context.Context, strategy string, limit int) ([]*models.LedgerEntry, error)
+}
+
+type ledgerRepo struct {
+	store *SQLiteStore
+}
+
+func NewLedgerRepository(store *SQLiteStore) LedgerRepository {
+	return &ledgerRepo{store: store}
+}
+
+func (r *ledgerRepo) ListLedger(ctx context.Context, strategy string, limit int) ([]*models.LedgerEntry, error) {
+	const q = `
+SELECT id, strategy, date, kind, code, amount, ref, note
+FROM cash_ledger
+WHERE (? = '' OR strategy = ?)
+ORDER BY date DESC, id DESC
+LIMIT ?`
+	rows, err := r.store.DB.QueryContext(ctx, q, strategy, strategy, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.LedgerEntry
+	for rows.Next() {
+		var e models.LedgerEntry
+		var date string
+		if err := rows.Scan(&e.ID, &e.Strategy, &date, &e.Kind, &e.Code, &e.Amount, &e.Ref, &e.Note); err != nil {
+			return nil, err
+		}
+		e.Date, _ = time.ParseInLocation("2006-01-02", date, models.KST)
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// DryRunTradeRepository --dry-run 에서 실제로 내지 않은 (가상) 체결 기록. 실계좌 trades 와 섞이지 않는다.
+type DryRunTradeRepository interface {
+	InsertDryRunTrade(ctx context.Context, runID string, t *models.Trade) error
+	// ListDryRunTrades runID 가 비어 있으면 전체 (최신순)
+	ListDryRunTrades(ctx context.Context, runID string, limit int) ([]*models.Trade, error)
+}
+
+type dryRunTradeRepo struct {
+	store *SQLiteStore
+}
+
+func NewDryRunTradeRepository(store *SQLiteStore) DryRunTradeRepository {
+	return &dryRunTradeRepo{store: store}
+}
+
+func (r *dryRunTradeRepo) InsertDryRunTrade(ctx context.Context, runID string, t *models.Trade) error {
+	const q = `
+INSERT INTO dry_run_trades (run_id, code, side, quantity, price, time, strategy)
+VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.store.DB.ExecContext(ctx, q,
+		runID, t.Code, t.Side, t.Quantity, t.Price, t.Time.UTC().Format(time.RFC3339), t.Strategy,
+	)
+	return err
+}
+
+func (r *dryRunTradeRepo) ListDryRunTrades(ctx context.Context, runID string, limit int) ([]*models.Trade, error) {
+	const q = `
+SELECT id, code, side, quantity, price, time, strategy
+FROM dry_run_trades
+WHERE (? = '' OR run_id = ?)
+ORDER BY id DESC
+LIMIT ?`
+	rows, err := r.store.DB.QueryContext(ctx, q, runID, runID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanTrades(rows)
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// OrderRepository 주문 의도와 처리 결과 기록 (체결은 trades 에 따로 남는다)
+type OrderRepository interface {
+	InsertOrder(ctx context.Context, o *models.Order) error
+	// ListOrders 최근 주문 (최신순). strategy 가 비어 있으면 전체.
+	ListOrders(ctx context.Context, strategy string, limit int) ([]*models.Order, error)
+}
+
+type orderRepo struct {
+	store *SQLiteStore
+}
+
+func NewOrderRepository(store *SQLiteStore) OrderRepository {
+	return &orderRepo{store: store}
+}
+
+func (r *orderRepo) InsertOrder(ctx context.Context, o *models.Order) error {
+	const q = `
+INSERT INTO orders (strategy, code, side, intent_quantity, target_value, limit_price, reason, status, quantity, price, error, created_at, arrival_price)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	in := o.Intent
+	res, err := r.store.DB.ExecContext(ctx, q,
+		in.Strategy, in.Code, in.Side, in.Quantity, in.TargetValue, in.LimitPrice, in.Reason,
+		o.Status, o.Quantity, o.Price, o.Error, o.CreatedAt.UTC().Format(time.RFC3339), o.ArrivalPrice,
+	)
+	if err != nil {
+		return err
+	}
+	o.ID, err = res.LastInsertId()
+	return err
+}
+
+func (r *orderRepo) ListOrders(ctx context.Context, strategy string, limit int) ([]*models.Order, error) {
+	const q = `
+SELECT id, strategy, code, side, intent_quantity, target_value, limit_price, reason, status, quantity, price, error, created_at, arrival_price
+FROM orders
+WHERE (? = '' OR strategy = ?)
+ORDER BY created_at DESC, id DESC
+LIMIT ?`
+	rows, err := r.store.DB.QueryContext(ctx, q, strategy, strategy, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.Order
+	for rows.Next() {
+		var o models.Order
+		var created string
+		in := &o.Intent
+		if err := rows.Scan(
+			&o.ID, &in.Strategy, &in.Code, &in.Side, &in.Quantity, &in.TargetValue, &in.LimitPrice, &in.Reason,
+			&o.Status, &o.Quantity, &o.Price, &o.Error, &created, &o.ArrivalPrice,
+		); err != nil {
+			return nil, err
+		}
+		o.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		out = append(out, &o)
+	}
+	return out, rows.Err()
+}
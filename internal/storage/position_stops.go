@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// PositionStopRepository 포지션별 트레일링/본전 스탑 상태
+type PositionStopRepository interface {
+	// GetStop 없으면 nil
+	GetStop(ctx context.Context, strategy, code string) (*models.PositionStop, error)
+	SaveStop(ctx context.Context, st *models.PositionStop) error
+	DeleteStop(ctx context.Context, strategy, code string) error
+	ListStops(ctx context.Context, strategy string) ([]*models.PositionStop, error)
+}
+
+type positionStopRepo struct {
+	store *SQLiteStore
+}
+
+func NewPositionStopRepository(store *SQLiteStore) PositionStopRepository {
+	return &positionStopRepo{store: store}
+}
+
+const positionStopColumns = `strategy, code, opened_at, entry_price, high_water, stop_price, breakeven_armed, updated_at`
+
+func (r *positionStopRepo) GetStop(ctx context.Context, strategy, code string) (*models.PositionStop, error) {
+	row := r.store.DB.QueryRowContext(ctx,
+		`SELECT `+positionStopColumns+` FROM position_stops WHERE strategy = ? AND code = ?`, strategy, code)
+	st, err := scanPositionStop(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return st, err
+}
+
+func (r *positionStopRepo) SaveStop(ctx context.Context, st *models.PositionStop) error {
+	const q = `
+INSERT INTO position_stops (` + positionStopColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(strategy, code) DO UPDATE SET
+    opened_at = excluded.opened_at,
+    entry_price = excluded.entry_price,
+    high_water = excluded.high_water,
+    stop_price = excluded.stop_price,
+    breakeven_armed = excluded.breakeven_armed,
+    updated_at = excluded.updated_at`
+	_, err := r.store.DB.ExecContext(ctx, q,
+		st.Strategy,
+		st.Code,
+		st.OpenedAt.UTC().Format(time.RFC3339),
+		st.EntryPrice,
+		st.HighWater,
+		st.StopPrice,
+		st.BreakevenArmed,
+		st.UpdatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+func (r *positionStopRepo) DeleteStop(ctx context.Context, strategy, code string) error {
+	_, err := r.store.DB.ExecContext(ctx, `DELETE FROM position_stops WHERE strategy = ? AND code = ?`, strategy, code)
+	return err
+}
+
+func (r *positionStopRepo) ListStops(ctx context.Context, strategy string) ([]*models.PositionStop, error) {
+	rows, err := r.store.DB.QueryContext(ctx,
+		`SELECT `+positionStopColumns+` FROM position_stops WHERE strategy = ? ORDER BY code`, strategy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.PositionStop
+	for rows.Next() {
+		st, err := scanPositionStop(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+func scanPositionStop(row rowScanner) (*models.PositionStop, error) {
+	var st models.PositionStop
+	var opened, updated string
+	if err := row.Scan(
+		&st.Strategy, &st.Code, &opened, &st.EntryPrice, &st.HighWater, &st.StopPrice, &st.BreakevenArmed, &updated,
+	); err != nil {
+		return nil, err
+	}
+	st.OpenedAt, _ = time.Parse(time.RFC3339, opened)
+	st.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+	return &st, nil
+}
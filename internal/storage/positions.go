@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// PositionRepository 체결로 갱신되는 전략별 포지션
+type PositionRepository interface {
+	// ListPositions 보유 중(수량 > 0)인 포지션. strategy 가 비어 있으면 전체.
+	ListPositions(ctx context.Context, strategy string) ([]*models.Position, error)
+	// ListClosedPositions 최근 청산 순. strategy 가 비어 있으면 전체.
+	ListClosedPositions(ctx context.Context, strategy string, limit int) ([]*models.ClosedPosition, error)
+	// LastTradeID 포지션에 반영한 마지막 체결 id (없으면 0)
+	LastTradeID(ctx context.Context) (int64, error)
+	// SavePositions 바뀐 포지션(0주면 삭제), 청산 기록, 마지막 체결 id 를 한 트랜잭션으로 저장한다.
+	// lastTradeID 가 저장된 값과 다르면 (다른 프로세스가 먼저 반영) 아무것도 쓰지 않고 false.
+	SavePositions(ctx context.Context, changed []*models.Position, closed []*models.ClosedPosition, fromTradeID, lastTradeID int64) (bool, error)
+	// ResetPositions 포지션/청산 기록을 지우고 처음부터 다시 반영하게 한다.
+	ResetPositions(ctx context.Context) error
+}
+
+type positionRepo struct {
+	store *SQLiteStore
+}
+
+func NewPositionRepository(store *SQLiteStore) PositionRepository {
+	return &positionRepo{store: store}
+}
+
+func (r *positionRepo) ListPositions(ctx context.Context, strategy string) ([]*models.Position, error) {
+	const q = `
+SELECT strategy, code, quantity, avg_price, realized_pnl, opened_at
+FROM positions
+WHERE (? = '' OR strategy = ?) AND quantity > 0
+ORDER BY strategy, code`
+	rows, err := r.store.DB.QueryContext(ctx, q, strategy, strategy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.Position
+	for rows.Next() {
+		var p models.Position
+		var opened string
+		if err := rows.Scan(&p.Strategy, &p.Code, &p.Quantity, &p.AvgPrice, &p.Realized, &opened); err != nil {
+			return nil, err
+		}
+		p.OpenedAt, _ = time.Parse(time.RFC3339, opened)
+		out = append(out, &p)
+	}
+	return out, rows.Err()
+}
+
+func (r *positionRepo) ListClosedPositions(ctx context.Context, strategy string, limit int) ([]*models.ClosedPosition, error) {
+	const q = `
+SELECT strategy, code, opened_at, closed_at, realized_pnl
+FROM closed_positions
+WHERE (? = '' OR strategy = ?)
+ORDER BY closed_at DESC, id DESC
+LIMIT ?`
+	rows, err := r.store.DB.QueryContext(ctx, q, strategy, strategy, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.ClosedPosition
+	for rows.Next() {
+		var c models.ClosedPosition
+		var opened, closed string
+		if err := rows.Scan(&c.Strategy, &c.Code, &opened, &closed, &c.Realized); err != nil {
+			return nil, err
+		}
+		c.OpenedAt, _ = time.Parse(time.RFC3339, opened)
+		c.ClosedAt, _ = time.Parse(time.RFC3339, closed)
+		out = append(out, &c)
+	}
+	return out, rows.Err()
+}
+
+func (r *positionRepo) LastTradeID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.store.DB.QueryRowContext(ctx, `SELECT last_trade_id FROM position_sync WHERE id = 1`).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+func (r *positionRepo) SavePositions(ctx context.Context, changed []*models.Position, closed []*models.ClosedPosition, fromTradeID, lastTradeID int64) (bool, error) {
+	tx, err := r.store.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var cur int64
+	if err := tx.QueryRowContext(ctx, `SELECT last_trade_id FROM position_sync WHERE id = 1`).Scan(&cur); err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	if cur != fromTradeID {
+		return false, nil
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, p := range changed {
+		if p.Quantity <= 0 {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM positions WHERE strategy = ? AND code = ?`, p.Strategy, p.Code); err != nil {
+				return false, err
+			}
+			continue
+		}
+		const q = `
+INSERT INTO positions (strategy, code, quantity, avg_price, realized_pnl, opened_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(strategy, code) DO UPDATE SET
+    quantity = excluded.quantity,
+    avg_price = excluded.avg_price,
+    realized_pnl = excluded.realized_pnl,
+    opened_at = excluded.opened_at,
+    updated_at = excluded.updated_at`
+		if _, err := tx.ExecContext(ctx, q,
+			p.Strategy, p.Code, p.Quantity, p.AvgPrice, p.Realized, p.OpenedAt.UTC().Format(time.RFC3339), now,
+		); err != nil {
+			return false, err
+		}
+	}
+	for _, c := range closed {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO closed_positions (strategy, code, opened_at, closed_at, realized_pnl)
+VALUES (?, ?, ?, ?, ?)`,
+			c.Strategy, c.Code, c.OpenedAt.UTC().Format(time.RFC3339), c.ClosedAt.UTC().Format(time.RFC3339), c.Realized,
+		); err != nil {
+			return false, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO position_sync (id, last_trade_id) VALUES (1, ?)
+ON CONFLICT(id) DO UPDATE SET last_trade_id = excluded.last_trade_id`, lastTradeID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *positionRepo) ResetPositions(ctx context.Context) error {
+	tx, err := r.store.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, q := range []string{`DELETE FROM positions`, `DELETE FROM closed_positions`, `DELETE FROM position_sync`} {
+		if _, err := tx.ExecContext(ctx, q); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"stock-investing/internal/models"
@@ -10,6 +11,12 @@ import (
 type Repository interface {
 	InsertTrade(ctx context.Context, t *models.Trade) error
 	ListTrades(ctx context.Context, limit int) ([]*models.Trade, error)
+	// ListTradesByStrategy 전략별 체결 기록 (오래된 순)
+	ListTradesByStrategy(ctx context.Context, strategy string) ([]*models.Trade, error)
+	// ListTradesByCode 종목별 체결 기록, 전 전략 (오래된 순)
+	ListTradesByCode(ctx context.Context, code string) ([]*models.Trade, error)
+	// ListTradesAfter id 가 afterID 보다 큰 체결 기록 (id 순)
+	ListTradesAfter(ctx context.Context, afterID int64) ([]*models.Trade, error)
 }
 
 type repo struct {
@@ -47,6 +54,49 @@ LIMIT ?`
 	if err != nil {
 		return nil, err
 	}
+	return scanTrades(rows)
+}
+
+func (r *repo) ListTradesByStrategy(ctx context.Context, strategy string) ([]*models.Trade, error) {
+	const q = `
+SELECT id, code, side, quantity, price, time, strategy
+FROM trades
+WHERE strategy = ?
+ORDER BY time, id`
+	rows, err := r.store.DB.QueryContext(ctx, q, strategy)
+	if err != nil {
+		return nil, err
+	}
+	return scanTrades(rows)
+}
+
+func (r *repo) ListTradesByCode(ctx context.Context, code string) ([]*models.Trade, error) {
+	const q = `
+SELECT id, code, side, quantity, price, time, strategy
+FROM trades
+WHERE code = ?
+ORDER BY time, id`
+	rows, err := r.store.DB.QueryContext(ctx, q, code)
+	if err != nil {
+		return nil, err
+	}
+	return scanTrades(rows)
+}
+
+func (r *repo) ListTradesAfter(ctx context.Context, afterID int64) ([]*models.Trade, error) {
+	const q = `
+SELECT id, code, side, quantity, price, time, strategy
+FROM trades
+WHERE id > ?
+ORDER BY id`
+	rows, err := r.store.DB.QueryContext(ctx, q, afterID)
+	if err != nil {
+		return nil, err
+	}
+	return scanTrades(rows)
+}
+
+func scanTrades(rows *sql.Rows) ([]*models.Trade, error) {
 	defer rows.Close()
 
 	var out []*models.Trade
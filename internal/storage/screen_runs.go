@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// ScreenRunRepository 스크리닝 실행 스냅샷 저장/조회
+type ScreenRunRepository interface {
+	InsertScreenRun(ctx context.Context, run *models.ScreenRun) error
+	// ListScreenRuns [from, to) 구간의 실행 목록 (후보 제외). name 이 비어 있으면 전체.
+	ListScreenRuns(ctx context.Context, name string, from, to time.Time) ([]*models.ScreenRun, error)
+	GetScreenRun(ctx context.Context, id int64) (*models.ScreenRun, error)
+	// LatestScreenRunBefore t 이전 가장 최근 실행 (후보 포함). 없으면 nil.
+	LatestScreenRunBefore(ctx context.Context, name string, t time.Time) (*models.ScreenRun, error)
+	CompareScreenRuns(ctx context.Context, fromID, toID int64) (*ScreenRunDiff, error)
+	// CandidateHistory 한 종목이 최근 실행들에서 어떻게 평가됐는지 (최신순)
+	CandidateHistory(ctx context.Context, code string, limit int) ([]*CandidateAt, error)
+}
+
+// ScreenRunDiff 두 실행의 선정 종목 비교
+type ScreenRunDiff struct {
+	From, To *models.ScreenRun
+	Added    []models.ScreenCandidate // To 에서 새로 선정
+	Removed  []models.ScreenCandidate // From 에서 선정됐으나 To 에서 탈락 (To 기준 탈락 사유 포함)
+	Kept     []models.ScreenCandidate // 양쪽 모두 선정 (To 기준 값)
+}
+
+// CandidateAt 특정 실행에서의 종목 평가 결과
+type CandidateAt struct {
+	RunID     int64
+	RunName   string
+	StartedAt time.Time
+	models.ScreenCandidate
+}
+
+type screenRunRepo struct {
+	store *SQLiteStore
+}
+
+func NewScreenRunRepository(store *SQLiteStore) ScreenRunRepository {
+	return &screenRunRepo{store: store}
+}
+
+func (r *screenRunRepo) InsertScreenRun(ctx context.Context, run *models.ScreenRun) error {
+	tx, err := r.store.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO screen_runs (name, params, started_at, finished_at) VALUES (?, ?, ?, ?)`,
+		run.Name,
+		run.Params,
+		run.StartedAt.UTC().Format(time.RFC3339),
+		run.FinishedAt.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	const q = `
+INSERT INTO screen_candidates (run_id, code, name, market, rank, selected, score, dropped_by, indicator_values)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(run_id, code) DO NOTHING`
+	for _, c := range run.Candidates {
+		values, err := json.Marshal(c.Values)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, q, id, c.Code, c.Name, c.Market, c.Rank, c.Selected, c.Score, c.DroppedBy, string(values)); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	run.ID = id
+	return nil
+}
+
+func (r *screenRunRepo) ListScreenRuns(ctx context.Context, name string, from, to time.Time) ([]*models.ScreenRun, error) {
+	const q = `
+SELECT id, name, params, started_at, finished_at
+FROM screen_runs
+WHERE (? = '' OR name = ?) AND started_at >= ? AND started_at < ?
+ORDER BY started_at, id`
+	rows, err := r.store.DB.QueryContext(ctx, q, name, name, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.ScreenRun
+	for rows.Next() {
+		run, err := scanScreenRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, run)
+	}
+	return out, rows.Err()
+}
+
+func (r *screenRunRepo) GetScreenRun(ctx context.Context, id int64) (*models.ScreenRun, error) {
+	row := r.store.DB.QueryRowContext(ctx, `SELECT id, name, params, started_at, finished_at FROM screen_runs WHERE id = ?`, id)
+	run, err := scanScreenRun(row)
+	if err != nil {
+		return nil, err
+	}
+	if run.Candidates, err = r.candidates(ctx, id); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (r *screenRunRepo) LatestScreenRunBefore(ctx context.Context, name string, t time.Time) (*models.ScreenRun, error) {
+	const q = `
+SELECT id FROM screen_runs
+WHERE name = ? AND started_at < ?
+ORDER BY started_at DESC, id DESC
+LIMIT 1`
+	var id int64
+	err := r.store.DB.QueryRowContext(ctx, q, name, t.UTC().Format(time.RFC3339)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetScreenRun(ctx, id)
+}
+
+func (r *screenRunRepo) CompareScreenRuns(ctx context.Context, fromID, toID int64) (*ScreenRunDiff, error) {
+	from, err := r.GetScreenRun(ctx, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := r.GetScreenRun(ctx, toID)
+	if err != nil {
+		return nil, err
+	}
+	return DiffScreenRuns(from, to), nil
+}
+
+// DiffScreenRuns 선정 종목 기준으로 두 실행을 비교한다.
+func DiffScreenRuns(from, to *models.ScreenRun) *ScreenRunDiff {
+	d := &ScreenRunDiff{From: from, To: to}
+	prevSelected := map[string]bool{}
+	for _, c := range from.Candidates {
+		if c.Selected {
+			prevSelected[c.Code] = true
+		}
+	}
+	toByCode := map[string]models.ScreenCandidate{}
+	for _, c := range to.Candidates {
+		toByCode[c.Code] = c
+		switch {
+		case c.Selected && prevSelected[c.Code]:
+			d.Kept = append(d.Kept, c)
+		case c.Selected:
+			d.Added = append(d.Added, c)
+		}
+	}
+	for _, c := range from.Candidates {
+		if !c.Selected {
+			continue
+		}
+		now, ok := toByCode[c.Code]
+		switch {
+		case !ok:
+			c.DroppedBy = "not_in_universe"
+			d.Removed = append(d.Removed, c)
+		case !now.Selected:
+			d.Removed = append(d.Removed, now)
+		}
+	}
+	sort.Slice(d.Removed, func(i, j int) bool { return d.Removed[i].Code < d.Removed[j].Code })
+	return d
+}
+
+func (r *screenRunRepo) CandidateHistory(ctx context.Context, code string, limit int) ([]*CandidateAt, error) {
+	const q = `
+SELECT r.id, r.name, r.started_at, c.code, c.name, c.market, c.rank, c.selected, c.score, c.dropped_by, c.indicator_values
+FROM screen_candidates c
+JOIN screen_runs r ON r.id = c.run_id
+WHERE c.code = ?
+ORDER BY r.started_at DESC, r.id DESC
+LIMIT ?`
+	rows, err := r.store.DB.QueryContext(ctx, q, code, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*CandidateAt
+	for rows.Next() {
+		var a CandidateAt
+		var started, values string
+		if err := rows.Scan(
+			&a.RunID, &a.RunName, &started,
+			&a.Code, &a.Name, &a.Market, &a.Rank, &a.Selected, &a.Score, &a.DroppedBy, &values,
+		); err != nil {
+			return nil, err
+		}
+		a.StartedAt, _ = time.Parse(time.RFC3339, started)
+		if err := json.Unmarshal([]byte(values), &a.Values); err != nil {
+			return nil, err
+		}
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
+
+func (r *screenRunRepo) candidates(ctx context.Context, runID int64) ([]models.ScreenCandidate, error) {
+	const q = `
+SELECT code, name, market, rank, selected, score, dropped_by, indicator_values
+FROM screen_candidates
+WHERE run_id = ?
+ORDER BY selected DESC, rank, code`
+	rows, err := r.store.DB.QueryContext(ctx, q, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.ScreenCandidate
+	for rows.Next() {
+		var c models.ScreenCandidate
+		var values string
+		if err := rows.Scan(&c.Code, &c.Name, &c.Market, &c.Rank, &c.Selected, &c.Score, &c.DroppedBy, &values); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(values), &c.Values); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanScreenRun(row rowScanner) (*models.ScreenRun, error) {
+	var run models.ScreenRun
+	var started, finished string
+	if err := row.Scan(&run.ID, &run.Name, &run.Params, &started, &finished); err != nil {
+		return nil, err
+	}
+	run.StartedAt, _ = time.Parse(time.RFC3339, started)
+	run.FinishedAt, _ = time.Parse(time.RFC3339, finished)
+	return &run, nil
+}
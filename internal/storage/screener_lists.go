@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// 스크리너 리스트 이름
+const (
+	ListBlack = "blacklist" // 항상 제외
+	ListWhite = "whitelist" // 비어 있지 않으면 이 종목들만 허용
+)
+
+// ScreenerListRepository 스크리너 블랙/화이트리스트와 손절 후 재진입 금지(쿨다운) 목록
+type ScreenerListRepository interface {
+	AddToList(ctx context.Context, list, code, reason string) error
+	RemoveFromList(ctx context.Context, list, code string) error
+	ListCodes(ctx context.Context, list string) ([]string, error)
+
+	AddCooldown(ctx context.Context, code string, until time.Time, reason string) error
+	ActiveCooldowns(ctx context.Context, now time.Time) ([]string, error)
+}
+
+type screenerListRepo struct {
+	store *SQLiteStore
+}
+
+func NewScreenerListRepository(store *SQLiteStore) ScreenerListRepository {
+	return &screenerListRepo{store: store}
+}
+
+func (r *screenerListRepo) AddToList(ctx context.Context, list, code, reason string) error {
+	const q = `
+INSERT INTO screener_lists (list, code, reason, created_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(list, code) DO UPDATE SET reason = excluded.reason`
+	_, err := r.store.DB.ExecContext(ctx, q, list, code, reason, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+func (r *screenerListRepo) RemoveFromList(ctx context.Context, list, code string) error {
+	const q = `DELETE FROM screener_lists WHERE list = ? AND code = ?`
+	_, err := r.store.DB.ExecContext(ctx, q, list, code)
+	return err
+}
+
+func (r *screenerListRepo) ListCodes(ctx context.Context, list string) ([]string, error) {
+	const q = `SELECT code FROM screener_lists WHERE list = ? ORDER BY code`
+	return r.queryCodes(ctx, q, list)
+}
+
+// AddCooldown until 까지 code 의 신규 진입을 막는다. 이미 있으면 더 늦은 만료일로 갱신.
+func (r *screenerListRepo) AddCooldown(ctx context.Context, code string, until time.Time, reason string) error {
+	const q = `
+INSERT INTO cooldowns (code, until, reason, created_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(code) DO UPDATE SET
+    until = MAX(cooldowns.until, excluded.until),
+    reason = excluded.reason`
+	_, err := r.store.DB.ExecContext(
+		ctx,
+		q,
+		code,
+		until.UTC().Format(time.RFC3339),
+		reason,
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+func (r *screenerListRepo) ActiveCooldowns(ctx context.Context, now time.Time) ([]string, error) {
+	const q = `SELECT code FROM cooldowns WHERE until > ? ORDER BY code`
+	return r.queryCodes(ctx, q, now.UTC().Format(time.RFC3339))
+}
+
+func (r *screenerListRepo) queryCodes(ctx context.Context, q string, args ...interface{}) ([]string, error) {
+	rows, err := r.store.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		out = append(out, code)
+	}
+	return out, rows.Err()
+}
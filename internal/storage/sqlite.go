@@ -31,6 +31,21 @@ func (s *SQLiteStore) Close() error {
 }
 
 func (s *SQLiteStore) Migrate() error {
+	// 초기 positions 테이블(code 단일 키)은 쓰인 적이 없으므로 전략별 스키마로 다시 만든다.
+	if ok, err := s.hasTable("positions"); err != nil {
+		return err
+	} else if ok {
+		current, err := s.hasColumn("positions", "strategy")
+		if err != nil {
+			return err
+		}
+		if !current {
+			if _, err := s.DB.Exec(`DROP TABLE positions`); err != nil {
+				return err
+			}
+		}
+	}
+
 	schema := `
 CREATE TABLE IF NOT EXISTS trades (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -42,10 +57,32 @@ CREATE TABLE IF NOT EXISTS trades (
     strategy TEXT NOT NULL
 );
 
+-- 체결 기록에서 갱신되는 전략별 보유 포지션 (0주가 되면 closed_positions 로 옮긴다)
 CREATE TABLE IF NOT EXISTS positions (
-    code TEXT PRIMARY KEY,
+    strategy TEXT NOT NULL,
+    code TEXT NOT NULL,
     quantity INTEGER NOT NULL,
-    avg_price REAL NOT NULL
+    avg_price REAL NOT NULL,
+    realized_pnl REAL NOT NULL DEFAULT 0,
+    opened_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (strategy, code)
+);
+
+CREATE TABLE IF NOT EXISTS closed_positions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    strategy TEXT NOT NULL,
+    code TEXT NOT NULL,
+    opened_at TEXT NOT NULL,
+    closed_at TEXT NOT NULL,
+    realized_pnl REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_closed_positions_strategy ON closed_positions(strategy, closed_at);
+
+-- positions 에 반영한 마지막 체결 id
+CREATE TABLE IF NOT EXISTS position_sync (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    last_trade_id INTEGER NOT NULL
 );
 
 CREATE TABLE IF NOT EXISTS daily_pnl (
@@ -54,7 +91,226 @@ CREATE TABLE IF NOT EXISTS daily_pnl (
     profit REAL NOT NULL,
     drawdown REAL NOT NULL
 );
+
+CREATE TABLE IF NOT EXISTS candles (
+    code TEXT NOT NULL,
+    interval TEXT NOT NULL,
+    date TEXT NOT NULL,
+    open REAL NOT NULL,
+    high REAL NOT NULL,
+    low REAL NOT NULL,
+    close REAL NOT NULL,
+    volume INTEGER NOT NULL,
+    PRIMARY KEY (code, interval, date)
+);
+
+-- candles 에 빈틈 없이 채워진 구간. 휴장일과 누락을 구분하기 위해 따로 관리한다.
+CREATE TABLE IF NOT EXISTS candle_coverage (
+    code TEXT NOT NULL,
+    interval TEXT NOT NULL,
+    from_date TEXT NOT NULL,
+    to_date TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (code, interval)
+);
+
+CREATE TABLE IF NOT EXISTS screener_lists (
+    list TEXT NOT NULL,
+    code TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (list, code)
+);
+
+CREATE TABLE IF NOT EXISTS screen_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    params TEXT NOT NULL,
+    started_at TEXT NOT NULL,
+    finished_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_screen_runs_name_started ON screen_runs (name, started_at);
+
+CREATE TABLE IF NOT EXISTS screen_candidates (
+    run_id INTEGER NOT NULL REFERENCES screen_runs(id),
+    code TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    market TEXT NOT NULL DEFAULT '',
+    rank INTEGER NOT NULL,
+    selected INTEGER NOT NULL,
+    score REAL NOT NULL,
+    dropped_by TEXT NOT NULL DEFAULT '',
+    indicator_values TEXT NOT NULL DEFAULT '{}',
+    PRIMARY KEY (run_id, code)
+);
+CREATE INDEX IF NOT EXISTS idx_screen_candidates_code ON screen_candidates (code);
+
+CREATE TABLE IF NOT EXISTS cooldowns (
+    code TEXT PRIMARY KEY,
+    until TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS position_stops (
+    strategy TEXT NOT NULL,
+    code TEXT NOT NULL,
+    opened_at TEXT NOT NULL,
+    entry_price REAL NOT NULL,
+    high_water REAL NOT NULL,
+    stop_price REAL NOT NULL,
+    breakeven_armed INTEGER NOT NULL DEFAULT 0,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (strategy, code)
+);
+
+CREATE TABLE IF NOT EXISTS strategy_state (
+    strategy TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (strategy, key)
+);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    strategy TEXT NOT NULL,
+    code TEXT NOT NULL,
+    side TEXT NOT NULL,
+    intent_quantity INTEGER NOT NULL,
+    target_value REAL NOT NULL,
+    limit_price REAL NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    price REAL NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    arrival_price REAL NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_orders_strategy_created ON orders(strategy, created_at);
+
+CREATE TABLE IF NOT EXISTS dry_run_trades (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_id TEXT NOT NULL,
+    code TEXT NOT NULL,
+    side TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    price REAL NOT NULL,
+    time TEXT NOT NULL,
+    strategy TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_dry_run_trades_run ON dry_run_trades(run_id);
+
+CREATE TABLE IF NOT EXISTS dividends (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    strategy TEXT NOT NULL,
+    code TEXT NOT NULL,
+    ex_date TEXT NOT NULL,
+    pay_date TEXT NOT NULL,
+    per_share REAL NOT NULL,
+    quantity INTEGER NOT NULL,
+    gross REAL NOT NULL,
+    tax REAL NOT NULL,
+    net REAL NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    posted_at TEXT NOT NULL DEFAULT '',
+    reinvested_at TEXT NOT NULL DEFAULT '',
+    UNIQUE (strategy, code, ex_date)
+);
+
+CREATE TABLE IF NOT EXISTS cash_ledger (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    strategy TEXT NOT NULL,
+    date TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    code TEXT NOT NULL DEFAULT '',
+    amount REAL NOT NULL,
+    ref TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    UNIQUE (kind, ref)
+);
+
+CREATE TABLE IF NOT EXISTS corporate_actions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    ex_date TEXT NOT NULL,
+    ratio REAL NOT NULL,
+    new_code TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    applied_at TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    UNIQUE (code, kind, ex_date)
+);
+
+-- 기업 행위 적용 시 바뀐 항목 (보정 전/후)
+CREATE TABLE IF NOT EXISTS corporate_action_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action_id INTEGER NOT NULL REFERENCES corporate_actions(id),
+    item TEXT NOT NULL,
+    strategy TEXT NOT NULL DEFAULT '',
+    code TEXT NOT NULL,
+    before TEXT NOT NULL,
+    after TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_corporate_action_log_action ON corporate_action_log(action_id);
+
+CREATE TABLE IF NOT EXISTS dca_carry (
+    strategy TEXT NOT NULL,
+    code TEXT NOT NULL,
+    amount REAL NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (strategy, code)
+);
 `
-	_, err := s.DB.Exec(schema)
+	if _, err := s.DB.Exec(schema); err != nil {
+		return err
+	}
+
+	// 기존 DB 의 테이블에 나중에 추가된 컬럼
+	if err := s.addColumn("orders", "arrival_price", "REAL NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	return s.addColumn("daily_pnl", "start_equity", "REAL NOT NULL DEFAULT 0")
+}
+
+// addColumn 컬럼이 없을 때만 ALTER TABLE ADD COLUMN (여러 번 실행해도 안전)
+func (s *SQLiteStore) addColumn(table, column, decl string) error {
+	ok, err := s.hasColumn(table, column)
+	if err != nil || ok {
+		return err
+	}
+	_, err = s.DB.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, decl))
 	return err
 }
+
+func (s *SQLiteStore) hasColumn(table, column string) (bool, error) {
+	rows, err := s.DB.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, typ        string
+			dflt             sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+func (s *SQLiteStore) hasTable(table string) (bool, error) {
+	var n int
+	err := s.DB.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&n)
+	return n > 0, err
+}
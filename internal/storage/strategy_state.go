@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// StateRepository 전략별로 실행 간에 유지해야 하는 값 (마지막 리밸런싱일 등)
+type StateRepository interface {
+	// GetState 없으면 ok=false
+	GetState(ctx context.Context, strategy, key string) (value string, ok bool, err error)
+	SetState(ctx context.Context, strategy, key, value string) error
+}
+
+type stateRepo struct {
+	store *SQLiteStore
+}
+
+func NewStateRepository(store *SQLiteStore) StateRepository {
+	return &stateRepo{store: store}
+}
+
+func (r *stateRepo) GetState(ctx context.Context, strategy, key string) (string, bool, error) {
+	var v string
+	err := r.store.DB.QueryRowContext(ctx,
+		`SELECT value FROM strategy_state WHERE strategy = ? AND key = ?`, strategy, key).Scan(&v)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return v, true, nil
+}
+
+func (r *stateRepo) SetState(ctx context.Context, strategy, key, value string) error {
+	const q = `
+INSERT INTO strategy_state (strategy, key, value, updated_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(strategy, key) DO UPDATE SET
+    value = excluded.value,
+    updated_at = excluded.updated_at`
+	_, err := r.store.DB.ExecContext(ctx, q, strategy, key, value, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
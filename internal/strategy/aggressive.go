@@ -5,10 +5,33 @@ import (
 	"math"
 	"time"
 
+	"stock-investing/internal/indicator"
 	"stock-investing/internal/models"
+	"stock-investing/internal/sizing"
 	"stock-investing/pkg/logger"
 )
 
+func init() {
+	Register(Definition{
+		Name:        string(ModeAggressive),
+		Description: "스크리너 기반 성장주 매수 + 익절/손절/트레일링 청산",
+		Config: []ConfigField{
+			{Key: "AGGRESSIVE_ALLOC", Default: "0.24", Description: "전체 평가금액 중 aggressive 비중"},
+			{Key: "AGGRESSIVE_MAX_HOLDINGS", Default: "6", Description: "동시 보유 종목 수"},
+			{Key: "AGGRESSIVE_POSITION_RATIO", Default: "0.04", Description: "종목당 비중"},
+			{Key: "AGGRESSIVE_PYRAMIDING", Default: "false", Description: "보유 종목 추가 매수"},
+			{Key: "AGGRESSIVE_SIZER", Default: "fixed_fraction", Description: "fixed_fraction | fixed_risk | atr | kelly"},
+			{Key: "AGGRESSIVE_TAKE_PROFIT", Default: "0.2", Description: "익절 수익률"},
+			{Key: "AGGRESSIVE_STOP_LOSS", Default: "0.06", Description: "손절 손실률"},
+			{Key: "AGGRESSIVE_MAX_HOLDING_DAYS", Default: "90", Description: "최대 보유 기간 (일)"},
+			{Key: "AGGRESSIVE_TRAILING_STOP", Default: "0", Description: "고점 대비 트레일링 스탑"},
+			{Key: "AGGRESSIVE_BREAKEVEN_AFTER", Default: "0", Description: "본전 스탑 전환 수익률"},
+			{Key: "SCREENER_RULES_FILE", Default: "", Description: "스크리너 규칙 파일"},
+		},
+		New: func(deps Deps) (Runner, error) { return NewAggressiveStrategy(deps), nil },
+	})
+}
+
 type AggressiveStrategy struct {
 	deps Deps
 }
@@ -17,46 +40,107 @@ func NewAggressiveStrategy(deps Deps) *AggressiveStrategy {
 	return &AggressiveStrategy{deps: deps}
 }
 
+func (s *AggressiveStrategy) Name() string { return string(ModeAggressive) }
+
 func (s *AggressiveStrategy) Run(ctx context.Context) error {
 	logger.Info.Println("[aggressive] running high-volatility strategy")
+	if err := runSleeve(ctx, s.deps, s); err != nil {
+		return err
+	}
+	logger.Info.Println("[aggressive] high-volatility strategy completed")
+	return nil
+}
 
-	// 0) 간단한 equity 가정 (TODO: 실제 계좌 평가액으로 대체)
-	equity := 10_000_000.0
+// Plan 청산 매도 의도 + 빈 슬롯/남은 예산 안의 신규(또는 피라미딩) 매수 의도.
+// 손실 한도에 걸리면 청산 의도만 담아 에러와 함께 반환한다.
+func (s *AggressiveStrategy) Plan(ctx context.Context, bal *models.Balance) (*Plan, error) {
+	equity := bal.TotalEquity
 
-	// 0-1) 최대 손실 한도 체크
-	if err := s.deps.Risk.CheckMaxLoss(ctx, equity); err != nil {
-		logger.Error.Printf("[aggressive] max loss check failed: %v\n", err)
-		return err
+	// 0) 보유 포지션 청산 조건 체크 (익절/손절/최대 보유기간). 매도는 손실 한도와 무관하게 진행한다.
+	plan, err := planExits(ctx, s.deps, "aggressive", s.deps.Aggressive.Exit, s.deps.Aggressive.CooldownDays)
+	if err != nil {
+		logger.Error.Printf("[aggressive] exit check failed: %v\n", err)
+		plan = &Plan{}
+	}
+	exiting := map[string]bool{}
+	for _, in := range plan.Intents {
+		exiting[in.Code] = true
+	}
+
+	// 0-1) 손실 한도에 걸리면 청산만 낸다
+	if halt, err := checkMaxLoss(ctx, s.deps, "aggressive", equity); halt {
+		return plan, err
+	}
+
+	// 0-2) 현재 보유 종목과 남은 예산. 이번에 청산할 종목은 빠진 것으로 본다.
+	cfg := s.deps.Aggressive
+	positions, err := openPositions(ctx, s.deps, "aggressive")
+	if err != nil {
+		logger.Error.Printf("[aggressive] failed to load positions: %v\n", err)
+		return plan, err
+	}
+	held := map[string]*models.Position{}
+	var deployed float64
+	for _, p := range positions {
+		if exiting[p.Code] {
+			continue
+		}
+		held[p.Code] = p
+		deployed += p.AvgPrice * float64(p.Quantity)
+	}
+	budget := cfg.Alloc*equity - deployed
+	slots := cfg.MaxHoldings - len(held)
+	logger.Info.Printf("[aggressive] equity=%.0f holdings=%d/%d deployed=%.0f budget=%.0f\n",
+		equity, len(held), cfg.MaxHoldings, deployed, budget)
+	if budget <= 0 {
+		logger.Info.Println("[aggressive] sleeve budget exhausted, skipping buys")
+		return plan, nil
+	}
+	if slots <= 0 && !cfg.Pyramiding {
+		logger.Info.Println("[aggressive] all slots filled, skipping buys")
+		return plan, nil
 	}
 
 	// 1) 스크리너로 후보 종목 리스트 얻기
 	stocks, err := s.deps.Screener.Screen(ctx)
 	if err != nil {
 		logger.Error.Printf("[aggressive] screener error: %v\n", err)
-		return err
+		return plan, err
 	}
 	if len(stocks) == 0 {
 		logger.Info.Println("[aggressive] no screened stocks, skipping")
-		return nil
+		return plan, nil
 	}
 
-	// 간단한 예: 상위 3개 종목만 매수 시도
-	maxCandidates := 3
-	if len(stocks) < maxCandidates {
-		maxCandidates = len(stocks)
-	}
-	candidates := stocks[:maxCandidates]
-
-	for _, stock := range candidates {
+	// 스크리너 순위대로 빈 슬롯만 채운다. 보유 종목은 피라미딩일 때만 목표 비중까지 추가 매수.
+	for _, stock := range stocks {
 		select {
 		case <-ctx.Done():
 			logger.Info.Println("[aggressive] context canceled, aborting")
-			return ctx.Err()
+			return plan, ctx.Err()
 		default:
 		}
 
+		if budget <= 0 {
+			break
+		}
+
+		// 같은 실행에서 청산하는 종목은 다시 사지 않는다.
+		if exiting[stock.Code] {
+			logger.Info.Printf("[aggressive] %s exiting in this run, skipping buy\n", stock.Code)
+			continue
+		}
+
+		pos, isHeld := held[stock.Code]
+		switch {
+		case isHeld && !cfg.Pyramiding:
+			continue
+		case !isHeld && slots <= 0:
+			continue
+		}
+
 		// 2) 현재가 조회
-		price, err := s.deps.KIS.GetQuote(ctx, stock.Code)
+		price, err := s.deps.Market.GetQuote(ctx, stock.Code)
 		if err != nil {
 			logger.Error.Printf("[aggressive] failed to get quote for %s: %v\n", stock.Code, err)
 			continue
@@ -66,44 +150,79 @@ func (s *AggressiveStrategy) Run(ctx context.Context) error {
 			continue
 		}
 
-		// 3) 포지션당 목표 비중 (예: 4%)
-		targetValue := equity * 0.04
+		// 3) sizer 로 목표 포지션 금액을 정하고, 리스크 한도와 남은 예산으로 자른다.
+		targetValue, err := positionValue(ctx, s.deps, "aggressive", cfg.Sizer, cfg.PositionRatio, cfg.Exit, stock.Code, equity, price)
+		if err != nil {
+			logger.Info.Printf("[aggressive] sizing failed for %s: %v\n", stock.Code, err)
+			continue
+		}
+		targetValue = math.Min(targetValue, s.deps.Risk.MaxPositionValue(equity))
+		if isHeld {
+			targetValue -= pos.AvgPrice * float64(pos.Quantity)
+		}
+		targetValue = math.Min(targetValue, budget)
 		qty := int64(math.Floor(targetValue / price))
 		if qty <= 0 {
 			logger.Info.Printf("[aggressive] amount too small for %s (price=%.2f, target=%.2f)\n", stock.Code, price, targetValue)
 			continue
 		}
 
-		// 3-1) 포지션 사이즈 리스크 체크
-		newPosValue := float64(qty) * price
-		if err := s.deps.Risk.CheckPositionSize(ctx, equity, newPosValue); err != nil {
-			logger.Error.Printf("[aggressive] position risk check failed for %s: %v\n", stock.Code, err)
-			continue
+		// 4) 리스크 체크(기존 보유분 포함)/주문/기록은 실행 파이프라인에서
+		reason := "entry"
+		if isHeld {
+			reason = "pyramid"
 		}
-
-		// 4) 매수 주문 (stub)
-		if err := s.deps.KIS.Buy(ctx, stock.Code, qty); err != nil {
-			logger.Error.Printf("[aggressive] buy failed for %s: %v\n", stock.Code, err)
-			continue
-		}
-
-		// 5) 트레이드 기록
-		trade := &models.Trade{
+		plan.Intents = append(plan.Intents, models.OrderIntent{
+			Strategy: "aggressive",
 			Code:     stock.Code,
 			Side:     "BUY",
 			Quantity: qty,
-			Price:    price,
-			Time:     time.Now(),
-			Strategy: "aggressive",
-		}
-		if err := s.deps.Repo.InsertTrade(ctx, trade); err != nil {
-			logger.Error.Printf("[aggressive] failed to insert trade for %s: %v\n", stock.Code, err)
-			continue
+			Reason:   reason,
+		})
+		budget -= float64(qty) * price
+		if !isHeld {
+			slots--
 		}
+		logger.Info.Printf("[aggressive] plan buy %s x %d @ %.2f (%s, slots left %d, budget %.0f)\n",
+			stock.Code, qty, price, reason, slots, budget)
+	}
+
+	return plan, nil
+}
 
-		logger.Info.Printf("[aggressive] buy %s x %d @ %.2f\n", stock.Code, qty, price)
+// positionValue sizer 로 목표 포지션 금액을 계산한다. sizer 가 nil 이면 fraction 고정 비율.
+// 손절가는 exit 의 고정 손절률과 ATR 트레일링 중 더 높은 (가까운) 쪽을 쓴다.
+func positionValue(ctx context.Context, deps Deps, name string, sizer sizing.Sizer, fraction float64, exit ExitRules,
+	code string, equity, price float64) (float64, error) {
+	if sizer == nil {
+		sizer = sizing.FixedFraction(fraction)
 	}
 
-	logger.Info.Println("[aggressive] high-volatility strategy completed")
-	return nil
+	in := sizing.Input{Equity: equity, Price: price}
+	if sizing.NeedsATR(sizer) || exit.TrailingATR > 0 {
+		now := time.Now()
+		n := exit.atrPeriod()
+		candles, err := deps.Market.GetDailyCandles(ctx, code, now.AddDate(0, 0, -n*2-10), now)
+		if err == nil {
+			in.ATR, err = indicator.ATR(candles, n)
+		}
+		if err != nil {
+			logger.Info.Printf("[%s] ATR unavailable for %s: %v\n", name, code, err)
+			in.ATR = 0
+		}
+	}
+	if exit.StopLoss > 0 {
+		in.Stop = price * (1 - exit.StopLoss)
+	}
+	if exit.TrailingATR > 0 && in.ATR > 0 {
+		in.Stop = math.Max(in.Stop, price-exit.TrailingATR*in.ATR)
+	}
+
+	v, err := sizer.Size(in)
+	if err != nil {
+		return 0, err
+	}
+	logger.Info.Printf("[%s] size %s: %s price=%.2f stop=%.2f atr=%.2f -> %.0f\n",
+		name, code, sizer.Name(), price, in.Stop, in.ATR, v)
+	return v, nil
 }
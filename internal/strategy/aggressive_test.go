@@ -0,0 +1,44 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"stock-investing/internal/sizing"
+)
+
+func TestPositionValue(t *testing.T) {
+	deps := Deps{Market: &fakeMarket{}}
+
+	tests := []struct {
+		name  string
+		sizer sizing.Sizer
+		exit  ExitRules
+		value float64
+		err   error
+	}{
+		{name: "nil sizer uses the fraction", exit: ExitRules{StopLoss: 0.05}, value: 40_000},
+		{name: "fixed risk uses the stop loss", sizer: sizing.FixedRisk(0.01), exit: ExitRules{StopLoss: 0.05}, value: 200_000},
+		{name: "fixed risk without stop loss", sizer: sizing.FixedRisk(0.01), err: sizing.ErrNoStop},
+		{name: "atr without candles", sizer: sizing.VolTarget(0.002), exit: ExitRules{StopLoss: 0.05}, err: sizing.ErrNoATR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := positionValue(context.Background(), deps, "aggressive", tt.sizer, 0.04, tt.exit, "A", 1_000_000, 100)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("positionValue error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !near(v, tt.value) {
+				t.Errorf("positionValue = %.2f, want %.2f", v, tt.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"stock-investing/internal/indicator"
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+// DCA 변형
+const (
+	DCAFixed          = "fixed"           // 매일 고정 금액
+	DCAValueAveraging = "value_averaging" // 목표 성장 경로까지 부족한 만큼
+	DCADip            = "dip"             // 고점/이동평균 대비 하락 시 금액 확대
+)
+
+const (
+	stateVATarget = "va_target"
+	stateVADate   = "va_date"
+)
+
+// DCAConfig 적립 방식 설정
+type DCAConfig struct {
+	Mode string
+
+	// value averaging
+	VAGrowth      float64 // 목표 경로 연 성장률 (0.08)
+	VAMaxMultiple float64 // 하루 투입 상한 = DailyAmount × 배수 (0 이면 무제한)
+
+	// dip-weighted
+	DipThreshold  float64 // 기준 대비 이만큼 낮으면 확대 (0.05 = -5%)
+	DipLookback   int     // 고점/이동평균 기간 (일봉 개수)
+	DipBasis      string  // "high" | "ma"
+	DipMultiplier float64 // 확대 배수 (2 = 2배)
+}
+
+// dailyAllocation 설정된 DCA 방식으로 오늘 ETF 별 투입 금액을 정하고 결정 근거를 로그로 남긴다.
+// carry 는 예산에 따로 더해지는 이월 금액으로, value averaging 은 이를 부족분에서 뺀다.
+func (s *StableStrategy) dailyAllocation(ctx context.Context, holdings []*etfHolding, carry map[string]float64, now time.Time) (map[string]float64, error) {
+	cfg := s.deps.Stable
+	base := float64(cfg.DailyAmount)
+
+	switch cfg.DCA.Mode {
+	case DCAValueAveraging:
+		var carried float64
+		for _, c := range carry {
+			carried += c
+		}
+		amount, err := s.valueAveragingAmount(ctx, holdings, carried, now)
+		if err != nil {
+			return nil, err
+		}
+		return allocateDCA(holdings, amount), nil
+
+	case DCADip:
+		alloc := allocateDCA(holdings, base)
+		for _, h := range holdings {
+			if alloc[h.Code] <= 0 {
+				continue
+			}
+			ref, dd, err := s.dipDrawdown(ctx, h, now)
+			if err != nil {
+				logger.Info.Printf("[stable] dca decision mode=dip %s: no reference (%v), base amount %.0f\n", h.Code, err, alloc[h.Code])
+				continue
+			}
+			mult := 1.0
+			if cfg.DCA.DipThreshold > 0 && dd >= cfg.DCA.DipThreshold && cfg.DCA.DipMultiplier > 0 {
+				mult = cfg.DCA.DipMultiplier
+			}
+			logger.Info.Printf("[stable] dca decision mode=dip %s: price=%.2f %s(%d)=%.2f drawdown=%.2f%% x%.2f -> %.0f\n",
+				h.Code, h.Price, cfg.DCA.DipBasis, cfg.DCA.DipLookback, ref, dd*100, mult, alloc[h.Code]*mult)
+			alloc[h.Code] *= mult
+		}
+		return alloc, nil
+
+	default:
+		logger.Info.Printf("[stable] dca decision mode=fixed amount=%.0f\n", base)
+		return allocateDCA(holdings, base), nil
+	}
+}
+
+// valueAveragingAmount 목표 가치 경로: 매 거래일 전일 목표 × 성장 + DailyAmount.
+// 오늘 투입액 = 목표 − 현재 평가금액 − 이월 금액 (음수면 0, 상한 적용). 이월 금액은 이전 부족분 중
+// 아직 사지 못한 돈이라 예산에 그대로 더해지므로, 빼지 않으면 같은 부족분을 두 번 채운다.
+// 목표는 strategy_state 에 저장한다.
+func (s *StableStrategy) valueAveragingAmount(ctx context.Context, holdings []*etfHolding, carried float64, now time.Time) (float64, error) {
+	cfg := s.deps.Stable
+	base := float64(cfg.DailyAmount)
+	today := now.In(models.KST).Format("2006-01-02")
+
+	var value float64
+	for _, h := range holdings {
+		value += h.Value
+	}
+
+	target := value + base
+	if s.deps.State != nil {
+		v, ok, err := s.deps.State.GetState(ctx, "stable", stateVATarget)
+		if err != nil {
+			return 0, err
+		}
+		d, _, err := s.deps.State.GetState(ctx, "stable", stateVADate)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			prev, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return 0, err
+			}
+			target = prev
+			if d != today {
+				last, err := time.ParseInLocation("2006-01-02", d, models.KST)
+				if err != nil {
+					return 0, err
+				}
+				years := now.Sub(last).Hours() / 24 / 365
+				target = prev*math.Pow(1+cfg.DCA.VAGrowth, years) + base
+			}
+		}
+		if err := s.deps.State.SetState(ctx, "stable", stateVATarget, strconv.FormatFloat(target, 'f', 0, 64)); err != nil {
+			return 0, err
+		}
+		if err := s.deps.State.SetState(ctx, "stable", stateVADate, today); err != nil {
+			return 0, err
+		}
+	}
+
+	amount := math.Max(0, target-value-carried)
+	capped := amount
+	if cfg.DCA.VAMaxMultiple > 0 {
+		capped = math.Min(amount, base*cfg.DCA.VAMaxMultiple)
+	}
+	logger.Info.Printf("[stable] dca decision mode=value_averaging target=%.0f value=%.0f carry=%.0f gap=%.0f invest=%.0f\n",
+		target, value, carried, target-value-carried, capped)
+	return capped, nil
+}
+
+// dipDrawdown 기준가(N일 고점 또는 이동평균) 와 그 대비 하락률 (양수 = 기준보다 낮음)
+func (s *StableStrategy) dipDrawdown(ctx context.Context, h *etfHolding, now time.Time) (float64, float64, error) {
+	n := s.deps.Stable.DCA.DipLookback
+	if n <= 0 {
+		n = 60
+	}
+	// 거래일 n개를 확보할 만큼 넉넉하게 달력일로 요청
+	candles, err := s.deps.Market.GetDailyCandles(ctx, h.Code, now.AddDate(0, 0, -n*2-10), now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var ref float64
+	if s.deps.Stable.DCA.DipBasis == "ma" {
+		ref, err = indicator.SMA(candles, n)
+	} else {
+		ref, err = indicator.Highest(candles, n)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if ref <= 0 {
+		return 0, 0, indicator.ErrInsufficientData
+	}
+	return ref, 1 - h.Price/ref, nil
+}
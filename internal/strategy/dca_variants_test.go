@@ -0,0 +1,71 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+func TestValueAveragingAmount(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, models.KST)
+	holdings := func(value float64) []*etfHolding {
+		return []*etfHolding{{Code: "A", Value: value, Target: 1}}
+	}
+
+	tests := []struct {
+		name       string
+		prevTarget string // 저장된 목표 ("" = 없음)
+		prevDate   string
+		value      float64
+		carried    float64
+		maxMult    float64
+		amount     float64
+		target     string // 저장되는 목표
+	}{
+		{name: "first day invests the base", value: 1000, amount: 100, target: "1100"},
+		{name: "carry is netted out of the gap", value: 1000, carried: 30, amount: 70, target: "1100"},
+		{name: "next day adds the base", prevTarget: "1000", prevDate: "2025-05-30", value: 900, carried: 50,
+			amount: 150, target: "1100"},
+		{name: "same day keeps the target", prevTarget: "1500", prevDate: "2025-06-02", value: 1000,
+			amount: 500, target: "1500"},
+		{name: "capped at the multiple", prevTarget: "1500", prevDate: "2025-06-02", value: 1000, maxMult: 3,
+			amount: 300, target: "1500"},
+		{name: "above the path buys nothing", prevTarget: "1000", prevDate: "2025-06-02", value: 1200,
+			amount: 0, target: "1000"},
+		{name: "carry covering the gap buys nothing", prevTarget: "1100", prevDate: "2025-06-02", value: 1000, carried: 150,
+			amount: 0, target: "1100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := memState{}
+			if tt.prevTarget != "" {
+				state["stable/"+stateVATarget] = tt.prevTarget
+				state["stable/"+stateVADate] = tt.prevDate
+			}
+			s := NewStableStrategy(Deps{
+				State: state,
+				Stable: StableConfig{
+					DailyAmount: 100,
+					DCA:         DCAConfig{Mode: "value_averaging", VAGrowth: 0, VAMaxMultiple: tt.maxMult},
+				},
+			})
+
+			amount, err := s.valueAveragingAmount(context.Background(), holdings(tt.value), tt.carried, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if amount != tt.amount {
+				t.Errorf("amount = %.0f, want %.0f", amount, tt.amount)
+			}
+			if got := state["stable/"+stateVATarget]; got != tt.target {
+				t.Errorf("stored target = %s, want %s", got, tt.target)
+			}
+			if got := state["stable/"+stateVADate]; got != "2025-06-02" {
+				t.Errorf("stored date = %s, want 2025-06-02", got)
+			}
+		})
+	}
+}
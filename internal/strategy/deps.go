@@ -2,25 +2,123 @@ package strategy
 
 import (
 	"context"
+	"time"
 
 	"stock-investing/internal/kis"
+	"stock-investing/internal/models"
 	"stock-investing/internal/risk"
 	"stock-investing/internal/screener"
+	"stock-investing/internal/sizing"
 	"stock-investing/internal/storage"
 )
 
 // Stable용 설정만 우선 넣고, 나중에 Aggressive용도 추가 가능
 type StableConfig struct {
 	ETFs        []string
+	Weights     map[string]float64 // ETF 별 목표 비중 (비어 있으면 동일 비중)
 	DailyAmount int64
+
+	Alloc          float64 // 전체 평가금액 중 stable 슬리브 목표 비중 (0.56)
+	RebalanceDays  int     // 정기 리밸런싱 주기 (일)
+	RebalanceDrift float64 // 목표 비중과의 차이가 이보다 크면 주기와 무관하게 리밸런싱 (0.05 = 5%p)
+
+	DCA DCAConfig
+	// DCASession 적립/리밸런싱 주문을 낼 세션 (models.Session*, 비어 있으면 정규장). 같은 실행에서 나가므로 세션도 같다.
+	DCASession string
+
+	// ReinvestDividends 입금된 세후 배당을 적립 예산에 더한다 (Deps.Dividends 가 있을 때만).
+	ReinvestDividends bool
+}
+
+type AggressiveConfig struct {
+	Alloc         float64 // 전체 평가금액 중 aggressive 슬리브 비중 (0.24)
+	MaxHoldings   int     // 동시 보유 종목 수 상한 (6)
+	PositionRatio float64 // 종목당 목표 비중 (전체 평가금액 대비, 0.04)
+	// Sizer nil 이면 PositionRatio 고정 비율. 결과는 risk.Manager.MaxPositionValue 와 예산으로 잘린다.
+	Sizer      sizing.Sizer
+	Pyramiding bool // 보유 종목 추가 매수 허용 (종목당 목표 비중까지)
+
+	Exit         ExitRules
+	CooldownDays int // 손절 청산 후 스크리너에서 제외할 기간
+}
+
+// MeanReversionConfig 대형주 평균 회귀 슬리브
+type MeanReversionConfig struct {
+	Alloc         float64 // 전체 평가금액 중 비중 (0 이면 hybrid 에서 제외)
+	MaxHoldings   int
+	PositionRatio float64 // 종목당 비중 (전체 평가금액 대비)
+	// Sizer nil 이면 PositionRatio 고정 비율. 결과는 risk.Manager.MaxPositionValue 와 예산으로 잘린다.
+	Sizer sizing.Sizer
+
+	BBPeriod  int     // 볼린저 밴드 기간 (20)
+	BBK       float64 // 표준편차 배수 (2)
+	RSIPeriod int     // 단기 RSI 기간 (2)
+	RSIEntry  float64 // RSI 가 이 값 미만이면 과매도 (10)
+
+	// Exit MeanTarget 은 BBPeriod (중심선 회귀), MaxHoldingDays 는 N 일 시간 청산
+	Exit ExitRules
+	// Universe 후보 종목 (KOSPI 200 유동성 종목). nil 이면 Deps.Screener.
+	Universe screener.Screener
+}
+
+// RotationConfig ETF 모멘텀 로테이션 슬리브
+type RotationConfig struct {
+	Alloc           float64  // 전체 평가금액 중 비중 (0 이면 hybrid 에서 제외)
+	Universe        []string // 후보 ETF
+	Lookbacks       []int    // 모멘텀 기간 (개월)
+	TopK            int
+	SafeAsset       string  // 절대 모멘텀 탈락 슬롯을 옮길 ETF ("" 이면 현금)
+	AbsThreshold    float64 // 모멘텀 점수가 이 값 이하이면 탈락
+	RebalanceMonths int
+	Band            float64 // 슬롯 금액 대비 이 비율 미만의 차이는 거래하지 않는다
+}
+
+// OrderExecutor 주문 의도 처리 파이프라인 (execution.Executor 가 구현). 결과는 intents 와 같은 순서.
+type OrderExecutor interface {
+	Execute(ctx context.Context, equity float64, intents []models.OrderIntent) []*models.Order
+}
+
+// CooldownStore 손절 종목 재진입 금지 목록 (storage.ScreenerListRepository 가 구현)
+type CooldownStore interface {
+	AddCooldown(ctx context.Context, code string, until time.Time, reason string) error
+}
+
+// DividendLedger 원장에 입금됐지만 아직 재투자하지 않은 배당 (dividend.Service 가 구현)
+type DividendLedger interface {
+	PendingReinvestment(ctx context.Context, strategy string) (float64, []int64, error)
+	MarkReinvested(ctx context.Context, ids []int64) error
+}
+
+// MarketData 시세/일봉 조회 (marketdata.Service 또는 kis.Client)
+type MarketData interface {
+	GetQuote(ctx context.Context, code string) (float64, error)
+	GetDailyCandles(ctx context.Context, code string, from, to time.Time) ([]models.Candle, error)
 }
 
 type Deps struct {
 	Ctx      context.Context
 	KIS      *kis.Client
+	Market   MarketData
 	Risk     risk.Manager
 	Screener screener.Screener
 	Repo     storage.Repository
+	// Positions 전략별 보유 포지션. nil 이면 Repo 의 체결 기록으로 계산한다.
+	Positions PositionReader
+	// Exec 모든 주문은 여기로 보낸다. 전략은 KIS 주문 API 를 직접 호출하지 않는다.
+	Exec OrderExecutor
+	// Cooldowns nil 이면 손절 후 쿨다운을 기록하지 않는다.
+	Cooldowns CooldownStore
+	// Stops nil 이면 트레일링/본전 스탑을 쓰지 않는다.
+	Stops storage.PositionStopRepository
+	// State 전략별 영속 상태 (마지막 리밸런싱일 등)
+	State storage.StateRepository
+	// Carry nil 이면 적립 잔액을 이월하지 않는다.
+	Carry storage.CarryRepository
+	// Dividends nil 이면 배당을 재투자 예산에 넣지 않는다.
+	Dividends DividendLedger
 
-	Stable StableConfig
+	Stable        StableConfig
+	Aggressive    AggressiveConfig
+	MeanReversion MeanReversionConfig
+	Rotation      RotationConfig
 }
@@ -0,0 +1,235 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"stock-investing/internal/indicator"
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+// ExitRules 전략별 청산 규칙. 0 인 항목은 적용하지 않는다.
+type ExitRules struct {
+	TakeProfit     float64 // 평균단가 대비 수익률 (0.2 = +20%)
+	StopLoss       float64 // 평균단가 대비 손실률 (0.06 = -6%)
+	MaxHoldingDays int     // 진입 후 최대 보유 기간 (달력일)
+	MeanTarget     int     // 현재가가 n일 이동평균 이상으로 돌아오면 청산 (평균 회귀)
+
+	// 트레일링/본전 스탑 (Deps.Stops 가 있어야 동작). 둘 다 설정되면 더 높은(타이트한) 스탑을 쓴다.
+	TrailingPct    float64 // 고점 대비 하락률 (0.1 = 고점 -10%)
+	TrailingATR    float64 // 고점 - ATR × 배수
+	ATRPeriod      int     // ATR 기간 (기본 14)
+	BreakevenAfter float64 // 고점이 평균단가 대비 이 수익률에 도달하면 스탑을 평균단가로 올린다
+}
+
+func (r ExitRules) usesStops() bool {
+	return r.TrailingPct > 0 || r.TrailingATR > 0 || r.BreakevenAfter > 0
+}
+
+func (r ExitRules) atrPeriod() int {
+	if r.ATRPeriod > 0 {
+		return r.ATRPeriod
+	}
+	return 14
+}
+
+// 청산 사유
+const (
+	ExitTakeProfit = "take_profit"
+	ExitStopLoss   = "stop_loss"
+	ExitMaxHolding = "max_holding"
+	ExitTrailing   = "trailing_stop"
+	ExitBreakeven  = "breakeven_stop"
+	ExitMean       = "mean_reversion"
+)
+
+// checkExit 청산해야 하면 사유를 반환한다. 손절 > 트레일링/본전 스탑 > 익절 > 평균 회귀 > 기간 순으로 본다.
+// st 는 updateStop 으로 갱신된 스탑 상태 (없으면 nil), mean 은 MeanTarget 이동평균 (없으면 0).
+func (r ExitRules) checkExit(p *models.Position, st *models.PositionStop, price, mean float64, now time.Time) (string, bool) {
+	if p.AvgPrice <= 0 {
+		return "", false
+	}
+	ret := price/p.AvgPrice - 1
+	switch {
+	case r.StopLoss > 0 && ret <= -r.StopLoss:
+		return ExitStopLoss, true
+	case st != nil && st.StopPrice > 0 && price <= st.StopPrice:
+		if st.BreakevenArmed && st.StopPrice <= st.EntryPrice {
+			return ExitBreakeven, true
+		}
+		return ExitTrailing, true
+	case r.TakeProfit > 0 && ret >= r.TakeProfit:
+		return ExitTakeProfit, true
+	case r.MeanTarget > 0 && mean > 0 && price >= mean:
+		return ExitMean, true
+	case r.MaxHoldingDays > 0 && !p.OpenedAt.IsZero() && now.Sub(p.OpenedAt) >= time.Duration(r.MaxHoldingDays)*24*time.Hour:
+		return ExitMaxHolding, true
+	}
+	return "", false
+}
+
+// planExits 전략의 보유 포지션을 현재가와 비교해 청산 조건을 만족하면 전량 매도 의도를 만든다.
+// 반환된 Plan 의 Settle 은 체결된 청산에 대해 손익 로그, 스탑 정리, 손절 쿨다운을 처리한다.
+// 개별 종목 실패는 로그만 남기고 계속 진행한다.
+func planExits(ctx context.Context, deps Deps, strategy string, rules ExitRules, cooldownDays int) (*Plan, error) {
+	positions, err := openPositions(ctx, deps, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	type exit struct {
+		pos    *models.Position
+		reason string
+	}
+	tag := "[" + strategy + "]"
+	var exits []exit
+	plan := &Plan{}
+	for _, p := range positions {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		price, err := deps.Market.GetQuote(ctx, p.Code)
+		if err != nil {
+			logger.Error.Printf("%s exit check: failed to get quote for %s: %v\n", tag, p.Code, err)
+			continue
+		}
+		if price <= 0 {
+			logger.Error.Printf("%s exit check: invalid price for %s: %.2f\n", tag, p.Code, price)
+			continue
+		}
+
+		now := time.Now()
+		var st *models.PositionStop
+		if deps.Stops != nil && rules.usesStops() {
+			if st, err = refreshStop(ctx, deps, rules, p, price, now); err != nil {
+				logger.Error.Printf("%s stop update failed for %s: %v\n", tag, p.Code, err)
+			}
+		}
+
+		var mean float64
+		if rules.MeanTarget > 0 {
+			candles, err := deps.Market.GetDailyCandles(ctx, p.Code, now.AddDate(0, 0, -rules.MeanTarget*2-10), now)
+			if err == nil {
+				mean, err = indicator.SMA(candles, rules.MeanTarget)
+			}
+			if err != nil {
+				logger.Error.Printf("%s exit check: SMA(%d) unavailable for %s: %v\n", tag, rules.MeanTarget, p.Code, err)
+			}
+		}
+
+		reason, ok := rules.checkExit(p, st, price, mean, now)
+		if !ok {
+			continue
+		}
+		exits = append(exits, exit{pos: p, reason: reason})
+		plan.Intents = append(plan.Intents, models.OrderIntent{
+			Strategy: strategy,
+			Code:     p.Code,
+			Side:     "SELL",
+			Quantity: p.Quantity,
+			Reason:   reason,
+		})
+	}
+
+	plan.Settle = func(ctx context.Context, results []*models.Order) {
+		now := time.Now()
+		// 청산 의도는 항상 Intents 앞쪽에 있다 (뒤에 매수 의도가 붙을 수 있음).
+		for i, e := range exits {
+			o, p, reason := results[i], e.pos, e.reason
+			if o.Status != models.OrderFilled {
+				continue
+			}
+
+			pnl := (o.Price - p.AvgPrice) * float64(o.Quantity)
+			logger.Info.Printf("%s exit %s (%s) x %d @ %.2f, avg=%.2f, pnl=%.0f (%.2f%%)\n",
+				tag, p.Code, reason, o.Quantity, o.Price, p.AvgPrice, pnl, (o.Price/p.AvgPrice-1)*100)
+
+			if deps.Stops != nil {
+				if err := deps.Stops.DeleteStop(ctx, strategy, p.Code); err != nil {
+					logger.Error.Printf("%s failed to clear stop for %s: %v\n", tag, p.Code, err)
+				}
+			}
+
+			if reason == ExitStopLoss && deps.Cooldowns != nil && cooldownDays > 0 {
+				until := now.AddDate(0, 0, cooldownDays)
+				if err := deps.Cooldowns.AddCooldown(ctx, p.Code, until, strategy+" "+reason); err != nil {
+					logger.Error.Printf("%s failed to add cooldown for %s: %v\n", tag, p.Code, err)
+				}
+			}
+		}
+	}
+	return plan, nil
+}
+
+// refreshStop 저장된 스탑 상태를 현재가와 진입 이후 일봉 고가로 갱신해 저장한다.
+// 재진입(OpenedAt 변경)이면 새로 시작한다.
+func refreshStop(ctx context.Context, deps Deps, rules ExitRules, p *models.Position, price float64, now time.Time) (*models.PositionStop, error) {
+	st, err := deps.Stops.GetStop(ctx, p.Strategy, p.Code)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil || !st.OpenedAt.Equal(p.OpenedAt) {
+		st = &models.PositionStop{
+			Strategy:  p.Strategy,
+			Code:      p.Code,
+			OpenedAt:  p.OpenedAt,
+			HighWater: p.AvgPrice,
+		}
+	}
+
+	// 진입 다음 날부터의 일봉 고가 + 현재가로 고점 갱신. ATR 계산용으로 진입 전 구간도 받는다.
+	var atr float64
+	from := p.OpenedAt.AddDate(0, 0, -rules.atrPeriod()*2-10)
+	candles, err := deps.Market.GetDailyCandles(ctx, p.Code, from, now)
+	if err != nil {
+		logger.Error.Printf("[%s] failed to get candles for %s, using quote only: %v\n", p.Strategy, p.Code, err)
+	} else {
+		openedDay := p.OpenedAt.In(models.KST).Format("2006-01-02")
+		for _, c := range candles {
+			if c.Date.In(models.KST).Format("2006-01-02") > openedDay && c.High > st.HighWater {
+				st.HighWater = c.High
+			}
+		}
+		if rules.TrailingATR > 0 {
+			if atr, err = indicator.ATR(candles, rules.atrPeriod()); err != nil {
+				logger.Info.Printf("[%s] ATR unavailable for %s: %v\n", p.Strategy, p.Code, err)
+				atr = 0
+			}
+		}
+	}
+
+	updateStop(st, rules, p.AvgPrice, price, atr)
+	st.UpdatedAt = now
+	if err := deps.Stops.SaveStop(ctx, st); err != nil {
+		return st, err
+	}
+	return st, nil
+}
+
+// updateStop 고점과 스탑 가격을 갱신한다. 스탑은 내려가지 않는다.
+func updateStop(st *models.PositionStop, rules ExitRules, entry, price, atr float64) {
+	st.EntryPrice = entry
+	if price > st.HighWater {
+		st.HighWater = price
+	}
+
+	stop := st.StopPrice
+	if rules.TrailingPct > 0 {
+		stop = math.Max(stop, st.HighWater*(1-rules.TrailingPct))
+	}
+	if rules.TrailingATR > 0 && atr > 0 {
+		stop = math.Max(stop, st.HighWater-rules.TrailingATR*atr)
+	}
+	if rules.BreakevenAfter > 0 && st.HighWater >= entry*(1+rules.BreakevenAfter) {
+		st.BreakevenArmed = true
+	}
+	if st.BreakevenArmed {
+		stop = math.Max(stop, entry)
+	}
+	st.StopPrice = stop
+}
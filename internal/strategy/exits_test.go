@@ -0,0 +1,244 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+func TestCheckExit(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, models.KST)
+	rules := ExitRules{TakeProfit: 0.2, StopLoss: 0.06, MaxHoldingDays: 30}
+	pos := func(avg float64, heldDays int) *models.Position {
+		return &models.Position{Code: "A", Quantity: 10, AvgPrice: avg, OpenedAt: now.AddDate(0, 0, -heldDays)}
+	}
+
+	tests := []struct {
+		name   string
+		rules  ExitRules
+		p      *models.Position
+		price  float64
+		reason string
+	}{
+		{name: "stop loss", rules: rules, p: pos(100, 5), price: 94, reason: ExitStopLoss},
+		{name: "just above stop loss", rules: rules, p: pos(100, 5), price: 94.5},
+		{name: "take profit", rules: rules, p: pos(100, 5), price: 121, reason: ExitTakeProfit},
+		{name: "max holding", rules: rules, p: pos(100, 30), price: 105, reason: ExitMaxHolding},
+		{name: "stop loss before max holding", rules: rules, p: pos(100, 40), price: 90, reason: ExitStopLoss},
+		{name: "take profit before max holding", rules: rules, p: pos(100, 40), price: 125, reason: ExitTakeProfit},
+		{name: "no rules", rules: ExitRules{}, p: pos(100, 400), price: 10},
+		{name: "unknown cost", rules: rules, p: pos(0, 5), price: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := tt.rules.checkExit(tt.p, nil, tt.price, 0, now)
+			if reason != tt.reason || ok != (tt.reason != "") {
+				t.Errorf("checkExit = %q %v, want %q", reason, ok, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPlanExits(t *testing.T) {
+	now := time.Now()
+	positions := fakePositions{"aggressive": {
+		{Strategy: "aggressive", Code: "A", Quantity: 10, AvgPrice: 100, OpenedAt: now.AddDate(0, 0, -3)},
+		{Strategy: "aggressive", Code: "B", Quantity: 5, AvgPrice: 100, OpenedAt: now.AddDate(0, 0, -3)},
+		{Strategy: "aggressive", Code: "C", Quantity: 7, AvgPrice: 100, OpenedAt: now.AddDate(0, 0, -3)},
+		{Strategy: "aggressive", Code: "D", Quantity: 2, AvgPrice: 100, OpenedAt: now.AddDate(0, 0, -3)},
+	}}
+	// C 는 시세가 없어 건너뛴다.
+	market := &fakeMarket{quotes: map[string]float64{"A": 90, "B": 105, "D": 130}}
+
+	tests := []struct {
+		name      string
+		status    []string // 청산 의도 별 실행 결과
+		cooldowns []string
+	}{
+		{name: "filled stop loss starts a cooldown", status: []string{models.OrderFilled, models.OrderFilled}, cooldowns: []string{"A"}},
+		{name: "failed exit does not", status: []string{models.OrderFailed, models.OrderFilled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cooldowns := fakeCooldowns{}
+			deps := Deps{Market: market, Positions: positions, Cooldowns: cooldowns}
+			plan, err := planExits(context.Background(), deps, "aggressive", ExitRules{TakeProfit: 0.2, StopLoss: 0.06}, 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := []models.OrderIntent{
+				{Strategy: "aggressive", Code: "A", Side: "SELL", Quantity: 10, Reason: ExitStopLoss},
+				{Strategy: "aggressive", Code: "D", Side: "SELL", Quantity: 2, Reason: ExitTakeProfit},
+			}
+			if len(plan.Intents) != len(want) {
+				t.Fatalf("intents = %+v, want %+v", plan.Intents, want)
+			}
+			for i := range want {
+				if plan.Intents[i] != want[i] {
+					t.Errorf("intent %d = %+v, want %+v", i, plan.Intents[i], want[i])
+				}
+			}
+
+			var results []*models.Order
+			for i, in := range plan.Intents {
+				results = append(results, &models.Order{Intent: in, Status: tt.status[i], Quantity: in.Quantity, Price: 100})
+			}
+			plan.Settle(context.Background(), results)
+			if len(cooldowns) != len(tt.cooldowns) {
+				t.Fatalf("cooldowns = %v, want %v", cooldowns, tt.cooldowns)
+			}
+			for _, code := range tt.cooldowns {
+				if until := cooldowns[code]; until.Sub(now) < 19*24*time.Hour {
+					t.Errorf("cooldown %s until %s, want about 20 days", code, until)
+				}
+			}
+		})
+	}
+}
+
+// memStops 메모리 position_stops
+type memStops map[string]*models.PositionStop
+
+func (m memStops) GetStop(_ context.Context, strategy, code string) (*models.PositionStop, error) {
+	if st, ok := m[strategy+"/"+code]; ok {
+		c := *st
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (m memStops) SaveStop(_ context.Context, st *models.PositionStop) error {
+	c := *st
+	m[st.Strategy+"/"+st.Code] = &c
+	return nil
+}
+
+func (m memStops) DeleteStop(_ context.Context, strategy, code string) error {
+	delete(m, strategy+"/"+code)
+	return nil
+}
+
+func (m memStops) ListStops(context.Context, string) ([]*models.PositionStop, error) { return nil, nil }
+
+func TestUpdateStop(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     ExitRules
+		st        models.PositionStop // 갱신 전 상태
+		price     float64
+		atr       float64
+		highWater float64
+		stop      float64
+		armed     bool
+	}{
+		{name: "trailing pct follows the high", rules: ExitRules{TrailingPct: 0.1}, st: models.PositionStop{HighWater: 100},
+			price: 120, highWater: 120, stop: 108},
+		{name: "stop never moves down", rules: ExitRules{TrailingPct: 0.1}, st: models.PositionStop{HighWater: 120, StopPrice: 108},
+			price: 110, highWater: 120, stop: 108},
+		{name: "trailing ATR", rules: ExitRules{TrailingATR: 2}, st: models.PositionStop{HighWater: 100},
+			price: 130, atr: 5, highWater: 130, stop: 120},
+		{name: "ATR unavailable keeps the stop", rules: ExitRules{TrailingATR: 2}, st: models.PositionStop{HighWater: 100, StopPrice: 95},
+			price: 130, highWater: 130, stop: 95},
+		{name: "tighter of pct and ATR", rules: ExitRules{TrailingPct: 0.1, TrailingATR: 2}, st: models.PositionStop{HighWater: 100},
+			price: 100, atr: 3, highWater: 100, stop: 94},
+		{name: "breakeven arms at the threshold", rules: ExitRules{BreakevenAfter: 0.1}, st: models.PositionStop{HighWater: 100},
+			price: 111, highWater: 111, stop: 100, armed: true},
+		{name: "breakeven stays armed after a pullback", rules: ExitRules{BreakevenAfter: 0.1}, st: models.PositionStop{HighWater: 111, BreakevenArmed: true},
+			price: 101, highWater: 111, stop: 100, armed: true},
+		{name: "breakeven not reached", rules: ExitRules{BreakevenAfter: 0.1}, st: models.PositionStop{HighWater: 100},
+			price: 105, highWater: 105, stop: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := tt.st
+			updateStop(&st, tt.rules, 100, tt.price, tt.atr)
+			if st.HighWater != tt.highWater || st.StopPrice != tt.stop || st.BreakevenArmed != tt.armed || st.EntryPrice != 100 {
+				t.Errorf("got high=%.2f stop=%.2f armed=%v entry=%.2f, want %.2f %.2f %v 100",
+					st.HighWater, st.StopPrice, st.BreakevenArmed, st.EntryPrice, tt.highWater, tt.stop, tt.armed)
+			}
+		})
+	}
+}
+
+func TestCheckExitStops(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, models.KST)
+	p := &models.Position{Code: "A", Quantity: 10, AvgPrice: 100, OpenedAt: now.AddDate(0, 0, -5)}
+	rules := ExitRules{StopLoss: 0.06, TakeProfit: 0.5, TrailingPct: 0.1}
+
+	tests := []struct {
+		name   string
+		st     *models.PositionStop
+		price  float64
+		reason string
+	}{
+		{name: "trailing stop hit", st: &models.PositionStop{EntryPrice: 100, StopPrice: 108}, price: 107, reason: ExitTrailing},
+		{name: "above trailing stop", st: &models.PositionStop{EntryPrice: 100, StopPrice: 108}, price: 109},
+		{name: "breakeven stop hit", st: &models.PositionStop{EntryPrice: 100, StopPrice: 100, BreakevenArmed: true}, price: 99, reason: ExitBreakeven},
+		{name: "armed but trailed above entry", st: &models.PositionStop{EntryPrice: 100, StopPrice: 104, BreakevenArmed: true}, price: 103, reason: ExitTrailing},
+		{name: "stop loss wins", st: &models.PositionStop{EntryPrice: 100, StopPrice: 98}, price: 90, reason: ExitStopLoss},
+		{name: "no stop yet", st: &models.PositionStop{EntryPrice: 100}, price: 97},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := rules.checkExit(p, tt.st, tt.price, 0, now)
+			if reason != tt.reason || ok != (tt.reason != "") {
+				t.Errorf("checkExit = %q %v, want %q", reason, ok, tt.reason)
+			}
+		})
+	}
+}
+
+func TestRefreshStop(t *testing.T) {
+	opened := time.Date(2025, 6, 2, 10, 0, 0, 0, models.KST)
+	now := opened.AddDate(0, 0, 5)
+	candle := func(d int, high float64) models.Candle {
+		return models.Candle{Code: "A", Date: time.Date(2025, 6, 2+d, 0, 0, 0, 0, models.KST), High: high, Low: high - 1, Close: high - 0.5}
+	}
+	// 진입일 고가(130)는 진입 전 움직임일 수 있어 고점에 넣지 않는다.
+	market := &fakeMarket{candles: map[string][]models.Candle{"A": {candle(-1, 150), candle(0, 130), candle(1, 110), candle(2, 115)}}}
+	p := &models.Position{Strategy: "aggressive", Code: "A", Quantity: 10, AvgPrice: 100, OpenedAt: opened}
+
+	tests := []struct {
+		name      string
+		saved     *models.PositionStop
+		price     float64
+		highWater float64
+		stop      float64
+	}{
+		{name: "new stop from candles after entry", price: 112, highWater: 115, stop: 103.5},
+		{name: "saved high water is kept", saved: &models.PositionStop{Strategy: "aggressive", Code: "A", OpenedAt: opened, HighWater: 120, StopPrice: 108},
+			price: 112, highWater: 120, stop: 108},
+		{name: "re-entry starts over", saved: &models.PositionStop{Strategy: "aggressive", Code: "A", OpenedAt: opened.AddDate(0, 0, -30), HighWater: 200, StopPrice: 180},
+			price: 112, highWater: 115, stop: 103.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stops := memStops{}
+			if tt.saved != nil {
+				stops.SaveStop(context.Background(), tt.saved)
+			}
+			deps := Deps{Market: market, Stops: stops}
+			st, err := refreshStop(context.Background(), deps, ExitRules{TrailingPct: 0.1}, p, tt.price, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !near(st.HighWater, tt.highWater) || !near(st.StopPrice, tt.stop) || !st.OpenedAt.Equal(opened) {
+				t.Errorf("stop = high %.2f stop %.2f opened %s, want %.2f %.2f %s", st.HighWater, st.StopPrice, st.OpenedAt, tt.highWater, tt.stop, opened)
+			}
+			if saved, _ := stops.GetStop(context.Background(), "aggressive", "A"); saved == nil || !near(saved.StopPrice, tt.stop) {
+				t.Errorf("saved stop = %+v", saved)
+			}
+		})
+	}
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
@@ -2,30 +2,120 @@ package strategy
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"stock-investing/internal/models"
 	"stock-investing/pkg/logger"
 )
 
+func init() {
+	Register(Definition{
+		Name:        string(ModeHybrid),
+		Description: "stable/aggressive 를 Alloc 비중으로 함께 계획하고, 슬리브 간 반대 주문을 상계해 한 번에 실행",
+		Config: []ConfigField{
+			{Key: "STABLE_ALLOC", Default: "0.56", Description: "stable 비중"},
+			{Key: "AGGRESSIVE_ALLOC", Default: "0.24", Description: "aggressive 비중"},
+			{Key: "MEANREV_ALLOC", Default: "0", Description: "mean_reversion 비중 (0 보다 크면 슬리브로 포함)"},
+			{Key: "ROTATION_ALLOC", Default: "0", Description: "momentum_rotation 비중 (0 보다 크면 슬리브로 포함)"},
+			{Key: "MIN_CASH_RATIO", Default: "0.2", Description: "전체 최소 현금 비율"},
+		},
+		New: func(deps Deps) (Runner, error) { return NewHybridStrategy(deps), nil },
+	})
+}
+
 type HybridStrategy struct {
-	stable     *StableStrategy
-	aggressive *AggressiveStrategy
+	deps    Deps
+	sleeves []Sleeve
 }
 
 func NewHybridStrategy(deps Deps) *HybridStrategy {
-	return &HybridStrategy{
-		stable:     NewStableStrategy(deps),
-		aggressive: NewAggressiveStrategy(deps),
+	h := &HybridStrategy{
+		deps: deps,
+		sleeves: []Sleeve{
+			NewStableStrategy(deps),
+			NewAggressiveStrategy(deps),
+		},
+	}
+	// 평균 회귀/로테이션 슬리브는 비중을 준 경우에만 함께 운용한다.
+	if deps.MeanReversion.Alloc > 0 {
+		h.sleeves = append(h.sleeves, NewMeanReversionStrategy(deps))
+	}
+	if deps.Rotation.Alloc > 0 {
+		h.sleeves = append(h.sleeves, NewRotationStrategy(deps))
 	}
+	// 모든 슬리브가 한 시각에 실행되므로 stable 세션과 정규장 중 한쪽은 시간 밖이 된다.
+	if deps.Stable.DCASession != models.SessionRegular {
+		logger.Error.Printf("[hybrid] STABLE_DCA_SESSION=%s shares the hybrid schedule slot with regular-session sleeves; schedule stable separately\n",
+			deps.Stable.DCASession)
+	}
+	return h
 }
 
+// Run 같은 잔고로 각 슬리브를 계획하고 (각자 Alloc × 평가금액 안에서), 모든 의도를 모아
+// 최소 현금 비율을 지킨 뒤 한 번에 실행한다. 한 슬리브의 실패는 다른 슬리브에 영향을 주지 않는다.
 func (h *HybridStrategy) Run(ctx context.Context) error {
 	logger.Info.Println("[hybrid] start")
-	if err := h.stable.Run(ctx); err != nil {
+
+	bal, err := h.deps.KIS.GetBalance(ctx)
+	if err != nil {
+		logger.Error.Printf("[hybrid] failed to get balance: %v\n", err)
 		return err
 	}
-	if err := h.aggressive.Run(ctx); err != nil {
-		return err
+	logger.Info.Printf("[hybrid] equity=%.0f cash=%.0f stable=%.0f%% aggressive=%.0f%%\n",
+		bal.TotalEquity, bal.Cash, h.deps.Stable.Alloc*100, h.deps.Aggressive.Alloc*100)
+
+	type planned struct {
+		name       string
+		plan       *Plan
+		start, end int
 	}
+	var (
+		plans   []planned
+		intents []models.OrderIntent
+		errs    []error
+	)
+	for _, sl := range h.sleeves {
+		plan, err := h.planSleeve(ctx, sl, bal)
+		if err != nil {
+			logger.Error.Printf("[hybrid] %s sleeve error: %v\n", sl.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", sl.Name(), err))
+		}
+		if plan == nil {
+			continue
+		}
+		plans = append(plans, planned{name: sl.Name(), plan: plan, start: len(intents), end: len(intents) + len(plan.Intents)})
+		intents = append(intents, plan.Intents...)
+	}
+
+	// 의도 순서는 stable → aggressive (→ mean_reversion → momentum_rotation) 이므로 현금이 모자라면 뒤 슬리브 매수부터 빠진다.
+	results := executeWithCashFloor(ctx, h.deps, bal, intents)
+	for _, p := range plans {
+		if p.plan.Settle == nil {
+			continue
+		}
+		h.settleSleeve(ctx, p.name, p.plan, results[p.start:p.end])
+	}
+
 	logger.Info.Println("[hybrid] done")
-	return nil
+	return errors.Join(errs...)
+}
+
+// planSleeve 슬리브 계획 중 panic 도 해당 슬리브 에러로 가둔다.
+func (h *HybridStrategy) planSleeve(ctx context.Context, sl Sleeve, bal *models.Balance) (plan *Plan, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			plan, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sl.Plan(ctx, bal)
+}
+
+func (h *HybridStrategy) settleSleeve(ctx context.Context, name string, plan *Plan, results []*models.Order) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error.Printf("[hybrid] %s settle panic: %v\n", name, r)
+		}
+	}()
+	plan.Settle(ctx, results)
 }
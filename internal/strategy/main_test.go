@@ -0,0 +1,62 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/risk"
+	"stock-investing/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+// fakeMarket 고정 시세/일봉. 없는 종목은 에러.
+type fakeMarket struct {
+	quotes  map[string]float64
+	candles map[string][]models.Candle
+}
+
+func (m *fakeMarket) GetQuote(_ context.Context, code string) (float64, error) {
+	if q, ok := m.quotes[code]; ok {
+		return q, nil
+	}
+	return 0, fmt.Errorf("no quote for %s", code)
+}
+
+func (m *fakeMarket) GetDailyCandles(_ context.Context, code string, from, to time.Time) ([]models.Candle, error) {
+	c, ok := m.candles[code]
+	if !ok {
+		return nil, fmt.Errorf("no candles for %s", code)
+	}
+	return c, nil
+}
+
+// fakePositions 전략별 고정 보유 포지션
+type fakePositions map[string][]*models.Position
+
+func (f fakePositions) Positions(_ context.Context, strategy string) ([]*models.Position, error) {
+	return f[strategy], nil
+}
+
+// fakeCooldowns 기록된 쿨다운 (code -> until)
+type fakeCooldowns map[string]time.Time
+
+func (f fakeCooldowns) AddCooldown(_ context.Context, code string, until time.Time, _ string) error {
+	f[code] = until
+	return nil
+}
+
+// fakeRisk 손실 한도 확인만 흉내 낸다 (lossErr 가 nil 이면 통과).
+type fakeRisk struct {
+	risk.Manager
+	lossErr error
+}
+
+func (r fakeRisk) CheckMaxLoss(context.Context, float64) error { return r.lossErr }
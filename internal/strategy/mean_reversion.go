@@ -0,0 +1,188 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"stock-investing/internal/indicator"
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+func init() {
+	Register(Definition{
+		Name:        string(ModeMeanRev),
+		Description: "KOSPI 200 유동성 종목 평균 회귀: 볼린저 하단 이탈 + RSI(2) 과매도 매수, 중심선 회귀/N일 후 청산",
+		Config: []ConfigField{
+			{Key: "MEANREV_ALLOC", Default: "0", Description: "전체 평가금액 중 비중 (0 이면 hybrid 제외, 단독 실행은 빈 계획)"},
+			{Key: "MEANREV_UNIVERSE", Default: "KOSPI 200 대형주 20종목", Description: "후보 종목코드 목록"},
+			{Key: "MEANREV_SCREENER", Default: "", Description: "SCREENER_RULES_FILE 안의 유니버스 스크리너 이름"},
+			{Key: "MEANREV_MAX_HOLDINGS", Default: "5", Description: "동시 보유 종목 수"},
+			{Key: "MEANREV_POSITION_RATIO", Default: "0.03", Description: "종목당 비중 (fixed_fraction)"},
+			{Key: "MEANREV_SIZER", Default: "fixed_fraction", Description: "포지션 크기: fixed_fraction | fixed_risk | atr | kelly"},
+			{Key: "MEANREV_BB_PERIOD", Default: "20", Description: "볼린저 기간 (청산 중심선)"},
+			{Key: "MEANREV_RSI_ENTRY", Default: "10", Description: "RSI(2) 진입 기준"},
+			{Key: "MEANREV_MAX_HOLDING_DAYS", Default: "7", Description: "시간 청산 (달력일)"},
+			{Key: "MEANREV_STOP_LOSS", Default: "0.08", Description: "손절 손실률"},
+		},
+		New: func(deps Deps) (Runner, error) { return NewMeanReversionStrategy(deps), nil },
+	})
+}
+
+// MeanReversionStrategy 유동성 높은 대형주의 단기 과매도 반등을 노린다.
+type MeanReversionStrategy struct {
+	deps Deps
+}
+
+func NewMeanReversionStrategy(deps Deps) *MeanReversionStrategy {
+	return &MeanReversionStrategy{deps: deps}
+}
+
+func (s *MeanReversionStrategy) Name() string { return string(ModeMeanRev) }
+
+func (s *MeanReversionStrategy) Run(ctx context.Context) error {
+	logger.Info.Println("[mean_reversion] running mean-reversion strategy")
+	if err := runSleeve(ctx, s.deps, s); err != nil {
+		return err
+	}
+	logger.Info.Println("[mean_reversion] mean-reversion strategy completed")
+	return nil
+}
+
+// Plan 청산(중심선 회귀/기간/손절) 매도 + 과매도 신호 종목의 신규 매수 의도. MEANREV_ALLOC 이 0 이면 빈 계획.
+func (s *MeanReversionStrategy) Plan(ctx context.Context, bal *models.Balance) (*Plan, error) {
+	const name = string(ModeMeanRev)
+	cfg := s.deps.MeanReversion
+	equity := bal.TotalEquity
+
+	if cfg.Alloc <= 0 {
+		logger.Info.Println("[mean_reversion] MEANREV_ALLOC not set, skipping")
+		return &Plan{}, nil
+	}
+
+	plan, err := planExits(ctx, s.deps, name, cfg.Exit, 0)
+	if err != nil {
+		logger.Error.Printf("[mean_reversion] exit check failed: %v\n", err)
+		plan = &Plan{}
+	}
+	exiting := map[string]bool{}
+	for _, in := range plan.Intents {
+		exiting[in.Code] = true
+	}
+
+	if halt, err := checkMaxLoss(ctx, s.deps, name, equity); halt {
+		return plan, err
+	}
+
+	positions, err := openPositions(ctx, s.deps, name)
+	if err != nil {
+		logger.Error.Printf("[mean_reversion] failed to load positions: %v\n", err)
+		return plan, err
+	}
+	held := map[string]bool{}
+	var deployed float64
+	for _, p := range positions {
+		if exiting[p.Code] {
+			continue
+		}
+		held[p.Code] = true
+		deployed += p.AvgPrice * float64(p.Quantity)
+	}
+	budget := cfg.Alloc*equity - deployed
+	slots := cfg.MaxHoldings - len(held)
+	logger.Info.Printf("[mean_reversion] equity=%.0f holdings=%d/%d deployed=%.0f budget=%.0f\n",
+		equity, len(held), cfg.MaxHoldings, deployed, budget)
+	if budget <= 0 || slots <= 0 {
+		logger.Info.Println("[mean_reversion] no budget or slots left, skipping buys")
+		return plan, nil
+	}
+
+	universe := cfg.Universe
+	if universe == nil {
+		universe = s.deps.Screener
+	}
+	stocks, err := universe.Screen(ctx)
+	if err != nil {
+		logger.Error.Printf("[mean_reversion] universe error: %v\n", err)
+		return plan, err
+	}
+
+	for _, stock := range stocks {
+		select {
+		case <-ctx.Done():
+			return plan, ctx.Err()
+		default:
+		}
+		if budget <= 0 || slots <= 0 {
+			break
+		}
+		if held[stock.Code] || exiting[stock.Code] {
+			continue
+		}
+
+		ok, last, err := s.oversold(ctx, stock.Code)
+		if err != nil {
+			logger.Info.Printf("[mean_reversion] signal unavailable for %s: %v\n", stock.Code, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		price, err := s.deps.Market.GetQuote(ctx, stock.Code)
+		if err != nil || price <= 0 {
+			logger.Error.Printf("[mean_reversion] failed to get quote for %s: %v\n", stock.Code, err)
+			continue
+		}
+		value, err := positionValue(ctx, s.deps, name, cfg.Sizer, cfg.PositionRatio, cfg.Exit, stock.Code, equity, price)
+		if err != nil {
+			logger.Info.Printf("[mean_reversion] sizing failed for %s: %v\n", stock.Code, err)
+			continue
+		}
+		value = math.Min(value, s.deps.Risk.MaxPositionValue(equity))
+		value = math.Min(value, budget)
+		qty := int64(math.Floor(value / price))
+		if qty <= 0 {
+			logger.Info.Printf("[mean_reversion] amount too small for %s (price=%.2f, target=%.2f)\n", stock.Code, price, value)
+			continue
+		}
+
+		plan.Intents = append(plan.Intents, models.OrderIntent{
+			Strategy: name,
+			Code:     stock.Code,
+			Side:     "BUY",
+			Quantity: qty,
+			Reason:   "oversold",
+		})
+		budget -= float64(qty) * price
+		slots--
+		logger.Info.Printf("[mean_reversion] plan buy %s x %d @ %.2f (close=%.2f, slots left %d, budget %.0f)\n",
+			stock.Code, qty, price, last, slots, budget)
+	}
+	return plan, nil
+}
+
+// oversold 마지막 종가가 볼린저 하단 아래이고 RSI(RSIPeriod) 가 RSIEntry 미만인지.
+func (s *MeanReversionStrategy) oversold(ctx context.Context, code string) (bool, float64, error) {
+	cfg := s.deps.MeanReversion
+	now := time.Now()
+	lookback := max(cfg.BBPeriod, cfg.RSIPeriod+1)*2 + 10
+	candles, err := s.deps.Market.GetDailyCandles(ctx, code, now.AddDate(0, 0, -lookback), now)
+	if err != nil {
+		return false, 0, err
+	}
+	mid, _, lower, err := indicator.Bollinger(candles, cfg.BBPeriod, cfg.BBK)
+	if err != nil {
+		return false, 0, err
+	}
+	rsi, err := indicator.RSI(candles, cfg.RSIPeriod)
+	if err != nil {
+		return false, 0, err
+	}
+	last := candles[len(candles)-1].Close
+	ok := last < lower && rsi < cfg.RSIEntry
+	logger.Info.Printf("[mean_reversion] %s close=%.2f bb_lower=%.2f mid=%.2f rsi(%d)=%.1f -> %v\n",
+		code, last, lower, mid, cfg.RSIPeriod, rsi, ok)
+	return ok, last, nil
+}
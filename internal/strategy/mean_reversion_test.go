@@ -0,0 +1,23 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+
+	"stock-investing/internal/models"
+)
+
+func TestMeanReversionWithoutAlloc(t *testing.T) {
+	// 보유 포지션이 있어도 비중이 0 이면 시세/포지션을 보지 않고 빈 계획을 낸다.
+	deps := Deps{
+		Market:    &fakeMarket{},
+		Positions: fakePositions{"mean_reversion": {{Strategy: "mean_reversion", Code: "A", Quantity: 10, AvgPrice: 100}}},
+	}
+	plan, err := NewMeanReversionStrategy(deps).Plan(context.Background(), &models.Balance{Cash: 1_000_000, TotalEquity: 1_000_000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Intents) != 0 || plan.Settle != nil {
+		t.Errorf("plan = %+v, want empty", plan)
+	}
+}
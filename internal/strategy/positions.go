@@ -0,0 +1,25 @@
+package strategy
+
+import (
+	"context"
+
+	"stock-investing/internal/execution"
+	"stock-investing/internal/models"
+)
+
+// PositionReader 체결로 갱신되는 전략별 보유 포지션 (position.Service 가 구현)
+type PositionReader interface {
+	Positions(ctx context.Context, strategy string) ([]*models.Position, error)
+}
+
+// openPositions 전략의 현재 보유 포지션. Deps.Positions 가 없으면 체결 기록으로 계산한다.
+func openPositions(ctx context.Context, deps Deps, strategy string) ([]*models.Position, error) {
+	if deps.Positions != nil {
+		return deps.Positions.Positions(ctx, strategy)
+	}
+	trades, err := deps.Repo.ListTradesByStrategy(ctx, strategy)
+	if err != nil {
+		return nil, err
+	}
+	return execution.PositionsFromTrades(strategy, trades), nil
+}
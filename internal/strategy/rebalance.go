@@ -0,0 +1,222 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+const stateLastRebalance = "last_rebalance"
+
+// etfHolding 리밸런싱 계산용 ETF 보유 현황
+type etfHolding struct {
+	Code   string
+	Price  float64
+	Qty    int64
+	Value  float64
+	Target float64 // 슬리브 내 목표 비중
+}
+
+// weightAfter plan 대로 매수한 뒤의 슬리브 내 비중
+func (h *etfHolding) weightAfter(all []*etfHolding, plan map[string]int64) float64 {
+	var total float64
+	for _, o := range all {
+		total += o.Value + float64(plan[o.Code])*o.Price
+	}
+	if total <= 0 {
+		return 0
+	}
+	return (h.Value + float64(plan[h.Code])*h.Price) / total
+}
+
+// targetWeights ETF 별 슬리브 내 목표 비중. 설정이 없으면 동일 비중.
+func (s *StableStrategy) targetWeights() map[string]float64 {
+	if len(s.deps.Stable.Weights) > 0 {
+		return s.deps.Stable.Weights
+	}
+	w := map[string]float64{}
+	for _, code := range s.deps.Stable.ETFs {
+		w[code] = 1 / float64(len(s.deps.Stable.ETFs))
+	}
+	return w
+}
+
+// loadHoldings 목표 ETF 와 stable 보유 종목의 현재가/평가금액. 시세 조회 실패 시 에러.
+func (s *StableStrategy) loadHoldings(ctx context.Context) ([]*etfHolding, error) {
+	positions, err := openPositions(ctx, s.deps, "stable")
+	if err != nil {
+		return nil, err
+	}
+	weights := s.targetWeights()
+
+	byCode := map[string]*etfHolding{}
+	for code, w := range weights {
+		byCode[code] = &etfHolding{Code: code, Target: w}
+	}
+	// 목표에서 빠진 ETF 를 보유 중이면 목표 0 으로 두어 정리되게 한다.
+	for _, p := range positions {
+		h, ok := byCode[p.Code]
+		if !ok {
+			h = &etfHolding{Code: p.Code}
+			byCode[p.Code] = h
+		}
+		h.Qty = p.Quantity
+	}
+
+	var out []*etfHolding
+	for _, h := range byCode {
+		price, err := s.deps.Market.GetQuote(ctx, h.Code)
+		if err != nil {
+			return nil, err
+		}
+		h.Price = price
+		h.Value = price * float64(h.Qty)
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out, nil
+}
+
+// rebalanceDue 마지막 리밸런싱 후 RebalanceDays 가 지났거나, 슬리브 안 ETF 비중이 RebalanceDrift 이상
+// 벌어졌거나, stable 이 Alloc 보다 RebalanceDrift 이상 커졌으면 사유를 반환한다.
+// 슬리브가 Alloc 보다 작은 것은 적립 중인 정상 상태라 DCA 로 채운다.
+func (s *StableStrategy) rebalanceDue(ctx context.Context, holdings []*etfHolding, equity float64, now time.Time) (string, bool, error) {
+	cfg := s.deps.Stable
+
+	var sleeve float64
+	for _, h := range holdings {
+		sleeve += h.Value
+	}
+	if sleeve <= 0 {
+		// 아직 적립 전이면 DCA 로 채운다.
+		return "", false, nil
+	}
+
+	if cfg.RebalanceDays > 0 && s.deps.State != nil {
+		v, ok, err := s.deps.State.GetState(ctx, "stable", stateLastRebalance)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			// 기록이 없으면 오늘을 기준일로 삼고 주기는 여기서부터 센다.
+			if err := s.deps.State.SetState(ctx, "stable", stateLastRebalance, now.In(models.KST).Format("2006-01-02")); err != nil {
+				return "", false, err
+			}
+		} else {
+			last, err := time.ParseInLocation("2006-01-02", v, models.KST)
+			if err != nil {
+				return "", false, err
+			}
+			if now.Sub(last) >= time.Duration(cfg.RebalanceDays)*24*time.Hour {
+				return "period elapsed since " + v, true, nil
+			}
+		}
+	}
+
+	if cfg.RebalanceDrift > 0 {
+		for _, h := range holdings {
+			if math.Abs(h.Value/sleeve-h.Target) >= cfg.RebalanceDrift {
+				return "etf drift " + h.Code, true, nil
+			}
+		}
+		if cfg.Alloc > 0 && equity > 0 && sleeve/equity-cfg.Alloc >= cfg.RebalanceDrift {
+			return "sleeve overweight", true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// planRebalance ETF 별 목표 비중으로 되돌리는 주문을 만든다. 슬리브가 Alloc × 평가금액보다 크면 거기까지 줄이고,
+// 작으면 크기는 그대로 두어 (부족분은 적립 매수로 채운다) 매수가 매도 대금을 넘지 않게 한다.
+// 매수는 예수금 + 예상 매도 대금 안에서만 한다. 주문은 적립 매수와 같은 세션(STABLE_DCA_SESSION)으로 낸다. 모든 주문이 전량 체결(또는 상계)되면 마지막 리밸런싱일을 기록한다.
+func (s *StableStrategy) planRebalance(holdings []*etfHolding, bal *models.Balance, reason string, now time.Time) *Plan {
+	cfg := s.deps.Stable
+
+	var sleeve float64
+	for _, h := range holdings {
+		sleeve += h.Value
+	}
+	target := sleeve
+	if cfg.Alloc > 0 {
+		target = math.Min(sleeve, cfg.Alloc*bal.TotalEquity)
+	}
+	logger.Info.Printf("[stable] rebalancing (%s): sleeve=%.0f target=%.0f (%.1f%% of %.0f)\n",
+		reason, sleeve, target, cfg.Alloc*100, bal.TotalEquity)
+
+	type order struct {
+		h   *etfHolding
+		qty int64
+	}
+	var sells, buys []order
+	for _, h := range holdings {
+		if h.Price <= 0 {
+			continue
+		}
+		diff := target*h.Target - h.Value
+		qty := int64(math.Floor(math.Abs(diff) / h.Price))
+		switch {
+		case diff < 0 && qty > 0:
+			if qty > h.Qty {
+				qty = h.Qty
+			}
+			sells = append(sells, order{h, qty})
+		case diff > 0 && qty > 0:
+			buys = append(buys, order{h, qty})
+		}
+	}
+
+	// 매도 의도를 앞에 두고 (실행기도 매도를 먼저 제출한다), 매도 대금을 더한 현금 안에서 매수한다.
+	plan := &Plan{}
+	cash := bal.Cash
+	for _, o := range sells {
+		plan.Intents = append(plan.Intents, models.OrderIntent{
+			Strategy: "stable", Code: o.h.Code, Side: "SELL", Quantity: o.qty, Reason: "rebalance",
+			Session: cfg.DCASession,
+		})
+		cash += float64(o.qty) * o.h.Price
+	}
+	for _, o := range buys {
+		qty := o.qty
+		if max := int64(math.Floor(cash / o.h.Price)); qty > max {
+			qty = max
+		}
+		if qty <= 0 {
+			logger.Info.Printf("[stable] not enough cash to rebalance into %s\n", o.h.Code)
+			continue
+		}
+		cash -= float64(qty) * o.h.Price
+		plan.Intents = append(plan.Intents, models.OrderIntent{
+			Strategy: "stable", Code: o.h.Code, Side: "BUY", Quantity: qty, Reason: "rebalance",
+			Session: cfg.DCASession,
+		})
+	}
+
+	plan.Settle = func(ctx context.Context, results []*models.Order) {
+		pending := false
+		for _, o := range results {
+			switch o.Status {
+			case models.OrderFilled:
+				logger.Info.Printf("[stable] rebalance %s %s x %d @ %.2f\n", o.Intent.Side, o.Intent.Code, o.Quantity, o.Price)
+				if o.Quantity < o.Intent.Quantity {
+					pending = true
+				}
+			case models.OrderNetted:
+			default:
+				logger.Info.Printf("[stable] rebalance %s %s not done (%s): %s\n", o.Intent.Side, o.Intent.Code, o.Status, o.Error)
+				pending = true
+			}
+		}
+		// 전량 체결(또는 상계)되지 않은 주문이 있으면 리밸런싱일을 남기지 않고 다음 실행에서 다시 맞춘다.
+		if pending || s.deps.State == nil {
+			return
+		}
+		if err := s.deps.State.SetState(ctx, "stable", stateLastRebalance, now.In(models.KST).Format("2006-01-02")); err != nil {
+			logger.Error.Printf("[stable] failed to save rebalance date: %v\n", err)
+		}
+	}
+	return plan
+}
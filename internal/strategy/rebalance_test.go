@@ -0,0 +1,140 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+)
+
+// memState 메모리 strategy_state
+type memState map[string]string
+
+func (m memState) GetState(_ context.Context, strategy, key string) (string, bool, error) {
+	v, ok := m[strategy+"/"+key]
+	return v, ok, nil
+}
+
+func (m memState) SetState(_ context.Context, strategy, key, value string) error {
+	m[strategy+"/"+key] = value
+	return nil
+}
+
+func TestRebalanceDue(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, models.KST)
+	even := func(a, b float64) []*etfHolding {
+		return []*etfHolding{{Code: "A", Value: a, Target: 0.5}, {Code: "B", Value: b, Target: 0.5}}
+	}
+
+	tests := []struct {
+		name     string
+		holdings []*etfHolding
+		equity   float64
+		last     string // 마지막 리밸런싱일 ("" = 기록 없음)
+		due      bool
+		reason   string
+	}{
+		{name: "nothing bought yet", holdings: even(0, 0), equity: 1000},
+		{name: "first run records the date", holdings: even(350, 350), equity: 1000},
+		{name: "within period and drift", holdings: even(360, 340), equity: 1000, last: "2025-05-01"},
+		{name: "period elapsed", holdings: even(350, 350), equity: 1000, last: "2025-03-01",
+			due: true, reason: "period elapsed since 2025-03-01"},
+		{name: "etf drift", holdings: even(420, 280), equity: 1000, last: "2025-05-01",
+			due: true, reason: "etf drift A"},
+		{name: "sleeve overweight", holdings: even(400, 400), equity: 1000, last: "2025-05-01",
+			due: true, reason: "sleeve overweight"},
+		// 적립 중인 슬리브는 DCA 로 채우고 리밸런싱으로 한 번에 사지 않는다.
+		{name: "sleeve underweight", holdings: even(100, 100), equity: 1000, last: "2025-05-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := memState{}
+			if tt.last != "" {
+				state["stable/"+stateLastRebalance] = tt.last
+			}
+			s := NewStableStrategy(Deps{
+				State:  state,
+				Stable: StableConfig{Alloc: 0.7, RebalanceDays: 90, RebalanceDrift: 0.05},
+			})
+
+			reason, due, err := s.rebalanceDue(context.Background(), tt.holdings, tt.equity, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if due != tt.due || reason != tt.reason {
+				t.Errorf("rebalanceDue = %q %v, want %q %v", reason, due, tt.reason, tt.due)
+			}
+			if tt.last == "" && tt.holdings[0].Value > 0 && state["stable/"+stateLastRebalance] != "2025-06-02" {
+				t.Errorf("last rebalance = %q, want today", state["stable/"+stateLastRebalance])
+			}
+		})
+	}
+}
+
+func TestPlanRebalanceSettle(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, models.KST)
+	order := func(side string, want, got int64, status string) *models.Order {
+		return &models.Order{
+			Intent:   models.OrderIntent{Strategy: "stable", Code: "A", Side: side, Quantity: want},
+			Quantity: got, Price: 100, Status: status,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		results  []*models.Order
+		recorded bool
+	}{
+		{name: "all filled", results: []*models.Order{order("SELL", 3, 3, models.OrderFilled), order("BUY", 2, 2, models.OrderFilled)}, recorded: true},
+		{name: "netted counts as done", results: []*models.Order{order("SELL", 3, 3, models.OrderNetted), order("BUY", 2, 2, models.OrderFilled)}, recorded: true},
+		{name: "partial fill stays pending", results: []*models.Order{order("SELL", 3, 1, models.OrderFilled)}},
+		{name: "rejected stays pending", results: []*models.Order{order("SELL", 3, 3, models.OrderFilled), order("BUY", 2, 0, models.OrderRejected)}},
+		{name: "failed stays pending", results: []*models.Order{order("BUY", 2, 0, models.OrderFailed)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := memState{"stable/" + stateLastRebalance: "2025-01-02"}
+			s := NewStableStrategy(Deps{State: state, Stable: StableConfig{Alloc: 0.8}})
+			holdings := []*etfHolding{
+				{Code: "A", Price: 100, Qty: 6, Value: 600, Target: 0.5},
+				{Code: "B", Price: 100, Qty: 2, Value: 200, Target: 0.5},
+			}
+
+			plan := s.planRebalance(holdings, &models.Balance{Cash: 0, TotalEquity: 1000}, "test", now)
+			if len(plan.Intents) != 2 || plan.Intents[0].Side != "SELL" || plan.Intents[0].Quantity != 2 ||
+				plan.Intents[1].Side != "BUY" || plan.Intents[1].Quantity != 2 {
+				t.Fatalf("intents = %+v, want SELL A 2 then BUY B 2", plan.Intents)
+			}
+			plan.Settle(context.Background(), tt.results)
+
+			want := "2025-01-02"
+			if tt.recorded {
+				want = "2025-06-02"
+			}
+			if got := state["stable/"+stateLastRebalance]; got != want {
+				t.Errorf("last rebalance = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestPlanRebalanceSession(t *testing.T) {
+	// 적립 매수와 같은 실행에서 나가므로 리밸런싱도 STABLE_DCA_SESSION 세션으로 낸다.
+	s := NewStableStrategy(Deps{Stable: StableConfig{Alloc: 0.8, DCASession: models.SessionAfterHours}})
+	holdings := []*etfHolding{
+		{Code: "A", Price: 100, Qty: 6, Value: 600, Target: 0.5},
+		{Code: "B", Price: 100, Qty: 2, Value: 200, Target: 0.5},
+	}
+	plan := s.planRebalance(holdings, &models.Balance{TotalEquity: 1000}, "test", time.Now())
+	if len(plan.Intents) != 2 {
+		t.Fatalf("intents = %+v", plan.Intents)
+	}
+	for _, in := range plan.Intents {
+		if in.Session != models.SessionAfterHours {
+			t.Errorf("%s %s session = %q, want %q", in.Side, in.Code, in.Session, models.SessionAfterHours)
+		}
+	}
+}
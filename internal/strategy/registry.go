@@ -0,0 +1,82 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ConfigField 전략이 읽는 설정 항목 (환경변수) 설명
+type ConfigField struct {
+	Key         string
+	Default     string
+	Description string
+}
+
+// Definition 레지스트리에 등록되는 전략 정의
+type Definition struct {
+	Name        string
+	Description string
+	Config      []ConfigField
+	New         func(deps Deps) (Runner, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Definition{}
+)
+
+// Register 전략을 이름으로 등록한다. 보통 각 전략 파일의 init() 에서 호출한다.
+// 같은 이름이 두 번 등록되면 panic.
+func Register(def Definition) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if def.Name == "" || def.New == nil {
+		panic("strategy: Register requires Name and New")
+	}
+	if _, dup := registry[def.Name]; dup {
+		panic("strategy: duplicate registration of " + def.Name)
+	}
+	registry[def.Name] = def
+}
+
+// Lookup 등록된 전략 정의
+func Lookup(name string) (Definition, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	def, ok := registry[name]
+	return def, ok
+}
+
+// New 이름으로 전략을 생성한다.
+func New(name string, deps Deps) (Runner, error) {
+	def, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q (registered: %s)", name, strings.Join(Names(), ", "))
+	}
+	return def.New(deps)
+}
+
+// Names 등록된 전략 이름 (정렬)
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	out := make([]string, 0, len(registry))
+	for name := range registry {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Definitions 등록된 전략 정의 (이름순)
+func Definitions() []Definition {
+	var out []Definition
+	for _, name := range Names() {
+		def, _ := Lookup(name)
+		out = append(out, def)
+	}
+	return out
+}
@@ -0,0 +1,270 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/pkg/logger"
+)
+
+func init() {
+	Register(Definition{
+		Name:        string(ModeRotation),
+		Description: "ETF 유니버스 3/6/12개월 모멘텀 순위 상위 K 보유 + 절대 모멘텀 필터, 정기 교체",
+		Config: []ConfigField{
+			{Key: "ROTATION_ALLOC", Default: "0", Description: "전체 평가금액 중 비중 (0 이면 hybrid 제외)"},
+			{Key: "ROTATION_UNIVERSE", Default: "069500,360750,148070,132030", Description: "후보 ETF (국내지수, 미국지수, 채권, 금)"},
+			{Key: "ROTATION_LOOKBACKS", Default: "3,6,12", Description: "모멘텀 기간 (개월, 수익률 평균)"},
+			{Key: "ROTATION_TOP_K", Default: "2", Description: "보유 ETF 수"},
+			{Key: "ROTATION_SAFE_ASSET", Default: "153130", Description: "절대 모멘텀 탈락 슬롯을 옮길 ETF (cash 면 현금)"},
+			{Key: "ROTATION_ABS_THRESHOLD", Default: "0", Description: "모멘텀 점수가 이 값 이하면 탈락"},
+			{Key: "ROTATION_REBALANCE_MONTHS", Default: "1", Description: "교체 주기 (개월)"},
+			{Key: "ROTATION_BAND", Default: "0.05", Description: "슬롯 금액 대비 이 비율 미만 차이는 거래하지 않음"},
+		},
+		New: func(deps Deps) (Runner, error) { return NewRotationStrategy(deps), nil },
+	})
+}
+
+const stateLastRotation = "last_rotation"
+
+// RotationStrategy 크로스섹셔널 모멘텀 로테이션
+type RotationStrategy struct {
+	deps Deps
+}
+
+func NewRotationStrategy(deps Deps) *RotationStrategy {
+	return &RotationStrategy{deps: deps}
+}
+
+func (s *RotationStrategy) Name() string { return string(ModeRotation) }
+
+func (s *RotationStrategy) Run(ctx context.Context) error {
+	logger.Info.Println("[momentum_rotation] running momentum rotation")
+	if err := runSleeve(ctx, s.deps, s); err != nil {
+		return err
+	}
+	logger.Info.Println("[momentum_rotation] momentum rotation completed")
+	return nil
+}
+
+// momentumScore ETF 별 모멘텀 점수 (기간별 수익률 평균)
+type momentumScore struct {
+	Code  string
+	Score float64
+}
+
+// Plan 교체일이면 상위 K 개 (절대 모멘텀 탈락 슬롯은 안전자산/현금) 를 슬롯당 같은 금액으로 맞추는 주문.
+// 교체일이 아니면 빈 계획. 손실 한도에 걸리면 매도만 낸다.
+func (s *RotationStrategy) Plan(ctx context.Context, bal *models.Balance) (*Plan, error) {
+	const name = string(ModeRotation)
+	cfg := s.deps.Rotation
+	now := time.Now()
+
+	if cfg.Alloc <= 0 || cfg.TopK <= 0 {
+		logger.Info.Println("[momentum_rotation] ROTATION_ALLOC/ROTATION_TOP_K not set, skipping")
+		return &Plan{}, nil
+	}
+	due, last, err := s.rotationDue(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	if !due {
+		logger.Info.Printf("[momentum_rotation] not due (last rotation %s)\n", last)
+		return &Plan{}, nil
+	}
+
+	// 1) 모멘텀 순위
+	var scores []momentumScore
+	for _, code := range cfg.Universe {
+		score, err := s.momentum(ctx, code, now)
+		if err != nil {
+			logger.Error.Printf("[momentum_rotation] momentum unavailable for %s: %v\n", code, err)
+			continue
+		}
+		scores = append(scores, momentumScore{Code: code, Score: score})
+	}
+	if len(scores) == 0 {
+		return nil, fmt.Errorf("no momentum scores for universe %v", cfg.Universe)
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Score > scores[j].Score })
+	for i, sc := range scores {
+		logger.Info.Printf("[momentum_rotation] rank %d %s score=%.2f%%\n", i+1, sc.Code, sc.Score*100)
+	}
+
+	// 2) 상위 K 슬롯 배정 (절대 모멘텀 탈락 → 안전자산, 없으면 현금)
+	slot := cfg.Alloc * bal.TotalEquity / float64(cfg.TopK)
+	targets := map[string]float64{}
+	for i := 0; i < cfg.TopK && i < len(scores); i++ {
+		sc := scores[i]
+		switch {
+		case sc.Score > cfg.AbsThreshold:
+			targets[sc.Code] += slot
+		case cfg.SafeAsset != "":
+			logger.Info.Printf("[momentum_rotation] %s failed absolute momentum, slot -> %s\n", sc.Code, cfg.SafeAsset)
+			targets[cfg.SafeAsset] += slot
+		default:
+			logger.Info.Printf("[momentum_rotation] %s failed absolute momentum, slot -> cash\n", sc.Code)
+		}
+	}
+
+	// 3) 현재 보유와 비교해 매도 → 매수
+	positions, err := openPositions(ctx, s.deps, name)
+	if err != nil {
+		return nil, err
+	}
+	held := map[string]int64{}
+	for _, p := range positions {
+		held[p.Code] = p.Quantity
+	}
+	codes := map[string]bool{}
+	for code := range targets {
+		codes[code] = true
+	}
+	for code := range held {
+		codes[code] = true
+	}
+
+	type order struct {
+		code  string
+		qty   int64
+		price float64
+	}
+	var sells, buys []order
+	band := cfg.Band * slot
+	unpriced := false // 시세를 못 받아 맞추지 못한 종목이 있으면 교체를 끝내지 않은 것으로 둔다
+	for code := range codes {
+		price, err := s.deps.Market.GetQuote(ctx, code)
+		if err != nil || price <= 0 {
+			logger.Error.Printf("[momentum_rotation] failed to get quote for %s: %v\n", code, err)
+			unpriced = true
+			continue
+		}
+		value := price * float64(held[code])
+		diff := targets[code] - value
+		logger.Info.Printf("[momentum_rotation] %s held=%d value=%.0f target=%.0f\n", code, held[code], value, targets[code])
+		if math.Abs(diff) < band && targets[code] > 0 {
+			continue
+		}
+		qty := int64(math.Floor(math.Abs(diff) / price))
+		switch {
+		case targets[code] == 0 && held[code] > 0:
+			sells = append(sells, order{code, held[code], price})
+		case diff < 0 && qty > 0:
+			sells = append(sells, order{code, min(qty, held[code]), price})
+		case diff > 0 && qty > 0:
+			buys = append(buys, order{code, qty, price})
+		}
+	}
+	sort.Slice(sells, func(i, j int) bool { return sells[i].code < sells[j].code })
+	sort.Slice(buys, func(i, j int) bool { return buys[i].code < buys[j].code })
+
+	plan := &Plan{}
+	cash := bal.Cash
+	for _, o := range sells {
+		plan.Intents = append(plan.Intents, models.OrderIntent{
+			Strategy: name, Code: o.code, Side: "SELL", Quantity: o.qty, Reason: "rotation",
+		})
+		cash += float64(o.qty) * o.price
+	}
+
+	// 손실 한도에 걸리면 매도만 낸다
+	if halt, err := checkMaxLoss(ctx, s.deps, name, bal.TotalEquity); halt {
+		return plan, err
+	}
+	for _, o := range buys {
+		qty := min(o.qty, int64(math.Floor(cash/o.price)))
+		if qty <= 0 {
+			logger.Info.Printf("[momentum_rotation] not enough cash to rotate into %s\n", o.code)
+			continue
+		}
+		cash -= float64(qty) * o.price
+		plan.Intents = append(plan.Intents, models.OrderIntent{
+			Strategy: name, Code: o.code, Side: "BUY", Quantity: qty, Reason: "rotation",
+		})
+	}
+
+	plan.Settle = func(ctx context.Context, results []*models.Order) {
+		pending := unpriced
+		for _, o := range results {
+			switch o.Status {
+			case models.OrderFilled:
+				logger.Info.Printf("[momentum_rotation] %s %s x %d @ %.2f\n", o.Intent.Side, o.Intent.Code, o.Quantity, o.Price)
+				if o.Quantity < o.Intent.Quantity {
+					pending = true
+				}
+			case models.OrderNetted:
+			default:
+				// 실패/거부 (현금 하한, 리스크 체크 등)
+				logger.Info.Printf("[momentum_rotation] %s %s not done (%s): %s\n", o.Intent.Side, o.Intent.Code, o.Status, o.Error)
+				pending = true
+			}
+		}
+		// 전량 체결(또는 상계)되지 않은 주문이나 시세를 못 받은 종목이 있으면 교체 월을 남기지 않고 다음 실행에서 다시 맞춘다.
+		if pending || s.deps.State == nil {
+			return
+		}
+		if err := s.deps.State.SetState(ctx, name, stateLastRotation, now.In(models.KST).Format("2006-01")); err != nil {
+			logger.Error.Printf("[momentum_rotation] failed to save rotation month: %v\n", err)
+		}
+	}
+	return plan, nil
+}
+
+// rotationDue 마지막 교체 월로부터 RebalanceMonths 가 지났는지 (기록이 없으면 바로 교체).
+func (s *RotationStrategy) rotationDue(ctx context.Context, now time.Time) (bool, string, error) {
+	if s.deps.State == nil {
+		return true, "", nil
+	}
+	v, ok, err := s.deps.State.GetState(ctx, string(ModeRotation), stateLastRotation)
+	if err != nil || !ok {
+		return err == nil, v, err
+	}
+	last, err := time.ParseInLocation("2006-01", v, models.KST)
+	if err != nil {
+		return false, v, err
+	}
+	cur := now.In(models.KST)
+	months := (cur.Year()-last.Year())*12 + int(cur.Month()-last.Month())
+	return months >= max(s.deps.Rotation.RebalanceMonths, 1), v, nil
+}
+
+// momentum 기간별 (개월) 수익률의 평균. 기준가는 now 에서 m 개월 전 이전의 마지막 종가.
+func (s *RotationStrategy) momentum(ctx context.Context, code string, now time.Time) (float64, error) {
+	lookbacks := s.deps.Rotation.Lookbacks
+	if len(lookbacks) == 0 {
+		return 0, fmt.Errorf("no lookbacks")
+	}
+	longest := 0
+	for _, m := range lookbacks {
+		longest = max(longest, m)
+	}
+	candles, err := s.deps.Market.GetDailyCandles(ctx, code, now.AddDate(0, -longest, -10), now)
+	if err != nil {
+		return 0, err
+	}
+	if len(candles) == 0 {
+		return 0, fmt.Errorf("no candles")
+	}
+	last := candles[len(candles)-1].Close
+
+	var sum float64
+	for _, m := range lookbacks {
+		at := now.AddDate(0, -m, 0)
+		base := 0.0
+		for _, c := range candles {
+			if c.Date.After(at) {
+				break
+			}
+			base = c.Close
+		}
+		if base <= 0 {
+			return 0, fmt.Errorf("not enough history for %d months", m)
+		}
+		sum += last/base - 1
+	}
+	return sum / float64(len(lookbacks)), nil
+}
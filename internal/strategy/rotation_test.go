@@ -0,0 +1,160 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/risk"
+)
+
+// rotationDeps 1개월 모멘텀이 scores 가 되도록 일봉을 만든다 (기준가 100).
+func rotationDeps(scores, quotes map[string]float64, held map[string]int64, state memState, lossErr error) Deps {
+	now := time.Now()
+	candles := map[string][]models.Candle{}
+	for code, sc := range scores {
+		candles[code] = []models.Candle{
+			{Code: code, Date: now.AddDate(0, 0, -40), Close: 100},
+			{Code: code, Date: now, Close: 100 * (1 + sc)},
+		}
+	}
+	var positions []*models.Position
+	for code, qty := range held {
+		positions = append(positions, &models.Position{Strategy: string(ModeRotation), Code: code, Quantity: qty, AvgPrice: 100})
+	}
+	return Deps{
+		Market:    &fakeMarket{quotes: quotes, candles: candles},
+		Risk:      fakeRisk{lossErr: lossErr},
+		Positions: fakePositions{string(ModeRotation): positions},
+		State:     state,
+		Rotation: RotationConfig{
+			Alloc: 0.5, Universe: []string{"A", "B", "C"}, Lookbacks: []int{1}, TopK: 2,
+			SafeAsset: "SAFE", RebalanceMonths: 1, Band: 0.05,
+		},
+	}
+}
+
+func intentList(intents []models.OrderIntent) string {
+	var out []string
+	for _, in := range intents {
+		out = append(out, fmt.Sprintf("%s %s %d", in.Side, in.Code, in.Quantity))
+	}
+	return strings.Join(out, ", ")
+}
+
+func TestRotationPlan(t *testing.T) {
+	thisMonth := time.Now().In(models.KST).Format("2006-01")
+	lastMonth := time.Now().In(models.KST).AddDate(0, -1, -5).Format("2006-01")
+	scores := map[string]float64{"A": 0.2, "B": 0.1, "C": -0.05}
+	quotes := map[string]float64{"A": 100, "B": 50, "C": 100, "SAFE": 100}
+
+	tests := []struct {
+		name     string
+		scores   map[string]float64
+		quotes   map[string]float64
+		held     map[string]int64
+		last     string // 마지막 교체 월 ("" = 기록 없음)
+		lossErr  error
+		intents  string
+		recorded bool // 전부 체결됐을 때 교체 월을 남기는지
+	}{
+		// 슬롯 250,000: C 전량 매도, A 200,000 → 250,000, B 신규
+		{name: "rotate into the top two", scores: scores, quotes: quotes, held: map[string]int64{"A": 2000, "C": 1000},
+			last: lastMonth, intents: "SELL C 1000, BUY A 500, BUY B 5000", recorded: true},
+		{name: "not due this month", scores: scores, quotes: quotes, held: map[string]int64{"C": 1000}, last: thisMonth},
+		{name: "within the band", scores: scores, quotes: quotes, held: map[string]int64{"A": 2450, "B": 4950},
+			recorded: true},
+		{name: "failed absolute momentum goes to the safe asset", scores: map[string]float64{"A": 0.2, "B": -0.1, "C": -0.2},
+			quotes: quotes, intents: "BUY A 2500, BUY SAFE 1500", recorded: true},
+		// 보유 종목 C 의 시세가 없으면 매도하지 못했으므로 교체 월을 남기지 않는다.
+		{name: "unpriced holding stays pending", scores: scores, quotes: map[string]float64{"A": 100, "B": 50},
+			held: map[string]int64{"A": 2000, "C": 1000}, intents: "BUY A 500, BUY B 5000"},
+		{name: "loss limit sells only", scores: scores, quotes: quotes, held: map[string]int64{"A": 2000, "C": 1000},
+			lossErr: risk.ErrLossLimit, intents: "SELL C 1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := memState{}
+			if tt.last != "" {
+				state[string(ModeRotation)+"/"+stateLastRotation] = tt.last
+			}
+			s := NewRotationStrategy(rotationDeps(tt.scores, tt.quotes, tt.held, state, tt.lossErr))
+
+			plan, err := s.Plan(context.Background(), &models.Balance{Cash: 400_000, TotalEquity: 1_000_000})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := intentList(plan.Intents); got != tt.intents {
+				t.Errorf("intents = %s, want %s", got, tt.intents)
+			}
+			if plan.Settle == nil {
+				return
+			}
+
+			var results []*models.Order
+			for _, in := range plan.Intents {
+				results = append(results, &models.Order{Intent: in, Quantity: in.Quantity, Price: 100, Status: models.OrderFilled})
+			}
+			plan.Settle(context.Background(), results)
+			recorded := state[string(ModeRotation)+"/"+stateLastRotation] == thisMonth
+			if recorded != tt.recorded {
+				t.Errorf("rotation month recorded = %v, want %v", recorded, tt.recorded)
+			}
+		})
+	}
+}
+
+func TestRotationSettle(t *testing.T) {
+	thisMonth := time.Now().In(models.KST).Format("2006-01")
+	quotes := map[string]float64{"A": 100, "B": 50, "C": 100, "SAFE": 100}
+	scores := map[string]float64{"A": 0.2, "B": 0.1, "C": -0.05}
+
+	tests := []struct {
+		name     string
+		status   []string // SELL C, BUY A, BUY B 순서
+		partial  bool     // 마지막 매수가 일부만 체결
+		recorded bool
+	}{
+		{name: "all filled", status: []string{models.OrderFilled, models.OrderFilled, models.OrderFilled}, recorded: true},
+		{name: "netted counts as done", status: []string{models.OrderNetted, models.OrderFilled, models.OrderFilled}, recorded: true},
+		{name: "partial fill", status: []string{models.OrderFilled, models.OrderFilled, models.OrderFilled}, partial: true},
+		{name: "rejected by the cash floor", status: []string{models.OrderFilled, models.OrderFilled, models.OrderRejected}},
+		{name: "failed sell", status: []string{models.OrderFailed, models.OrderFilled, models.OrderFilled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := memState{}
+			s := NewRotationStrategy(rotationDeps(scores, quotes, map[string]int64{"A": 2000, "C": 1000}, state, nil))
+			plan, err := s.Plan(context.Background(), &models.Balance{Cash: 400_000, TotalEquity: 1_000_000})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.Intents) != len(tt.status) {
+				t.Fatalf("intents = %s", intentList(plan.Intents))
+			}
+
+			var results []*models.Order
+			for i, in := range plan.Intents {
+				o := &models.Order{Intent: in, Quantity: in.Quantity, Price: 100, Status: tt.status[i]}
+				if tt.status[i] != models.OrderFilled && tt.status[i] != models.OrderNetted {
+					o.Quantity = 0
+				}
+				results = append(results, o)
+			}
+			if tt.partial {
+				results[len(results)-1].Quantity--
+			}
+			plan.Settle(context.Background(), results)
+
+			recorded := state[string(ModeRotation)+"/"+stateLastRotation] == thisMonth
+			if recorded != tt.recorded {
+				t.Errorf("rotation month recorded = %v, want %v", recorded, tt.recorded)
+			}
+		})
+	}
+}
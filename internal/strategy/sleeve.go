@@ -0,0 +1,140 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"stock-investing/internal/models"
+	"stock-investing/internal/risk"
+	"stock-investing/pkg/logger"
+)
+
+// Sleeve 자본을 배분받아 주문 의도만 만드는 전략. 실행은 호출 측(단독 실행 또는 hybrid)이 모아서 한다.
+type Sleeve interface {
+	Name() string
+	// Plan bal 기준으로 주문 의도를 만든다. 일부만 계획된 경우 plan 과 err 를 함께 반환할 수 있다.
+	Plan(ctx context.Context, bal *models.Balance) (*Plan, error)
+}
+
+// Plan 한 슬리브의 주문 의도와 실행 결과 후처리
+type Plan struct {
+	Intents []models.OrderIntent
+	// Settle 실행 결과 (Intents 와 같은 순서). nil 이면 후처리 없음.
+	Settle func(ctx context.Context, results []*models.Order)
+}
+
+// runSleeve 슬리브 하나를 단독으로 실행한다: 잔고 → 계획 → 최소 현금 → 실행 → 후처리.
+func runSleeve(ctx context.Context, deps Deps, sl Sleeve) error {
+	tag := "[" + sl.Name() + "]"
+	bal, err := deps.KIS.GetBalance(ctx)
+	if err != nil {
+		logger.Error.Printf("%s failed to get balance: %v\n", tag, err)
+		return err
+	}
+
+	plan, planErr := sl.Plan(ctx, bal)
+	if plan != nil {
+		results := executeWithCashFloor(ctx, deps, bal, plan.Intents)
+		if plan.Settle != nil {
+			plan.Settle(ctx, results)
+		}
+	}
+	return planErr
+}
+
+// checkMaxLoss 당일 손실/낙폭 한도를 확인한다. halt 이면 신규 매수를 내지 않는다 (청산은 계속).
+// 한도 초과는 예정된 매수 중단이라 err 없이 halt 만, 한도 확인 자체가 실패하면 err 와 함께 halt.
+func checkMaxLoss(ctx context.Context, deps Deps, name string, equity float64) (halt bool, err error) {
+	err = deps.Risk.CheckMaxLoss(ctx, equity)
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.Is(err, risk.ErrLossLimit):
+		logger.Info.Printf("[%s] buying halted: %v\n", name, err)
+		return true, nil
+	default:
+		logger.Error.Printf("[%s] max loss check failed, buying halted: %v\n", name, err)
+		return true, err
+	}
+}
+
+// executeWithCashFloor 최소 현금 비율을 지키도록 뒤쪽 매수부터 빼고 실행한다.
+// 결과는 intents 와 같은 순서이며, 뺀 매수는 rejected 로 채운다.
+func executeWithCashFloor(ctx context.Context, deps Deps, bal *models.Balance, intents []models.OrderIntent) []*models.Order {
+	keep := enforceMinCash(ctx, deps, bal, intents)
+
+	var kept []models.OrderIntent
+	for i, in := range intents {
+		if keep[i] {
+			kept = append(kept, in)
+		}
+	}
+	executed := deps.Exec.Execute(ctx, bal.TotalEquity, kept)
+
+	out := make([]*models.Order, len(intents))
+	j := 0
+	for i, in := range intents {
+		if keep[i] {
+			out[i] = executed[j]
+			j++
+			continue
+		}
+		out[i] = &models.Order{Intent: in, Status: models.OrderRejected, Error: "min cash ratio"}
+	}
+	return out
+}
+
+// enforceMinCash 예상 현금(예수금 + 매도 − 매수) 이 risk.CheckCashRatio 를 통과할 때까지
+// 마지막 매수 의도부터 제외한다. 반환값은 실행할 의도 여부.
+func enforceMinCash(ctx context.Context, deps Deps, bal *models.Balance, intents []models.OrderIntent) []bool {
+	keep := make([]bool, len(intents))
+	value := make([]float64, len(intents))
+	cash := bal.Cash
+	for i, in := range intents {
+		keep[i] = true
+		v, err := estimateValue(ctx, deps, in)
+		if err != nil {
+			// 값을 모르면 여기서는 판단하지 않고 실행기에 맡긴다.
+			continue
+		}
+		value[i] = v
+		if in.Side == "SELL" {
+			cash += v
+		} else {
+			cash -= v
+		}
+	}
+
+	for i := len(intents) - 1; i >= 0; i-- {
+		err := deps.Risk.CheckCashRatio(ctx, bal.TotalEquity, cash)
+		if err == nil {
+			break
+		}
+		if intents[i].Side != "BUY" {
+			continue
+		}
+		keep[i] = false
+		cash += value[i]
+		logger.Info.Printf("[%s] dropped BUY %s (%.0f): %v\n", intents[i].Strategy, intents[i].Code, value[i], err)
+	}
+	return keep
+}
+
+func estimateValue(ctx context.Context, deps Deps, in models.OrderIntent) (float64, error) {
+	if in.Quantity <= 0 {
+		return in.TargetValue, nil
+	}
+	price := in.LimitPrice
+	if price <= 0 {
+		p, err := deps.Market.GetQuote(ctx, in.Code)
+		if err != nil {
+			return 0, err
+		}
+		price = p
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid price for %s", in.Code)
+	}
+	return price * float64(in.Quantity), nil
+}
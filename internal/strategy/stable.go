@@ -3,12 +3,32 @@ package strategy
 import (
 	"context"
 	"math"
+	"sort"
 	"time"
 
 	"stock-investing/internal/models"
 	"stock-investing/pkg/logger"
 )
 
+func init() {
+	Register(Definition{
+		Name:        string(ModeStable),
+		Description: "ETF 적립식 매수 (DCA) + 정기/이탈 리밸런싱",
+		Config: []ConfigField{
+			{Key: "STABLE_ETFS", Default: "069500,360750", Description: "적립 대상 ETF"},
+			{Key: "STABLE_WEIGHTS", Default: "동일 비중", Description: "ETF 별 목표 비중 (CODE:WEIGHT,...)"},
+			{Key: "STABLE_DAILY_AMOUNT", Default: "70000", Description: "하루 적립 금액 (원)"},
+			{Key: "STABLE_ALLOC", Default: "0.56", Description: "전체 평가금액 중 stable 비중"},
+			{Key: "REBALANCE_PERIOD", Default: "90", Description: "정기 리밸런싱 주기 (일)"},
+			{Key: "REBALANCE_DRIFT", Default: "0.05", Description: "비중 이탈 허용치"},
+			{Key: "STABLE_DCA_MODE", Default: "fixed", Description: "fixed | value_averaging | dip"},
+			{Key: "STABLE_DCA_SESSION", Default: "regular", Description: "적립/리밸런싱 주문 세션: regular | opening_auction | pre_market | closing_auction | after_hours | after_hours_single"},
+			{Key: "STABLE_REINVEST_DIVIDENDS", Default: "true", Description: "입금된 배당/분배금을 적립 예산에 더함"},
+		},
+		New: func(deps Deps) (Runner, error) { return NewStableStrategy(deps), nil },
+	})
+}
+
 type StableStrategy struct {
 	deps Deps
 }
@@ -17,82 +37,206 @@ func NewStableStrategy(deps Deps) *StableStrategy {
 	return &StableStrategy{deps: deps}
 }
 
+func (s *StableStrategy) Name() string { return string(ModeStable) }
+
 func (s *StableStrategy) Run(ctx context.Context) error {
 	logger.Info.Println("[stable] running DCA ETF strategy")
+	if err := runSleeve(ctx, s.deps, s); err != nil {
+		return err
+	}
+	logger.Info.Println("[stable] DCA ETF strategy completed")
+	return nil
+}
 
-	// 0) 간단한 equity 가정 (TODO: 실제 계좌 평가액으로 대체)
-	equity := 10_000_000.0
+// Plan 리밸런싱이 필요하면 리밸런싱 주문을, 아니면 적립 매수 주문을 만든다.
+func (s *StableStrategy) Plan(ctx context.Context, bal *models.Balance) (*Plan, error) {
+	equity := bal.TotalEquity
 
-	// 0-1) 최대 손실 한도 체크
-	if err := s.deps.Risk.CheckMaxLoss(ctx, equity); err != nil {
-		logger.Error.Printf("[stable] max loss check failed: %v\n", err)
-		return err
+	// 0) 손실 한도. 리밸런싱도 매수를 포함하므로 한도에 걸린 날은 아무 주문도 내지 않는다.
+	if halt, err := checkMaxLoss(ctx, s.deps, "stable", equity); halt {
+		return nil, err
 	}
 
-	// 각 ETF에 대해 동일 금액으로 분할 매수 (간단 버전)
-	if len(s.deps.Stable.ETFs) == 0 || s.deps.Stable.DailyAmount <= 0 {
-		logger.Info.Println("[stable] no ETFs or DailyAmount configured, skipping")
-		return nil
+	if len(s.deps.Stable.ETFs) == 0 {
+		logger.Info.Println("[stable] no ETFs configured, skipping")
+		return nil, nil
 	}
 
-	allocPerETF := float64(s.deps.Stable.DailyAmount) / float64(len(s.deps.Stable.ETFs))
+	// 0-1) 정기/이탈 리밸런싱. 리밸런싱하는 날은 적립 매수를 건너뛴다.
+	now := time.Now()
+	holdings, err := s.loadHoldings(ctx)
+	if err != nil {
+		logger.Error.Printf("[stable] failed to load holdings, skipping rebalance check: %v\n", err)
+	} else {
+		reason, due, err := s.rebalanceDue(ctx, holdings, equity, now)
+		if err != nil {
+			logger.Error.Printf("[stable] rebalance check failed: %v\n", err)
+		} else if due {
+			logger.Info.Println("[stable] rebalancing, skipping DCA today")
+			return s.planRebalance(holdings, bal, reason, now), nil
+		}
+	}
 
-	for _, code := range s.deps.Stable.ETFs {
-		select {
-		case <-ctx.Done():
-			logger.Info.Println("[stable] context canceled, aborting")
-			return ctx.Err()
-		default:
+	// 1) 적립 매수: 목표 비중 대비 부족한 ETF 에 먼저 배정한다.
+	if s.deps.Stable.DailyAmount <= 0 {
+		logger.Info.Println("[stable] no DailyAmount configured, skipping")
+		return nil, nil
+	}
+	if holdings == nil {
+		logger.Info.Println("[stable] holdings unavailable, skipping DCA")
+		return nil, nil
+	}
+
+	// ETF 별 배정액 + 이월 금액으로 살 수 있는 만큼만 사고, 남은 금액은 다음 날로 넘긴다.
+	carry := map[string]float64{}
+	if s.deps.Carry != nil {
+		if carry, err = s.deps.Carry.ListCarry(ctx, "stable"); err != nil {
+			logger.Error.Printf("[stable] failed to load carry-over: %v\n", err)
+			return nil, err
 		}
+	}
+	alloc, err := s.dailyAllocation(ctx, holdings, carry, now)
+	if err != nil {
+		logger.Error.Printf("[stable] failed to decide DCA amount: %v\n", err)
+		return nil, err
+	}
 
-		// 1) 현재가 조회 (KIS stub)
-		price, err := s.deps.KIS.GetQuote(ctx, code)
-		if err != nil {
-			logger.Error.Printf("[stable] failed to get quote for %s: %v\n", code, err)
+	// 입금된 배당은 목표 비중 대비 부족분에 나눠 그날 예산에 더한다.
+	reinvest, divIDs := s.pendingDividends(ctx, holdings)
+
+	// ETF 별 예산(배정 + 이월 + 배당)으로 살 수 있는 만큼 매수 의도를 만든다.
+	plan := &Plan{}
+	budgets := map[string]float64{}
+	for _, h := range holdings {
+		budget := carry[h.Code] + alloc[h.Code] + reinvest[h.Code]
+		if budget <= 0 {
 			continue
 		}
-		if price <= 0 {
-			logger.Error.Printf("[stable] invalid price for %s: %.2f\n", code, price)
+		budgets[h.Code] = budget
+		if h.Price <= 0 || budget < h.Price {
 			continue
 		}
+		plan.Intents = append(plan.Intents, models.OrderIntent{
+			Strategy:    "stable",
+			Code:        h.Code,
+			Side:        "BUY",
+			Quantity:    int64(math.Floor(budget / h.Price)),
+			TargetValue: budget,
+			Reason:      "dca:" + dcaMode(s.deps.Stable.DCA.Mode),
+			Session:     s.deps.Stable.DCASession,
+		})
+	}
 
-		// 2) 수량 계산
-		qty := int64(math.Floor(allocPerETF / price))
-		if qty <= 0 {
-			logger.Info.Printf("[stable] amount too small for %s (price=%.2f, alloc=%.2f)\n", code, price, allocPerETF)
-			continue
+	// 2) 체결 결과로 이월 금액 갱신
+	plan.Settle = func(ctx context.Context, results []*models.Order) {
+		bought := map[string]int64{}
+		spent := map[string]float64{}
+		for _, o := range results {
+			if o.Status == models.OrderFilled {
+				bought[o.Intent.Code] += o.Quantity
+				spent[o.Intent.Code] += float64(o.Quantity) * o.Price
+			}
 		}
 
-		// 3) 포지션 사이즈 리스크 체크
-		newPosValue := float64(qty) * price
-		if err := s.deps.Risk.CheckPositionSize(ctx, equity, newPosValue); err != nil {
-			logger.Error.Printf("[stable] position risk check failed for %s: %v\n", code, err)
-			continue
+		for _, h := range holdings {
+			budget, ok := budgets[h.Code]
+			if !ok {
+				continue
+			}
+			left := budget - spent[h.Code]
+			carry[h.Code] = left
+			if s.deps.Carry != nil {
+				if err := s.deps.Carry.SetCarry(ctx, "stable", h.Code, left); err != nil {
+					logger.Error.Printf("[stable] failed to save carry-over for %s: %v\n", h.Code, err)
+				}
+			}
+
+			if qty := bought[h.Code]; qty > 0 {
+				logger.Info.Printf("[stable] DCA buy %s x %d @ %.2f (alloc %.0f + carry %.0f + dividends %.0f, weight %.1f%% / target %.1f%%)\n",
+					h.Code, qty, h.Price, alloc[h.Code], budget-alloc[h.Code]-reinvest[h.Code], reinvest[h.Code], h.weightAfter(holdings, bought)*100, h.Target*100)
+			} else {
+				logger.Info.Printf("[stable] DCA %s: %.0f carried over (price %.2f)\n", h.Code, left, h.Price)
+			}
 		}
+		s.reportCarry(carry)
 
-		// 4) 매수 주문 (stub)
-		if err := s.deps.KIS.Buy(ctx, code, qty); err != nil {
-			logger.Error.Printf("[stable] buy failed for %s: %v\n", code, err)
-			continue
+		// 쓰고 남은 배당은 이월 금액에 들어갔으므로 다시 더하지 않도록 표시한다.
+		if len(divIDs) > 0 {
+			if err := s.deps.Dividends.MarkReinvested(ctx, divIDs); err != nil {
+				logger.Error.Printf("[stable] failed to mark dividends reinvested: %v\n", err)
+			}
 		}
+	}
+	return plan, nil
+}
 
-		// 5) 트레이드 기록
-		trade := &models.Trade{
-			Code:     code,
-			Side:     "BUY",
-			Quantity: qty,
-			Price:    price,
-			Time:     time.Now(),
-			Strategy: "stable",
-		}
-		if err := s.deps.Repo.InsertTrade(ctx, trade); err != nil {
-			logger.Error.Printf("[stable] failed to insert trade for %s: %v\n", code, err)
-			continue
+// pendingDividends 재투자할 배당을 ETF 별로 나눈다. 이월 저장소가 없으면 남은 금액을 잃으므로 쓰지 않는다.
+func (s *StableStrategy) pendingDividends(ctx context.Context, holdings []*etfHolding) (map[string]float64, []int64) {
+	if !s.deps.Stable.ReinvestDividends || s.deps.Dividends == nil || s.deps.Carry == nil {
+		return nil, nil
+	}
+	amount, ids, err := s.deps.Dividends.PendingReinvestment(ctx, "stable")
+	if err != nil {
+		logger.Error.Printf("[stable] failed to load pending dividends: %v\n", err)
+		return nil, nil
+	}
+	if amount <= 0 {
+		return nil, nil
+	}
+	logger.Info.Printf("[stable] reinvesting %.0f KRW of dividends (%d payments)\n", amount, len(ids))
+	return allocateDCA(holdings, amount), ids
+}
+
+// reportCarry 일일 리포트: 적립됐지만 아직 투자되지 않은 ETF 별 금액
+func (s *StableStrategy) reportCarry(carry map[string]float64) {
+	codes := make([]string, 0, len(carry))
+	for code := range carry {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var total float64
+	for _, code := range codes {
+		total += carry[code]
+		logger.Info.Printf("[stable] pending %s: %.0f KRW\n", code, carry[code])
+	}
+	logger.Info.Printf("[stable] pending total: %.0f KRW\n", total)
+}
+
+// allocateDCA 하루 적립 금액을 매수 후 기준 목표 비중 대비 부족분에 비례해 나눈다.
+// 부족분을 다 채우고도 남으면 나머지는 목표 비중대로 나눈다. 종목코드 → 금액.
+func allocateDCA(holdings []*etfHolding, amount float64) map[string]float64 {
+	total := amount
+	for _, h := range holdings {
+		total += h.Value
+	}
+
+	gaps := map[string]float64{}
+	var gapSum float64
+	for _, h := range holdings {
+		if gap := h.Target*total - h.Value; gap > 0 {
+			gaps[h.Code] = gap
+			gapSum += gap
 		}
+	}
 
-		logger.Info.Printf("[stable] DCA buy %s x %d @ %.2f\n", code, qty, price)
+	out := map[string]float64{}
+	if gapSum >= amount {
+		for code, gap := range gaps {
+			out[code] = amount * gap / gapSum
+		}
+		return out
 	}
+	rest := amount - gapSum
+	for _, h := range holdings {
+		out[h.Code] = gaps[h.Code] + rest*h.Target
+	}
+	return out
+}
 
-	logger.Info.Println("[stable] DCA ETF strategy completed")
-	return nil
+func dcaMode(m string) string {
+	if m == "" {
+		return DCAFixed
+	}
+	return m
 }
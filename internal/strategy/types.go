@@ -2,12 +2,15 @@ package strategy
 
 import "context"
 
+// Mode 기본 제공 전략 이름. 다른 전략은 Register 로 임의의 이름을 쓸 수 있다.
 type Mode string
 
 const (
 	ModeHybrid     Mode = "hybrid"
 	ModeStable     Mode = "stable"
 	ModeAggressive Mode = "aggressive"
+	ModeMeanRev    Mode = "mean_reversion"
+	ModeRotation   Mode = "momentum_rotation"
 )
 
 // Runner 모든 전략의 공통 인터페이스
@@ -2,43 +2,154 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
+	"stock-investing/internal/models"
+	"stock-investing/internal/strategy"
 	"stock-investing/pkg/logger"
 )
 
-// 매우 단순한 틱 기반 스케줄러 (실제 cron 대체는 이후 단계에서 개선)
+// DefaultGrace 예정 시각 이후 실행을 허용하는 기본 시간
+const DefaultGrace = 20 * time.Minute
+
+// 매분 확인하는 단순한 일일 스케줄러 (KST 기준 HH:MM)
 type Scheduler struct {
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	// grace 예정 시각부터 이 시간 안에만 실행한다. 늦게 시작했거나 멈췄던 데몬이 장 마감 후
+	// 시장가 주문을 내지 않도록, 창이 지난 작업은 그날 건너뛴다.
+	grace    time.Duration
+	holidays map[string]bool // 휴장일 (KST YYYY-MM-DD)
+
+	mu   sync.Mutex
+	jobs []*job
+	wg   sync.WaitGroup
+}
+
+type job struct {
+	name    string
+	hour    int
+	minute  int
+	run     func(ctx context.Context) error
+	lastDay string // 마지막 실행일 (같은 날 중복 실행 방지)
+	running bool
 }
 
 func New() *Scheduler {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Scheduler{
-		ctx:    ctx,
-		cancel: cancel,
+		ctx:      ctx,
+		cancel:   cancel,
+		grace:    DefaultGrace,
+		holidays: map[string]bool{},
+	}
+}
+
+// WithGrace 작업별 실행 허용 시간 (예정 시각 ~ 예정 시각 + d)
+func (s *Scheduler) WithGrace(d time.Duration) *Scheduler {
+	if d > 0 {
+		s.grace = d
 	}
+	return s
+}
+
+// WithHolidays 주말 외에 작업을 돌리지 않을 날짜 (KST "2006-01-02")
+func (s *Scheduler) WithHolidays(days ...string) *Scheduler {
+	for _, d := range days {
+		s.holidays[d] = true
+	}
+	return s
+}
+
+// AddDaily 매일 at(KST "HH:MM") 부터 grace 안에 run 을 실행한다. 주말/휴장일은 건너뛴다.
+func (s *Scheduler) AddDaily(name, at string, run func(ctx context.Context) error) error {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return fmt.Errorf("job %s: invalid time %q (want HH:MM)", name, at)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &job{name: name, hour: t.Hour(), minute: t.Minute(), run: run})
+	logger.Info.Printf("scheduler: %s scheduled daily at %s KST\n", name, at)
+	return nil
+}
+
+// AddStrategy 등록된 전략을 이름으로 찾아 매일 at 에 실행한다.
+func (s *Scheduler) AddStrategy(name, at string, deps strategy.Deps) error {
+	runner, err := strategy.New(name, deps)
+	if err != nil {
+		return err
+	}
+	return s.AddDaily(name, at, runner.Run)
 }
 
 func (s *Scheduler) Start() {
-	logger.Info.Println("scheduler started (stub)")
-	// TODO: 이후 cron 기반으로 시간별 작업 연결
+	logger.Info.Println("scheduler started")
 	go func() {
-		ticker := time.NewTicker(1 * time.Hour)
+		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
 		for {
 			select {
 			case <-s.ctx.Done():
 				logger.Info.Println("scheduler stopped")
 				return
-			case <-ticker.C:
-				logger.Info.Println("scheduler tick (stub)")
+			case now := <-ticker.C:
+				s.tick(now)
 			}
 		}
 	}()
 }
 
+func (s *Scheduler) tick(now time.Time) {
+	now = now.In(models.KST)
+	if now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
+		return
+	}
+	today := now.Format("2006-01-02")
+	if s.holidays[today] {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.running || j.lastDay == today {
+			continue
+		}
+		due := time.Date(now.Year(), now.Month(), now.Day(), j.hour, j.minute, 0, 0, models.KST)
+		if now.Before(due) {
+			continue
+		}
+		j.lastDay = today
+		if now.Sub(due) > s.grace {
+			logger.Info.Printf("scheduler: skipping %s today, missed %s window by %s\n",
+				j.name, due.Format("15:04"), (now.Sub(due) - s.grace).Truncate(time.Minute))
+			continue
+		}
+		j.running = true
+		s.wg.Add(1)
+		go s.runJob(j)
+	}
+}
+
+func (s *Scheduler) runJob(j *job) {
+	defer s.wg.Done()
+	logger.Info.Printf("scheduler: running %s\n", j.name)
+	if err := j.run(s.ctx); err != nil {
+		logger.Error.Printf("scheduler: %s failed: %v\n", j.name, err)
+	} else {
+		logger.Info.Printf("scheduler: %s done\n", j.name)
+	}
+	s.mu.Lock()
+	j.running = false
+	s.mu.Unlock()
+}
+
+// Stop 실행 중인 작업이 끝날 때까지 기다린다.
 func (s *Scheduler) Stop() {
 	s.cancel()
+	s.wg.Wait()
 }